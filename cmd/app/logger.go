@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"github.com/ilam072/event-calendar/internal/config"
+	"github.com/ilam072/event-calendar/internal/metrics"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"github.com/rs/zerolog"
+	"os"
+)
+
+const (
+	logBackendAsync   = "async"
+	logBackendZerolog = "zerolog"
+)
+
+// newLogger returns the logger shared by the handlers, services, middlewares
+// and workers, and a function that flushes it on shutdown. The zerolog backend
+// writes to stdout.
+func newLogger(cfg config.LoggerConfig) (logger.Logger, func(), error) {
+	level, err := logger.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch cfg.Backend {
+	case logBackendAsync, "":
+		asyncLog, err := logger.NewAsyncLogger(logger.Config{
+			Output:       cfg.Output,
+			File:         cfg.File,
+			Level:        level,
+			Buffer:       cfg.Buffer,
+			Block:        cfg.Block,
+			MaxSize:      cfg.MaxSizeMB << 20,
+			MaxAge:       cfg.MaxAge,
+			MaxBackups:   cfg.MaxBackups,
+			MaxBackupAge: cfg.MaxBackupAge,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		asyncLog.Start()
+		metrics.RegisterLogDrops(asyncLog.Dropped)
+
+		return asyncLog, asyncLog.Stop, nil
+	case logBackendZerolog:
+		zl := zerolog.New(os.Stdout).With().Timestamp().Logger().Level(logger.ZerologLevel(level))
+
+		return logger.NewZerologLogger(zl), func() {}, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown log backend %q", cfg.Backend)
+	}
+}
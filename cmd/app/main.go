@@ -2,23 +2,43 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	apikeyrepo "github.com/ilam072/event-calendar/internal/apikey/repo"
+	apikeyrest "github.com/ilam072/event-calendar/internal/apikey/rest"
+	apikeyservice "github.com/ilam072/event-calendar/internal/apikey/service"
+	calendarrepo "github.com/ilam072/event-calendar/internal/calendar/repo"
+	calendarrest "github.com/ilam072/event-calendar/internal/calendar/rest"
+	calendarservice "github.com/ilam072/event-calendar/internal/calendar/service"
 	"github.com/ilam072/event-calendar/internal/config"
 	eventrepo "github.com/ilam072/event-calendar/internal/event/repo"
 	eventrest "github.com/ilam072/event-calendar/internal/event/rest"
 	eventservice "github.com/ilam072/event-calendar/internal/event/service"
 	"github.com/ilam072/event-calendar/internal/event/worker/janitor"
 	"github.com/ilam072/event-calendar/internal/event/worker/reminder"
+	exportrest "github.com/ilam072/event-calendar/internal/export/rest"
+	exportservice "github.com/ilam072/event-calendar/internal/export/service"
+	healthrest "github.com/ilam072/event-calendar/internal/health/rest"
+	idempotencyrepo "github.com/ilam072/event-calendar/internal/idempotency/repo"
+	jwksrest "github.com/ilam072/event-calendar/internal/jwks/rest"
+	"github.com/ilam072/event-calendar/internal/metrics"
 	"github.com/ilam072/event-calendar/internal/router"
+	schedulingrest "github.com/ilam072/event-calendar/internal/scheduling/rest"
+	schedulingservice "github.com/ilam072/event-calendar/internal/scheduling/service"
 	userrepo "github.com/ilam072/event-calendar/internal/user/repo"
 	userrest "github.com/ilam072/event-calendar/internal/user/rest"
 	userservice "github.com/ilam072/event-calendar/internal/user/service"
 	"github.com/ilam072/event-calendar/internal/validator"
+	"github.com/ilam072/event-calendar/migrations"
 	"github.com/ilam072/event-calendar/pkg/db"
 	"github.com/ilam072/event-calendar/pkg/email"
 	"github.com/ilam072/event-calendar/pkg/jwt"
-	"github.com/ilam072/event-calendar/pkg/logger"
-	"github.com/rs/zerolog/log"
+	"github.com/ilam072/event-calendar/pkg/migrate"
+	"github.com/ilam072/event-calendar/pkg/oidc"
+	"github.com/ilam072/event-calendar/pkg/tracing"
+	"github.com/prometheus/client_golang/prometheus"
 	"net/http"
+	"os"
 	"os/signal"
 	"syscall"
 	"time"
@@ -29,54 +49,162 @@ func main() {
 	cfg := config.MustLoad()
 
 	// Initialize logger
-	asyncLog, err := logger.NewAsyncLogger(cfg.Logger.File, 1000)
+	appLog, stopLog, err := newLogger(cfg.Logger)
 	if err != nil {
-		log.Logger.Fatal().Err(err).Msg("failed to initialize async logger")
+		fmt.Fprintf(os.Stderr, "failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer stopLog()
+
+	// fatal logs err and exits; os.Exit skips the deferred calls, so the
+	// logger is flushed here.
+	fatal := func(err error, msg string) {
+		appLog.Error().Err(err).Msg(msg)
+		stopLog()
+		os.Exit(1)
 	}
-	asyncLog.Start()
-	defer asyncLog.Stop()
 
 	// Context
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
 	defer cancel()
 
+	// Initialize tracing
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		ServiceName: cfg.Trace.ServiceName,
+		Exporter:    cfg.Trace.Exporter,
+		Protocol:    cfg.Trace.Protocol,
+		Endpoint:    cfg.Trace.Endpoint,
+		SampleRatio: cfg.Trace.SampleRatio,
+	})
+	if err != nil {
+		fatal(err, "failed to initialize tracing")
+	}
+
 	// Connect to DB
 	DB, err := db.OpenDB(ctx, cfg.DB)
 	if err != nil {
-		log.Logger.Fatal().Err(err).Msg("failed to connect to DB")
+		fatal(err, "failed to connect to DB")
+	}
+
+	// Apply migrations
+	migrator, err := migrate.New(DB, migrations.FS)
+	if err != nil {
+		fatal(err, "failed to load migrations")
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err = runMigrate(ctx, migrator, os.Args[2:]); err != nil {
+			fatal(err, "failed to migrate DB")
+		}
+		DB.Close()
+		return
+	}
+
+	if cfg.DB.AutoMigrate {
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			fatal(err, "failed to migrate DB")
+		}
+		appLog.Info().Any("applied", len(applied)).Msg("DB migrated")
+	}
+
+	prometheus.MustRegister(metrics.NewPoolCollector(DB))
+
 	// Initialize token manager
 	manager := jwt.NewManager([]byte(cfg.JWT.Secret))
+	if cfg.JWT.KeysDir != "" {
+		keys, err := jwt.LoadKeys(cfg.JWT.KeysDir)
+		if err != nil {
+			fatal(err, "failed to load jwt keys")
+		}
+		var legacySecret []byte
+		if cfg.JWT.AcceptHS256 {
+			legacySecret = []byte(cfg.JWT.Secret)
+		}
+		manager, err = jwt.NewManagerWithKeys(legacySecret, keys, cfg.JWT.ActiveKID)
+		if err != nil {
+			fatal(err, "failed to initialize token manager")
+		}
+	}
 
 	// Initialize validator
 	v := validator.New()
 
 	// Initialize email client
 	emailClient := email.New(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.From)
+	mailer := metrics.InstrumentSender(emailClient)
 
-	// Initialize user and event repositories
+	// Initialize repositories
 	userRepo := userrepo.NewUserRepo(DB)
 	eventRepo := eventrepo.NewEventRepo(DB)
+	calendarRepo := calendarrepo.NewCalendarRepo(DB)
+	apiKeyRepo := apikeyrepo.NewAPIKeyRepo(DB)
+	loginThrottleRepo := userrepo.NewLoginThrottleRepo(DB)
+	idempotencyRepo := idempotencyrepo.NewIdempotencyRepo(DB)
 
 	// Initialize reminder worker
-	reminderWorker := reminder.NewWorker(eventRepo, userRepo, emailClient, 100)
+	reminderWorker := reminder.NewWorker(eventRepo, userRepo, mailer, 100, appLog)
 	go reminderWorker.Run(ctx)
 
 	// Initialize janitor worker
-	janitorWorker := janitor.NewWorker(eventRepo)
+	janitorWorker := janitor.NewWorker(eventRepo, idempotencyRepo, loginThrottleRepo, userRepo, cfg.Trash.Retention, appLog)
 	go janitorWorker.Start()
 
-	// Initialize user and event services
-	user := userservice.NewUser(userRepo, manager, cfg.JWT.TokenTTL)
+	// Initialize services
+	guard := userservice.NewGuard(loginThrottleRepo, mailer, appLog)
+	user := userservice.NewUser(userRepo, manager, guard, mailer, cfg.JWT.TokenTTL, cfg.MFA.TokenTTL, cfg.MFA.TOTPIssuer, cfg.Server.PublicURL, appLog)
 	event := eventservice.NewEvent(eventRepo, reminderWorker.TasksChan())
+	batch := eventservice.NewBatch(event, db.NewTransactor(DB))
+	trash := eventservice.NewTrash(eventRepo, reminderWorker.TasksChan())
+	attendee := eventservice.NewAttendee(eventRepo, mailer, manager, cfg.Server.PublicURL, appLog)
+	calendar := calendarservice.NewCalendar(calendarRepo, eventRepo, db.NewTransactor(DB))
+	share := calendarservice.NewShare(calendarRepo, mailer, appLog)
+	scheduling := schedulingservice.NewScheduling(eventRepo, userRepo)
+	export := exportservice.NewExport(userRepo, eventRepo, calendarRepo, apiKeyRepo, loginThrottleRepo)
+	apiKey := apikeyservice.NewAPIKey(apiKeyRepo)
+
+	// Initialize handlers
+	userHandler := userrest.NewUserHandler(user, v, appLog)
+	eventHandler := eventrest.NewEventHandler(event, v, appLog)
+	attendeeHandler := eventrest.NewAttendeeHandler(attendee, v, appLog)
+	batchHandler := eventrest.NewBatchHandler(batch, v, appLog)
+	trashHandler := eventrest.NewTrashHandler(trash, appLog)
+	calendarHandler := calendarrest.NewCalendarHandler(calendar, v, appLog)
+	shareHandler := calendarrest.NewShareHandler(share, v, appLog)
+	schedulingHandler := schedulingrest.NewSchedulingHandler(scheduling, v, appLog)
+	exportHandler := exportrest.NewExportHandler(export, appLog)
+	apiKeyHandler := apikeyrest.NewAPIKeyHandler(apiKey, v, appLog)
+	jwksHandler := jwksrest.NewJWKSHandler(manager)
 
-	// Initialize user and event handlers
-	userHandler := userrest.NewUserHandler(user, v, asyncLog)
-	eventHandler := eventrest.NewEventHandler(event, v, asyncLog)
+	checks := []healthrest.Check{
+		{Name: "postgres", Critical: true, Check: DB.Ping},
+		{Name: "reminder_worker", Critical: true, Check: healthrest.Running(reminderWorker.Running)},
+		{Name: "janitor_worker", Critical: true, Check: healthrest.Running(janitorWorker.Running)},
+	}
+	if cfg.Health.CheckSMTP {
+		checks = append(checks, healthrest.Check{Name: "smtp", Check: healthrest.Cached(cfg.Health.SMTPCheckTTL, emailClient.Ping)})
+	}
+	healthHandler := healthrest.NewHealthHandler(checks...)
+
+	var oidcHandler *userrest.OIDCHandler
+	if cfg.OIDC.IssuerURL != "" {
+		oidcClient, err := oidc.New(ctx, oidc.Config{
+			IssuerURL:    cfg.OIDC.IssuerURL,
+			ClientID:     cfg.OIDC.ClientID,
+			ClientSecret: cfg.OIDC.ClientSecret,
+			RedirectURL:  cfg.OIDC.RedirectURL,
+		})
+		if err != nil {
+			fatal(err, "failed to initialize oidc client")
+		}
+		oidcHandler = userrest.NewOIDCHandler(user, oidcClient, appLog)
+	}
 
 	// Initialize Gin engine and set routes
-	engine := router.New(userHandler, eventHandler, manager)
+	engine := router.New(userHandler, oidcHandler, eventHandler, attendeeHandler, batchHandler, trashHandler, calendarHandler, shareHandler, schedulingHandler, exportHandler, apiKeyHandler, jwksHandler, healthHandler, manager, apiKey, userRepo, idempotencyRepo, cfg.Server.IdempotencyTTL, appLog)
+	if err = engine.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		fatal(err, "failed to set trusted proxies")
+	}
 
 	// Initialize and start http server
 	server := &http.Server{
@@ -84,20 +212,32 @@ func main() {
 		Handler: engine,
 	}
 
+	serveErr := make(chan error, 1)
 	go func() {
-		if err = server.ListenAndServe(); err != nil {
-			log.Logger.Fatal().Err(err).Msg("failed to listen start http server")
+		// ErrServerClosed only means Shutdown was called.
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
 		}
 	}()
 
-	<-ctx.Done()
+	failed := false
+	select {
+	case <-ctx.Done():
+		// Graceful shutdown
+		healthHandler.Shutdown()
+
+		appLog.Info().Str("delay", cfg.Server.ShutdownDelay.String()).Msg("draining before shutdown")
+		time.Sleep(cfg.Server.ShutdownDelay)
+	case err = <-serveErr:
+		appLog.Error().Err(err).Msg("failed to start http server")
+		failed = true
+	}
 
-	// Graceful shutdown
 	withTimeout, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
 	if err = server.Shutdown(withTimeout); err != nil {
-		log.Logger.Error().Err(err).Msg("server shutdown failed")
+		appLog.Error().Err(err).Msg("server shutdown failed")
 	}
 
 	DB.Close()
@@ -105,4 +245,14 @@ func main() {
 	janitorWorker.Stop()
 
 	reminderWorker.Stop()
+
+	if err = shutdownTracing(withTimeout); err != nil {
+		appLog.Error().Err(err).Msg("failed to flush traces")
+	}
+
+	if failed {
+		// os.Exit skips the deferred calls
+		stopLog()
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/google/uuid"
+	apikeyrepo "github.com/ilam072/event-calendar/internal/apikey/repo"
+	calendarrepo "github.com/ilam072/event-calendar/internal/calendar/repo"
+	exportservice "github.com/ilam072/event-calendar/internal/export/service"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	userrepo "github.com/ilam072/event-calendar/internal/user/repo"
+	"github.com/ilam072/event-calendar/pkg/db"
+	"io"
+	"os"
+	"time"
+)
+
+// exportDataFile is the file of the export archive that import reads.
+const exportDataFile = "export.json"
+
+// exportEvents writes the same archive as GET /api/v1/me/export.
+func exportEvents(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("events export", flag.ContinueOnError)
+	ref := fs.String("user", "", "email or id of the user")
+	out := fs.String("out", "", "file to write the zip archive to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *out == "" {
+		return errors.New("-out is required")
+	}
+
+	user, err := findUser(ctx, a, *ref)
+	if err != nil {
+		return err
+	}
+
+	export := exportservice.NewExport(a.users, a.events, calendarrepo.NewCalendarRepo(a.pool), apikeyrepo.NewAPIKeyRepo(a.pool), userrepo.NewLoginThrottleRepo(a.pool))
+	data, err := export.ExportUserData(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(*out, data, 0o600); err != nil {
+		return err
+	}
+
+	fmt.Printf("exported events of %s to %s\n", user.Email, *out)
+
+	return nil
+}
+
+// importEvents creates the active events of an export in the default calendar
+// of the user, who need not be the one the export was made for. Either all
+// events are imported or none. Reminders are kept only if they are still due.
+func importEvents(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("events import", flag.ContinueOnError)
+	ref := fs.String("user", "", "email or id of the user")
+	in := fs.String("in", "", "zip archive or export.json to import")
+	dryRun := fs.Bool("dry-run", false, "only count the events that would be imported")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" {
+		return errors.New("-in is required")
+	}
+
+	user, err := findUser(ctx, a, *ref)
+	if err != nil {
+		return err
+	}
+
+	export, err := readExport(*in)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		fmt.Printf("%d events would be imported for %s\n", len(export.Events), user.Email)
+		return nil
+	}
+
+	sent := make(map[uuid.UUID]bool, len(export.Reminders))
+	for _, r := range export.Reminders {
+		sent[r.EventID] = r.Sent
+	}
+
+	now := time.Now()
+	err = db.NewTransactor(a.pool).WithinTx(ctx, func(ctx context.Context) error {
+		for _, e := range export.Events {
+			remindAt := e.RemindAt
+			if remindAt != nil && (sent[e.ID] || remindAt.Before(now)) {
+				remindAt = nil
+			}
+
+			_, err := a.events.CreateEvent(ctx, domain.Event{
+				UserID:      user.ID,
+				Date:        e.Date,
+				StartsAt:    e.StartsAt,
+				EndsAt:      e.EndsAt,
+				Description: e.Description,
+				RemindAt:    remindAt,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to import event %s: %w", e.ID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("imported %d events for %s\n", len(export.Events), user.Email)
+	fmt.Println("imported reminders are scheduled when the server starts next")
+
+	return nil
+}
+
+// readExport reads the JSON dump of an export, either from the zip archive
+// or as a file on its own.
+func readExport(path string) (dto.UserExport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return dto.UserExport{}, err
+	}
+
+	if zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data))); err == nil {
+		f, err := zr.Open(exportDataFile)
+		if err != nil {
+			return dto.UserExport{}, fmt.Errorf("archive has no %s: %w", exportDataFile, err)
+		}
+		defer f.Close()
+
+		if data, err = io.ReadAll(f); err != nil {
+			return dto.UserExport{}, err
+		}
+	}
+
+	var export dto.UserExport
+	if err = json.Unmarshal(data, &export); err != nil {
+		return dto.UserExport{}, fmt.Errorf("invalid export: %w", err)
+	}
+
+	return export, nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+)
+
+// archiveEvents runs the archive job of the janitor worker.
+func archiveEvents(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("janitor archive", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "only count the events that would be archived")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *dryRun {
+		count, err := a.events.CountArchivableEvents(ctx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%d events would be archived\n", count)
+		return nil
+	}
+
+	archived, err := a.events.ArchiveOldEvents(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("archived %d events\n", archived)
+
+	return nil
+}
@@ -0,0 +1,92 @@
+// Command calendarctl runs administrative tasks against the database of the
+// event calendar, using the same repositories as the server.
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/ilam072/event-calendar/internal/config"
+	eventrepo "github.com/ilam072/event-calendar/internal/event/repo"
+	userrepo "github.com/ilam072/event-calendar/internal/user/repo"
+	"github.com/ilam072/event-calendar/pkg/db"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const usage = `usage: calendarctl <command> [flags]
+
+commands:
+  user create -email EMAIL [-password PASSWORD] [-verified]
+  user disable -user EMAIL|ID
+  user enable -user EMAIL|ID
+  user reset-password -user EMAIL|ID [-password PASSWORD]
+  reminders pending
+  reminders overdue [-grace DURATION]
+  reminders resend -event ID [-force]
+  janitor archive [-dry-run]
+  events export -user EMAIL|ID -out FILE
+  events import -user EMAIL|ID -in FILE [-dry-run]
+
+Passwords are generated and printed when not given.`
+
+type app struct {
+	pool   *pgxpool.Pool
+	users  *userrepo.UserRepo
+	events *eventrepo.EventRepo
+	smtp   config.SMTPConfig
+}
+
+type command func(ctx context.Context, a *app, args []string) error
+
+var commands = map[string]command{
+	"user create":         createUser,
+	"user disable":        disableUser,
+	"user enable":         enableUser,
+	"user reset-password": resetPassword,
+	"reminders pending":   pendingReminders,
+	"reminders overdue":   overdueReminders,
+	"reminders resend":    resendReminder,
+	"janitor archive":     archiveEvents,
+	"events export":       exportEvents,
+	"events import":       importEvents,
+}
+
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]+" "+os.Args[2]]
+	if !ok {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg := config.MustLoad()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+
+	pool, err := db.OpenDB(ctx, cfg.DB)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to DB: %v\n", err)
+		os.Exit(1)
+	}
+	defer pool.Close()
+
+	a := &app{
+		pool:   pool,
+		users:  userrepo.NewUserRepo(pool),
+		events: eventrepo.NewEventRepo(pool),
+		smtp:   cfg.SMTP,
+	}
+
+	if err = cmd(ctx, a, os.Args[3:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %s: %v\n", os.Args[1], os.Args[2], err)
+		pool.Close()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/event/worker/reminder"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/email"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+func pendingReminders(ctx context.Context, a *app, args []string) error {
+	if err := flag.NewFlagSet("reminders pending", flag.ContinueOnError).Parse(args); err != nil {
+		return err
+	}
+
+	events, err := a.events.GetPendingReminders(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return printReminders(events)
+}
+
+// overdueReminders lists the reminders that are unsent longer than grace
+// after they were due: sending failed, the server was down when they were
+// due, or the worker is far behind.
+func overdueReminders(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("reminders overdue", flag.ContinueOnError)
+	grace := fs.Duration("grace", 5*time.Minute, "how long after they are due reminders may still be on their way")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	events, err := a.events.GetOverdueReminders(ctx, time.Now().Add(-*grace))
+	if err != nil {
+		return err
+	}
+
+	return printReminders(events)
+}
+
+func resendReminder(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("reminders resend", flag.ContinueOnError)
+	ref := fs.String("event", "", "id of the event")
+	force := fs.Bool("force", false, "send the reminder even if it has already been sent")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	eventID, err := uuid.Parse(*ref)
+	if err != nil {
+		return errors.New("-event must be an event id")
+	}
+
+	sender := email.New(a.smtp.Host, a.smtp.Port, a.smtp.Username, a.smtp.Password, a.smtp.From)
+	worker := reminder.NewWorker(a.events, a.users, sender, 0, &logger.DummyLogger{})
+
+	if err = worker.Resend(ctx, eventID, *force); err != nil {
+		return err
+	}
+
+	fmt.Printf("sent reminder of event %s\n", eventID)
+
+	return nil
+}
+
+func printReminders(events []domain.Event) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "EVENT\tUSER\tREMIND AT\tDESCRIPTION")
+	for _, e := range events {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.ID, e.UserID, e.RemindAt.Format(time.RFC3339), e.Description)
+	}
+	return w.Flush()
+}
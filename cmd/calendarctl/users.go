@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	passwordSize      = 12
+	minPasswordLength = 6
+)
+
+func createUser(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("user create", flag.ContinueOnError)
+	email := fs.String("email", "", "email of the new user")
+	password := fs.String("password", "", "password, generated when empty")
+	verified := fs.Bool("verified", false, "treat the email as verified and link pending invitations")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return errors.New("-email is required")
+	}
+
+	pass, generated, err := passwordOrRandom(*password)
+	if err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	id, err := a.users.CreateUser(ctx, domain.User{Email: *email, PasswordHash: string(hash)})
+	if err != nil {
+		return err
+	}
+
+	if *verified {
+		if err = a.users.MarkEmailVerified(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("created user %s (%s)\n", *email, id)
+	if generated {
+		fmt.Printf("password: %s\n", pass)
+	}
+
+	return nil
+}
+
+func disableUser(ctx context.Context, a *app, args []string) error {
+	return setDisabled(ctx, a, "user disable", args, true)
+}
+
+func enableUser(ctx context.Context, a *app, args []string) error {
+	return setDisabled(ctx, a, "user enable", args, false)
+}
+
+func setDisabled(ctx context.Context, a *app, name string, args []string, disabled bool) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	ref := fs.String("user", "", "email or id of the user")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	user, err := findUser(ctx, a, *ref)
+	if err != nil {
+		return err
+	}
+
+	if err = a.users.SetDisabled(ctx, user.ID, disabled); err != nil {
+		return err
+	}
+
+	if disabled {
+		fmt.Printf("disabled user %s, their access tokens and api keys are rejected\n", user.Email)
+	} else {
+		fmt.Printf("enabled user %s\n", user.Email)
+	}
+
+	return nil
+}
+
+func resetPassword(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("user reset-password", flag.ContinueOnError)
+	ref := fs.String("user", "", "email or id of the user")
+	password := fs.String("password", "", "new password, generated when empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	user, err := findUser(ctx, a, *ref)
+	if err != nil {
+		return err
+	}
+
+	pass, generated, err := passwordOrRandom(*password)
+	if err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	if err = a.users.SetPasswordHash(ctx, user.ID, string(hash)); err != nil {
+		return err
+	}
+
+	fmt.Printf("reset password of %s\n", user.Email)
+	if generated {
+		fmt.Printf("password: %s\n", pass)
+	}
+
+	return nil
+}
+
+// findUser looks the user up by id when ref is a UUID and by email otherwise.
+func findUser(ctx context.Context, a *app, ref string) (domain.User, error) {
+	if ref == "" {
+		return domain.User{}, errors.New("-user is required")
+	}
+
+	if id, err := uuid.Parse(ref); err == nil {
+		return a.users.GetUserByID(ctx, id)
+	}
+
+	return a.users.GetUserByEmail(ctx, ref)
+}
+
+func passwordOrRandom(password string) (string, bool, error) {
+	if password != "" {
+		if len(password) < minPasswordLength {
+			return "", false, fmt.Errorf("password must be at least %d characters", minPasswordLength)
+		}
+		return password, false, nil
+	}
+
+	b := make([]byte, passwordSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", false, err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), true, nil
+}
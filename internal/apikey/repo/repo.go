@@ -0,0 +1,127 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
+
+type APIKeyRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAPIKeyRepo(db *pgxpool.Pool) *APIKeyRepo {
+	return &APIKeyRepo{db: db}
+}
+
+func (r *APIKeyRepo) CreateAPIKey(ctx context.Context, key domain.APIKey) (domain.APIKey, error) {
+	query := `
+		INSERT INTO api_keys (user_id, name, prefix, secret_hash, scope, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at;
+	`
+
+	err := r.db.QueryRow(ctx, query, key.UserID, key.Name, key.Prefix, key.SecretHash, key.Scope, key.ExpiresAt).
+		Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return domain.APIKey{}, errutils.Wrap("failed to create api key", err)
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRepo) GetAPIKeysByUser(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error) {
+	query := `
+		SELECT id, user_id, name, prefix, secret_hash, scope, expires_at, last_used_at, created_at
+		FROM api_keys
+		WHERE user_id = $1
+		ORDER BY created_at;
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get api keys", err)
+	}
+	defer rows.Close()
+
+	var keys []domain.APIKey
+	for rows.Next() {
+		var key domain.APIKey
+		if err := rows.Scan(
+			&key.ID,
+			&key.UserID,
+			&key.Name,
+			&key.Prefix,
+			&key.SecretHash,
+			&key.Scope,
+			&key.ExpiresAt,
+			&key.LastUsedAt,
+			&key.CreatedAt,
+		); err != nil {
+			return nil, errutils.Wrap("failed to scan", err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeyRepo) GetAPIKeyByPrefix(ctx context.Context, prefix string) (domain.APIKey, error) {
+	query := `
+		SELECT id, user_id, name, prefix, secret_hash, scope, expires_at, last_used_at, created_at
+		FROM api_keys
+		WHERE prefix = $1 AND user_id IN (SELECT id FROM users WHERE disabled_at IS NULL);
+	`
+
+	var key domain.APIKey
+	err := r.db.QueryRow(ctx, query, prefix).Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		&key.SecretHash,
+		&key.Scope,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.APIKey{}, errutils.Wrap("failed to get api key", ErrAPIKeyNotFound)
+		}
+		return domain.APIKey{}, errutils.Wrap("failed to get api key", err)
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRepo) TouchAPIKey(ctx context.Context, keyID uuid.UUID) error {
+	query := `UPDATE api_keys SET last_used_at = now() WHERE id = $1;`
+	if _, err := r.db.Exec(ctx, query, keyID); err != nil {
+		return errutils.Wrap("failed to update api key last usage", err)
+	}
+	return nil
+}
+
+func (r *APIKeyRepo) DeleteAPIKey(ctx context.Context, keyID uuid.UUID, userID uuid.UUID) error {
+	query := `DELETE FROM api_keys WHERE id = $1 AND user_id = $2;`
+
+	res, err := r.db.Exec(ctx, query, keyID, userID)
+	if err != nil {
+		return errutils.Wrap("failed to delete api key", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/response"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"net/http"
+)
+
+//go:generate mockgen -source=rest.go -destination=../mocks/rest_mocks.go -package=mocks
+type APIKey interface {
+	CreateAPIKey(ctx context.Context, req dto.CreateAPIKeyRequest, userID uuid.UUID) (dto.CreateAPIKeyResponse, error)
+	GetAPIKeys(ctx context.Context, userID uuid.UUID) (dto.GetAPIKeysResponse, error)
+	DeleteAPIKey(ctx context.Context, keyID uuid.UUID, userID uuid.UUID) error
+}
+
+type Validator interface {
+	Validate(i interface{}) error
+}
+
+type APIKeyHandler struct {
+	apiKey    APIKey
+	validator Validator
+	logger    logger.Logger
+}
+
+func NewAPIKeyHandler(apiKey APIKey, validator Validator, logger logger.Logger) *APIKeyHandler {
+	return &APIKeyHandler{apiKey: apiKey, validator: validator, logger: logger}
+}
+
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req dto.CreateAPIKeyRequest
+	if err := c.BindJSON(&req); err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to bind create api key json")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	key, err := h.apiKey.CreateAPIKey(c.Request.Context(), req, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidExpiration) {
+			response.BadRequest(c, "expires_at must be in the future")
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("user_id", userID.String()).Msg("failed to create api key")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	keys, err := h.apiKey.GetAPIKeys(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("user_id", userID.String()).Msg("failed to get api keys")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+func (h *APIKeyHandler) DeleteAPIKey(c *gin.Context) {
+	keyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to parse api key id into uuid")
+		response.BadRequest(c, "api key id must be UUID format")
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	if err = h.apiKey.DeleteAPIKey(c.Request.Context(), keyID, userID); err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			response.NotFound(c)
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("api_key_id", keyID.String()).Msg("failed to delete api key")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h *APIKeyHandler) getUserData(c *gin.Context) (uuid.UUID, bool) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		h.logger.Error().Ctx(c.Request.Context()).Msg("failed to get user_id from ctx")
+		response.Unauthorized(c, "missing user_id in token")
+		return uuid.Nil, false
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Any("user_id", userID).Msg("failed to parse user id into uuid")
+		response.Unauthorized(c, "user_id must be uuid format")
+		return uuid.Nil, false
+	}
+
+	return userUUID, true
+}
@@ -0,0 +1,147 @@
+package rest_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/apikey/mocks"
+	"github.com/ilam072/event-calendar/internal/apikey/rest"
+	"github.com/ilam072/event-calendar/internal/middlewares"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var log = &logger.DummyLogger{}
+
+func TestCreateAPIKey_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+
+	mockAPIKey := mocks.NewMockAPIKey(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
+	mockAPIKey.EXPECT().
+		CreateAPIKey(gomock.Any(), dto.CreateAPIKeyRequest{Name: "sync", Scope: "read"}, userID).
+		Return(dto.CreateAPIKeyResponse{Key: "ecal_x_y"}, nil)
+
+	h := rest.NewAPIKeyHandler(mockAPIKey, mockValidator, log)
+	r := routerWithHandler(h, userID.String(), middlewares.AuthMethodJWT)
+
+	body := `{"name":"sync","scope":"read"}`
+	req := httptest.NewRequest("POST", "/api-keys", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestCreateAPIKey_WithAPIKeyAuth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := rest.NewAPIKeyHandler(mocks.NewMockAPIKey(ctrl), mocks.NewMockValidator(ctrl), log)
+	r := routerWithHandler(h, uuid.New().String(), middlewares.AuthMethodAPIKey)
+
+	body := `{"name":"sync","scope":"read_write"}`
+	req := httptest.NewRequest("POST", "/api-keys", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestCreateAPIKey_ValidationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockValidator := mocks.NewMockValidator(ctrl)
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(errors.New("bad"))
+
+	h := rest.NewAPIKeyHandler(mocks.NewMockAPIKey(ctrl), mockValidator, log)
+	r := routerWithHandler(h, uuid.New().String(), middlewares.AuthMethodJWT)
+
+	body := `{"name":"","scope":"admin"}`
+	req := httptest.NewRequest("POST", "/api-keys", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGetAPIKeys_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKey := mocks.NewMockAPIKey(ctrl)
+	mockAPIKey.EXPECT().
+		GetAPIKeys(gomock.Any(), gomock.Any()).
+		Return(dto.GetAPIKeysResponse{}, nil)
+
+	h := rest.NewAPIKeyHandler(mockAPIKey, mocks.NewMockValidator(ctrl), log)
+	r := routerWithHandler(h, uuid.New().String(), middlewares.AuthMethodJWT)
+
+	req := httptest.NewRequest("GET", "/api-keys", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestDeleteAPIKey_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKey := mocks.NewMockAPIKey(ctrl)
+	mockAPIKey.EXPECT().
+		DeleteAPIKey(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.ErrAPIKeyNotFound)
+
+	h := rest.NewAPIKeyHandler(mockAPIKey, mocks.NewMockValidator(ctrl), log)
+	r := routerWithHandler(h, uuid.New().String(), middlewares.AuthMethodJWT)
+
+	req := httptest.NewRequest("DELETE", "/api-keys/"+uuid.New().String(), nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDeleteAPIKey_InvalidUUID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := rest.NewAPIKeyHandler(mocks.NewMockAPIKey(ctrl), mocks.NewMockValidator(ctrl), log)
+	r := routerWithHandler(h, uuid.New().String(), middlewares.AuthMethodJWT)
+
+	req := httptest.NewRequest("DELETE", "/api-keys/invalid", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func routerWithHandler(h *rest.APIKeyHandler, userID string, authMethod string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Set(middlewares.AuthMethodKey, authMethod)
+	})
+	r.Use(middlewares.RequireSession())
+	r.GET("/api-keys", h.GetAPIKeys)
+	r.POST("/api-keys", h.CreateAPIKey)
+	r.DELETE("/api-keys/:id", h.DeleteAPIKey)
+
+	return r
+}
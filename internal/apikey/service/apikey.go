@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/apikey/repo"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/apikey"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"time"
+)
+
+// touchInterval limits how often last_used_at is written for a busy key.
+const touchInterval = time.Minute
+
+//go:generate mockgen -source=apikey.go -destination=../mocks/service_mocks.go -package=mocks
+type APIKeyRepo interface {
+	CreateAPIKey(ctx context.Context, key domain.APIKey) (domain.APIKey, error)
+	GetAPIKeysByUser(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (domain.APIKey, error)
+	TouchAPIKey(ctx context.Context, keyID uuid.UUID) error
+	DeleteAPIKey(ctx context.Context, keyID uuid.UUID, userID uuid.UUID) error
+}
+
+type APIKey struct {
+	repo APIKeyRepo
+}
+
+func NewAPIKey(repo APIKeyRepo) *APIKey {
+	return &APIKey{repo: repo}
+}
+
+func (a *APIKey) CreateAPIKey(ctx context.Context, req dto.CreateAPIKeyRequest, userID uuid.UUID) (dto.CreateAPIKeyResponse, error) {
+	const op = "service.apikey.Create"
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return dto.CreateAPIKeyResponse{}, errutils.Wrap(op, domain.ErrInvalidExpiration)
+	}
+
+	key, prefix, secretHash, err := apikey.Generate()
+	if err != nil {
+		return dto.CreateAPIKeyResponse{}, errutils.Wrap(op, err)
+	}
+
+	created, err := a.repo.CreateAPIKey(ctx, domain.APIKey{
+		UserID:     userID,
+		Name:       req.Name,
+		Prefix:     prefix,
+		SecretHash: secretHash,
+		Scope:      req.Scope,
+		ExpiresAt:  req.ExpiresAt,
+	})
+	if err != nil {
+		return dto.CreateAPIKeyResponse{}, errutils.Wrap(op, err)
+	}
+
+	return dto.CreateAPIKeyResponse{
+		APIKey: domainToAPIKey(created),
+		Key:    key,
+	}, nil
+}
+
+func (a *APIKey) GetAPIKeys(ctx context.Context, userID uuid.UUID) (dto.GetAPIKeysResponse, error) {
+	const op = "service.apikey.GetAll"
+
+	keys, err := a.repo.GetAPIKeysByUser(ctx, userID)
+	if err != nil {
+		return dto.GetAPIKeysResponse{}, errutils.Wrap(op, err)
+	}
+
+	resp := dto.GetAPIKeysResponse{APIKeys: make([]dto.APIKey, 0, len(keys))}
+	for _, key := range keys {
+		resp.APIKeys = append(resp.APIKeys, domainToAPIKey(key))
+	}
+
+	return resp, nil
+}
+
+func (a *APIKey) DeleteAPIKey(ctx context.Context, keyID uuid.UUID, userID uuid.UUID) error {
+	const op = "service.apikey.Delete"
+
+	if err := a.repo.DeleteAPIKey(ctx, keyID, userID); err != nil {
+		if errors.Is(err, repo.ErrAPIKeyNotFound) {
+			return errutils.Wrap(op, domain.ErrAPIKeyNotFound)
+		}
+		return errutils.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Authenticate resolves a raw key from a request into the stored key.
+// Unknown, malformed and expired keys all yield domain.ErrInvalidAPIKey.
+func (a *APIKey) Authenticate(ctx context.Context, rawKey string) (domain.APIKey, error) {
+	const op = "service.apikey.Authenticate"
+
+	prefix, secret, ok := apikey.Parse(rawKey)
+	if !ok {
+		return domain.APIKey{}, errutils.Wrap(op, domain.ErrInvalidAPIKey)
+	}
+
+	key, err := a.repo.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, repo.ErrAPIKeyNotFound) {
+			return domain.APIKey{}, errutils.Wrap(op, domain.ErrInvalidAPIKey)
+		}
+		return domain.APIKey{}, errutils.Wrap(op, err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(apikey.Hash(secret)), []byte(key.SecretHash)) != 1 {
+		return domain.APIKey{}, errutils.Wrap(op, domain.ErrInvalidAPIKey)
+	}
+
+	now := time.Now()
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(now) {
+		return domain.APIKey{}, errutils.Wrap(op, domain.ErrInvalidAPIKey)
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > touchInterval {
+		if err = a.repo.TouchAPIKey(ctx, key.ID); err != nil {
+			return domain.APIKey{}, errutils.Wrap(op, err)
+		}
+	}
+
+	return key, nil
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/apikey"
+)
+
+func domainToAPIKey(key domain.APIKey) dto.APIKey {
+	return dto.APIKey{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     apikey.Prefix + key.Prefix,
+		Scope:      key.Scope,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
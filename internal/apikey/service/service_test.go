@@ -0,0 +1,159 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ilam072/event-calendar/internal/apikey/mocks"
+	"github.com/ilam072/event-calendar/internal/apikey/repo"
+	"github.com/ilam072/event-calendar/internal/apikey/service"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/apikey"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAPIKeyRepo(ctrl)
+	svc := service.NewAPIKey(mockRepo)
+
+	userID := uuid.New()
+	req := dto.CreateAPIKeyRequest{Name: "sync", Scope: domain.APIKeyScopeRead}
+
+	mockRepo.
+		EXPECT().
+		CreateAPIKey(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key domain.APIKey) (domain.APIKey, error) {
+			if key.UserID != userID || key.Name != "sync" || key.Scope != domain.APIKeyScopeRead {
+				t.Fatalf("unexpected key: %+v", key)
+			}
+			key.ID = uuid.New()
+			return key, nil
+		})
+
+	resp, err := svc.CreateAPIKey(context.Background(), req, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(resp.Key, resp.Prefix+"_") {
+		t.Fatalf("key %q does not start with prefix %q", resp.Key, resp.Prefix)
+	}
+}
+
+func TestCreateAPIKey_ExpiredInPast(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := service.NewAPIKey(mocks.NewMockAPIKeyRepo(ctrl))
+
+	past := time.Now().Add(-time.Hour)
+	req := dto.CreateAPIKeyRequest{Name: "sync", Scope: domain.APIKeyScopeRead, ExpiresAt: &past}
+
+	_, err := svc.CreateAPIKey(context.Background(), req, uuid.New())
+	if !errors.Is(err, domain.ErrInvalidExpiration) {
+		t.Fatalf("expected ErrInvalidExpiration, got %v", err)
+	}
+}
+
+func TestDeleteAPIKey_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAPIKeyRepo(ctrl)
+	svc := service.NewAPIKey(mockRepo)
+
+	mockRepo.
+		EXPECT().
+		DeleteAPIKey(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(repo.ErrAPIKeyNotFound)
+
+	err := svc.DeleteAPIKey(context.Background(), uuid.New(), uuid.New())
+	if !errors.Is(err, domain.ErrAPIKeyNotFound) {
+		t.Fatalf("expected ErrAPIKeyNotFound, got %v", err)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAPIKeyRepo(ctrl)
+	svc := service.NewAPIKey(mockRepo)
+
+	rawKey, prefix, secretHash, err := apikey.Generate()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	stored := domain.APIKey{
+		ID:         uuid.New(),
+		UserID:     uuid.New(),
+		Prefix:     prefix,
+		SecretHash: secretHash,
+		Scope:      domain.APIKeyScopeReadWrite,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo.EXPECT().GetAPIKeyByPrefix(gomock.Any(), prefix).Return(stored, nil)
+		mockRepo.EXPECT().TouchAPIKey(gomock.Any(), stored.ID).Return(nil)
+
+		key, err := svc.Authenticate(context.Background(), rawKey)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if key.UserID != stored.UserID {
+			t.Fatalf("expected user %s, got %s", stored.UserID, key.UserID)
+		}
+	})
+
+	t.Run("recently used key is not touched", func(t *testing.T) {
+		recent := stored
+		lastUsed := time.Now()
+		recent.LastUsedAt = &lastUsed
+
+		mockRepo.EXPECT().GetAPIKeyByPrefix(gomock.Any(), prefix).Return(recent, nil)
+
+		if _, err := svc.Authenticate(context.Background(), rawKey); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		mockRepo.EXPECT().GetAPIKeyByPrefix(gomock.Any(), prefix).Return(stored, nil)
+
+		forged := apikey.Prefix + prefix + "_" + strings.Repeat("0", len(rawKey)-len(apikey.Prefix+prefix+"_"))
+		_, err := svc.Authenticate(context.Background(), forged)
+		if !errors.Is(err, domain.ErrInvalidAPIKey) {
+			t.Fatalf("expected ErrInvalidAPIKey, got %v", err)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expired := stored
+		expiresAt := time.Now().Add(-time.Minute)
+		expired.ExpiresAt = &expiresAt
+
+		mockRepo.EXPECT().GetAPIKeyByPrefix(gomock.Any(), prefix).Return(expired, nil)
+
+		_, err := svc.Authenticate(context.Background(), rawKey)
+		if !errors.Is(err, domain.ErrInvalidAPIKey) {
+			t.Fatalf("expected ErrInvalidAPIKey, got %v", err)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := svc.Authenticate(context.Background(), "ecal_garbage")
+		if !errors.Is(err, domain.ErrInvalidAPIKey) {
+			t.Fatalf("expected ErrInvalidAPIKey, got %v", err)
+		}
+	})
+}
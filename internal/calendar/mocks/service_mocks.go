@@ -0,0 +1,192 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: calendar.go
+//
+// Generated by this command:
+//
+//	mockgen -source=calendar.go -destination=../mocks/service_mocks.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	domain "github.com/ilam072/event-calendar/internal/types/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCalendarRepo is a mock of CalendarRepo interface.
+type MockCalendarRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockCalendarRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockCalendarRepoMockRecorder is the mock recorder for MockCalendarRepo.
+type MockCalendarRepoMockRecorder struct {
+	mock *MockCalendarRepo
+}
+
+// NewMockCalendarRepo creates a new mock instance.
+func NewMockCalendarRepo(ctrl *gomock.Controller) *MockCalendarRepo {
+	mock := &MockCalendarRepo{ctrl: ctrl}
+	mock.recorder = &MockCalendarRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCalendarRepo) EXPECT() *MockCalendarRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateCalendar mocks base method.
+func (m *MockCalendarRepo) CreateCalendar(ctx context.Context, calendar domain.Calendar) (domain.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCalendar", ctx, calendar)
+	ret0, _ := ret[0].(domain.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCalendar indicates an expected call of CreateCalendar.
+func (mr *MockCalendarRepoMockRecorder) CreateCalendar(ctx, calendar any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCalendar", reflect.TypeOf((*MockCalendarRepo)(nil).CreateCalendar), ctx, calendar)
+}
+
+// DeleteCalendar mocks base method.
+func (m *MockCalendarRepo) DeleteCalendar(ctx context.Context, calendarID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCalendar", ctx, calendarID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCalendar indicates an expected call of DeleteCalendar.
+func (mr *MockCalendarRepoMockRecorder) DeleteCalendar(ctx, calendarID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCalendar", reflect.TypeOf((*MockCalendarRepo)(nil).DeleteCalendar), ctx, calendarID, userID)
+}
+
+// GetCalendar mocks base method.
+func (m *MockCalendarRepo) GetCalendar(ctx context.Context, calendarID, userID uuid.UUID) (domain.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendar", ctx, calendarID, userID)
+	ret0, _ := ret[0].(domain.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendar indicates an expected call of GetCalendar.
+func (mr *MockCalendarRepoMockRecorder) GetCalendar(ctx, calendarID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendar", reflect.TypeOf((*MockCalendarRepo)(nil).GetCalendar), ctx, calendarID, userID)
+}
+
+// GetCalendars mocks base method.
+func (m *MockCalendarRepo) GetCalendars(ctx context.Context, userID uuid.UUID) ([]domain.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendars", ctx, userID)
+	ret0, _ := ret[0].([]domain.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendars indicates an expected call of GetCalendars.
+func (mr *MockCalendarRepoMockRecorder) GetCalendars(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendars", reflect.TypeOf((*MockCalendarRepo)(nil).GetCalendars), ctx, userID)
+}
+
+// UpdateCalendar mocks base method.
+func (m *MockCalendarRepo) UpdateCalendar(ctx context.Context, calendar domain.Calendar) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCalendar", ctx, calendar)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCalendar indicates an expected call of UpdateCalendar.
+func (mr *MockCalendarRepoMockRecorder) UpdateCalendar(ctx, calendar any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCalendar", reflect.TypeOf((*MockCalendarRepo)(nil).UpdateCalendar), ctx, calendar)
+}
+
+// MockEventRepo is a mock of EventRepo interface.
+type MockEventRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockEventRepoMockRecorder is the mock recorder for MockEventRepo.
+type MockEventRepoMockRecorder struct {
+	mock *MockEventRepo
+}
+
+// NewMockEventRepo creates a new mock instance.
+func NewMockEventRepo(ctrl *gomock.Controller) *MockEventRepo {
+	mock := &MockEventRepo{ctrl: ctrl}
+	mock.recorder = &MockEventRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventRepo) EXPECT() *MockEventRepoMockRecorder {
+	return m.recorder
+}
+
+// TrashCalendarEvents mocks base method.
+func (m *MockEventRepo) TrashCalendarEvents(ctx context.Context, calendarID, actorID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrashCalendarEvents", ctx, calendarID, actorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TrashCalendarEvents indicates an expected call of TrashCalendarEvents.
+func (mr *MockEventRepoMockRecorder) TrashCalendarEvents(ctx, calendarID, actorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrashCalendarEvents", reflect.TypeOf((*MockEventRepo)(nil).TrashCalendarEvents), ctx, calendarID, actorID)
+}
+
+// MockTransactor is a mock of Transactor interface.
+type MockTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactorMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactorMockRecorder is the mock recorder for MockTransactor.
+type MockTransactorMockRecorder struct {
+	mock *MockTransactor
+}
+
+// NewMockTransactor creates a new mock instance.
+func NewMockTransactor(ctrl *gomock.Controller) *MockTransactor {
+	mock := &MockTransactor{ctrl: ctrl}
+	mock.recorder = &MockTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactor) EXPECT() *MockTransactorMockRecorder {
+	return m.recorder
+}
+
+// WithinTx mocks base method.
+func (m *MockTransactor) WithinTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTx indicates an expected call of WithinTx.
+func (mr *MockTransactorMockRecorder) WithinTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTx", reflect.TypeOf((*MockTransactor)(nil).WithinTx), ctx, fn)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: share.go
+//
+// Generated by this command:
+//
+//	mockgen -source=share.go -destination=../mocks/share_rest_mocks.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	dto "github.com/ilam072/event-calendar/internal/types/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockShare is a mock of Share interface.
+type MockShare struct {
+	ctrl     *gomock.Controller
+	recorder *MockShareMockRecorder
+	isgomock struct{}
+}
+
+// MockShareMockRecorder is the mock recorder for MockShare.
+type MockShareMockRecorder struct {
+	mock *MockShare
+}
+
+// NewMockShare creates a new mock instance.
+func NewMockShare(ctrl *gomock.Controller) *MockShare {
+	mock := &MockShare{ctrl: ctrl}
+	mock.recorder = &MockShareMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShare) EXPECT() *MockShareMockRecorder {
+	return m.recorder
+}
+
+// GetShares mocks base method.
+func (m *MockShare) GetShares(ctx context.Context, calendarID, userID uuid.UUID) (dto.GetCalendarSharesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShares", ctx, calendarID, userID)
+	ret0, _ := ret[0].(dto.GetCalendarSharesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShares indicates an expected call of GetShares.
+func (mr *MockShareMockRecorder) GetShares(ctx, calendarID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShares", reflect.TypeOf((*MockShare)(nil).GetShares), ctx, calendarID, userID)
+}
+
+// RevokeShare mocks base method.
+func (m *MockShare) RevokeShare(ctx context.Context, calendarID, shareID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeShare", ctx, calendarID, shareID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeShare indicates an expected call of RevokeShare.
+func (mr *MockShareMockRecorder) RevokeShare(ctx, calendarID, shareID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeShare", reflect.TypeOf((*MockShare)(nil).RevokeShare), ctx, calendarID, shareID, userID)
+}
+
+// ShareCalendar mocks base method.
+func (m *MockShare) ShareCalendar(ctx context.Context, req dto.ShareCalendarRequest, calendarID, userID uuid.UUID) (dto.CalendarShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShareCalendar", ctx, req, calendarID, userID)
+	ret0, _ := ret[0].(dto.CalendarShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShareCalendar indicates an expected call of ShareCalendar.
+func (mr *MockShareMockRecorder) ShareCalendar(ctx, req, calendarID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShareCalendar", reflect.TypeOf((*MockShare)(nil).ShareCalendar), ctx, req, calendarID, userID)
+}
@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: share.go
+//
+// Generated by this command:
+//
+//	mockgen -source=share.go -destination=../mocks/share_service_mocks.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	domain "github.com/ilam072/event-calendar/internal/types/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockShareRepo is a mock of ShareRepo interface.
+type MockShareRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockShareRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockShareRepoMockRecorder is the mock recorder for MockShareRepo.
+type MockShareRepoMockRecorder struct {
+	mock *MockShareRepo
+}
+
+// NewMockShareRepo creates a new mock instance.
+func NewMockShareRepo(ctrl *gomock.Controller) *MockShareRepo {
+	mock := &MockShareRepo{ctrl: ctrl}
+	mock.recorder = &MockShareRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShareRepo) EXPECT() *MockShareRepoMockRecorder {
+	return m.recorder
+}
+
+// DeleteShare mocks base method.
+func (m *MockShareRepo) DeleteShare(ctx context.Context, calendarID, shareID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteShare", ctx, calendarID, shareID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteShare indicates an expected call of DeleteShare.
+func (mr *MockShareRepoMockRecorder) DeleteShare(ctx, calendarID, shareID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShare", reflect.TypeOf((*MockShareRepo)(nil).DeleteShare), ctx, calendarID, shareID)
+}
+
+// GetCalendar mocks base method.
+func (m *MockShareRepo) GetCalendar(ctx context.Context, calendarID, userID uuid.UUID) (domain.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendar", ctx, calendarID, userID)
+	ret0, _ := ret[0].(domain.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendar indicates an expected call of GetCalendar.
+func (mr *MockShareRepoMockRecorder) GetCalendar(ctx, calendarID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendar", reflect.TypeOf((*MockShareRepo)(nil).GetCalendar), ctx, calendarID, userID)
+}
+
+// GetShares mocks base method.
+func (m *MockShareRepo) GetShares(ctx context.Context, calendarID uuid.UUID) ([]domain.CalendarShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShares", ctx, calendarID)
+	ret0, _ := ret[0].([]domain.CalendarShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShares indicates an expected call of GetShares.
+func (mr *MockShareRepoMockRecorder) GetShares(ctx, calendarID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShares", reflect.TypeOf((*MockShareRepo)(nil).GetShares), ctx, calendarID)
+}
+
+// UpsertShare mocks base method.
+func (m *MockShareRepo) UpsertShare(ctx context.Context, share domain.CalendarShare) (domain.CalendarShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertShare", ctx, share)
+	ret0, _ := ret[0].(domain.CalendarShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertShare indicates an expected call of UpsertShare.
+func (mr *MockShareRepoMockRecorder) UpsertShare(ctx, share any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertShare", reflect.TypeOf((*MockShareRepo)(nil).UpsertShare), ctx, share)
+}
+
+// MockSender is a mock of Sender interface.
+type MockSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockSenderMockRecorder
+	isgomock struct{}
+}
+
+// MockSenderMockRecorder is the mock recorder for MockSender.
+type MockSenderMockRecorder struct {
+	mock *MockSender
+}
+
+// NewMockSender creates a new mock instance.
+func NewMockSender(ctrl *gomock.Controller) *MockSender {
+	mock := &MockSender{ctrl: ctrl}
+	mock.recorder = &MockSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSender) EXPECT() *MockSenderMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockSender) Send(subject, message, to string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", subject, message, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockSenderMockRecorder) Send(subject, message, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockSender)(nil).Send), subject, message, to)
+}
@@ -0,0 +1,222 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/db"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrCalendarNotFound = errors.New("calendar not found")
+	ErrDefaultCalendar  = errors.New("default calendar cannot be deleted")
+	ErrShareNotFound    = errors.New("calendar share not found")
+	ErrShareWithOwner   = errors.New("calendar cannot be shared with its owner")
+)
+
+type CalendarRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewCalendarRepo(db *pgxpool.Pool) *CalendarRepo {
+	return &CalendarRepo{db: db}
+}
+
+// conn makes the queries join the transaction in ctx, if any.
+func (r *CalendarRepo) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, r.db)
+}
+
+func (r *CalendarRepo) CreateCalendar(ctx context.Context, calendar domain.Calendar) (domain.Calendar, error) {
+	query := `
+		INSERT INTO calendars (user_id, name, color, description, visibility)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, is_default, created_at, updated_at;
+	`
+
+	err := r.db.QueryRow(ctx, query, calendar.UserID, calendar.Name, calendar.Color, calendar.Description, calendar.Visibility).
+		Scan(&calendar.ID, &calendar.IsDefault, &calendar.CreatedAt, &calendar.UpdatedAt)
+	if err != nil {
+		return domain.Calendar{}, errutils.Wrap("failed to create calendar", err)
+	}
+
+	calendar.Role = domain.CalendarRoleOwner
+
+	return calendar, nil
+}
+
+// GetCalendars returns the calendars the user owns followed by the ones
+// shared with them.
+func (r *CalendarRepo) GetCalendars(ctx context.Context, userID uuid.UUID) ([]domain.Calendar, error) {
+	query := `
+		SELECT c.id, c.user_id, c.name, c.color, c.description, c.is_default AND a.role = 'owner',
+		       c.visibility, a.role, c.created_at, c.updated_at
+		FROM calendars c
+		JOIN calendar_access a ON a.calendar_id = c.id
+		WHERE a.user_id = $1
+		ORDER BY a.role = 'owner' DESC, c.is_default DESC, c.created_at;
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get calendars", err)
+	}
+	defer rows.Close()
+
+	var calendars []domain.Calendar
+	for rows.Next() {
+		var calendar domain.Calendar
+		if err := rows.Scan(
+			&calendar.ID,
+			&calendar.UserID,
+			&calendar.Name,
+			&calendar.Color,
+			&calendar.Description,
+			&calendar.IsDefault,
+			&calendar.Visibility,
+			&calendar.Role,
+			&calendar.CreatedAt,
+			&calendar.UpdatedAt,
+		); err != nil {
+			return nil, errutils.Wrap("failed to scan", err)
+		}
+		calendars = append(calendars, calendar)
+	}
+
+	return calendars, nil
+}
+
+// GetCalendar returns the calendar if the user owns it or it is shared
+// with them. Role holds the user's access.
+func (r *CalendarRepo) GetCalendar(ctx context.Context, calendarID uuid.UUID, userID uuid.UUID) (domain.Calendar, error) {
+	query := `
+		SELECT c.id, c.user_id, c.name, c.color, c.description, c.is_default AND a.role = 'owner',
+		       c.visibility, a.role, c.created_at, c.updated_at
+		FROM calendars c
+		JOIN calendar_access a ON a.calendar_id = c.id
+		WHERE c.id = $1 AND a.user_id = $2;
+	`
+
+	var calendar domain.Calendar
+	err := r.db.QueryRow(ctx, query, calendarID, userID).Scan(
+		&calendar.ID,
+		&calendar.UserID,
+		&calendar.Name,
+		&calendar.Color,
+		&calendar.Description,
+		&calendar.IsDefault,
+		&calendar.Visibility,
+		&calendar.Role,
+		&calendar.CreatedAt,
+		&calendar.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Calendar{}, errutils.Wrap("failed to get calendar", ErrCalendarNotFound)
+		}
+		return domain.Calendar{}, errutils.Wrap("failed to get calendar", err)
+	}
+
+	return calendar, nil
+}
+
+// UpdateCalendar updates the calendar. Making it the default clears the flag
+// on the user's previous default calendar; the flag itself is never cleared
+// here, so a user always keeps exactly one default calendar.
+func (r *CalendarRepo) UpdateCalendar(ctx context.Context, calendar domain.Calendar) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errutils.Wrap("failed to begin tx", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if calendar.IsDefault {
+		query := `
+			UPDATE calendars
+			SET is_default = false,
+			    updated_at = now()
+			WHERE user_id = $1 AND is_default AND id <> $2;
+		`
+		if _, err = tx.Exec(ctx, query, calendar.UserID, calendar.ID); err != nil {
+			return errutils.Wrap("failed to reset default calendar", err)
+		}
+	}
+
+	query := `
+		UPDATE calendars
+		SET name = $1,
+		    color = $2,
+		    description = $3,
+		    visibility = $4,
+		    is_default = is_default OR $5,
+		    updated_at = now()
+		WHERE id = $6 AND user_id = $7;
+	`
+
+	res, err := tx.Exec(
+		ctx,
+		query,
+		calendar.Name,
+		calendar.Color,
+		calendar.Description,
+		calendar.Visibility,
+		calendar.IsDefault,
+		calendar.ID,
+		calendar.UserID,
+	)
+	if err != nil {
+		return errutils.Wrap("failed to update calendar", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrCalendarNotFound
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return errutils.Wrap("failed to commit tx", err)
+	}
+
+	return nil
+}
+
+// DeleteCalendar deletes the calendar. The default calendar cannot be
+// deleted. Events left in the calendar are deleted with it, so callers move
+// them away first in the same transaction.
+func (r *CalendarRepo) DeleteCalendar(ctx context.Context, calendarID uuid.UUID, userID uuid.UUID) error {
+	query := `
+		DELETE FROM calendars
+		WHERE id = $1 AND user_id = $2 AND NOT is_default
+		RETURNING id;
+	`
+
+	var id uuid.UUID
+	err := r.conn(ctx).QueryRow(ctx, query, calendarID, userID).Scan(&id)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return errutils.Wrap("failed to delete calendar", err)
+	}
+
+	var isDefault bool
+	query = `SELECT is_default FROM calendars WHERE id = $1 AND user_id = $2;`
+	if err = r.conn(ctx).QueryRow(ctx, query, calendarID, userID).Scan(&isDefault); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCalendarNotFound
+		}
+		return errutils.Wrap("failed to delete calendar", err)
+	}
+
+	if isDefault {
+		return ErrDefaultCalendar
+	}
+
+	return ErrCalendarNotFound
+}
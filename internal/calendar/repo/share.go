@@ -0,0 +1,118 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"github.com/jackc/pgx/v5"
+)
+
+// UpsertShare grants the email access to the calendar or changes the role of
+// an existing grant. The share is linked to the account with that email if
+// one exists and has verified it, otherwise it stays pending until the email
+// is verified.
+func (r *CalendarRepo) UpsertShare(ctx context.Context, share domain.CalendarShare) (domain.CalendarShare, error) {
+	query := `
+		INSERT INTO calendar_shares (calendar_id, user_id, email, role, created_by)
+		SELECT $1::uuid, u.id, $2::varchar, $3::varchar, $4::uuid
+		FROM (SELECT (SELECT id FROM users WHERE lower(email) = $2 AND email_verified_at IS NOT NULL) AS id) u
+		WHERE u.id IS DISTINCT FROM (SELECT user_id FROM calendars WHERE id = $1)
+		ON CONFLICT (calendar_id, email) DO UPDATE
+		SET role = EXCLUDED.role
+		RETURNING id, calendar_id, user_id, email, role, created_by, created_at;
+	`
+
+	err := r.db.QueryRow(ctx, query, share.CalendarID, share.Email, share.Role, share.CreatedBy).Scan(
+		&share.ID,
+		&share.CalendarID,
+		&share.UserID,
+		&share.Email,
+		&share.Role,
+		&share.CreatedBy,
+		&share.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.CalendarShare{}, errutils.Wrap("failed to share calendar", ErrShareWithOwner)
+		}
+		return domain.CalendarShare{}, errutils.Wrap("failed to share calendar", err)
+	}
+
+	return share, nil
+}
+
+func (r *CalendarRepo) GetShares(ctx context.Context, calendarID uuid.UUID) ([]domain.CalendarShare, error) {
+	query := `
+		SELECT id, calendar_id, user_id, email, role, created_by, created_at
+		FROM calendar_shares
+		WHERE calendar_id = $1
+		ORDER BY created_at;
+	`
+
+	rows, err := r.db.Query(ctx, query, calendarID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get calendar shares", err)
+	}
+	defer rows.Close()
+
+	return scanShares(rows)
+}
+
+// GetUserShares returns the shares of the calendars the user owns and the
+// shares granting the user access to other calendars.
+func (r *CalendarRepo) GetUserShares(ctx context.Context, userID uuid.UUID) ([]domain.CalendarShare, error) {
+	query := `
+		SELECT s.id, s.calendar_id, s.user_id, s.email, s.role, s.created_by, s.created_at
+		FROM calendar_shares s
+		JOIN calendars c ON c.id = s.calendar_id
+		WHERE c.user_id = $1 OR s.user_id = $1
+		ORDER BY s.created_at;
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get user calendar shares", err)
+	}
+	defer rows.Close()
+
+	return scanShares(rows)
+}
+
+func scanShares(rows pgx.Rows) ([]domain.CalendarShare, error) {
+	var shares []domain.CalendarShare
+	for rows.Next() {
+		var share domain.CalendarShare
+		if err := rows.Scan(
+			&share.ID,
+			&share.CalendarID,
+			&share.UserID,
+			&share.Email,
+			&share.Role,
+			&share.CreatedBy,
+			&share.CreatedAt,
+		); err != nil {
+			return nil, errutils.Wrap("failed to scan", err)
+		}
+		shares = append(shares, share)
+	}
+
+	return shares, nil
+}
+
+func (r *CalendarRepo) DeleteShare(ctx context.Context, calendarID uuid.UUID, shareID uuid.UUID) error {
+	query := `DELETE FROM calendar_shares WHERE id = $1 AND calendar_id = $2;`
+
+	res, err := r.db.Exec(ctx, query, shareID, calendarID)
+	if err != nil {
+		return errutils.Wrap("failed to delete calendar share", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrShareNotFound
+	}
+
+	return nil
+}
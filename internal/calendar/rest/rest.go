@@ -0,0 +1,204 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/response"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"net/http"
+)
+
+//go:generate mockgen -source=rest.go -destination=../mocks/rest_mocks.go -package=mocks
+type Calendar interface {
+	CreateCalendar(ctx context.Context, req dto.CreateCalendarRequest, userID uuid.UUID) (dto.Calendar, error)
+	GetCalendars(ctx context.Context, userID uuid.UUID) (dto.GetCalendarsResponse, error)
+	GetCalendar(ctx context.Context, calendarID uuid.UUID, userID uuid.UUID) (dto.Calendar, error)
+	UpdateCalendar(ctx context.Context, req dto.UpdateCalendarRequest, calendarID uuid.UUID, userID uuid.UUID) error
+	DeleteCalendar(ctx context.Context, calendarID uuid.UUID, userID uuid.UUID) error
+}
+
+type Validator interface {
+	Validate(i interface{}) error
+}
+
+type CalendarHandler struct {
+	calendar  Calendar
+	validator Validator
+	logger    logger.Logger
+}
+
+func NewCalendarHandler(calendar Calendar, validator Validator, logger logger.Logger) *CalendarHandler {
+	return &CalendarHandler{calendar: calendar, validator: validator, logger: logger}
+}
+
+func (h *CalendarHandler) CreateCalendar(c *gin.Context) {
+	var req dto.CreateCalendarRequest
+	if err := c.BindJSON(&req); err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to bind create calendar json")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	calendar, err := h.calendar.CreateCalendar(c.Request.Context(), req, userID)
+	if err != nil {
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Any("calendar", req).Msg("failed to create calendar")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, calendar)
+}
+
+func (h *CalendarHandler) GetCalendars(c *gin.Context) {
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	calendars, err := h.calendar.GetCalendars(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("user_id", userID.String()).Msg("failed to get calendars")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, calendars)
+}
+
+func (h *CalendarHandler) GetCalendar(c *gin.Context) {
+	calendarID, ok := h.getCalendarID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	calendar, err := h.calendar.GetCalendar(c.Request.Context(), calendarID, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrCalendarNotFound) {
+			response.NotFound(c)
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("calendar_id", calendarID.String()).Msg("failed to get calendar")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, calendar)
+}
+
+func (h *CalendarHandler) UpdateCalendar(c *gin.Context) {
+	calendarID, ok := h.getCalendarID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateCalendarRequest
+	if err := c.BindJSON(&req); err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to bind update calendar json")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	if err := h.calendar.UpdateCalendar(c.Request.Context(), req, calendarID, userID); err != nil {
+		if errors.Is(err, domain.ErrCalendarNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			response.Forbidden(c, "only the owner can change the calendar")
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Any("calendar", req).Str("calendar_id", calendarID.String()).Msg("failed to update calendar")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h *CalendarHandler) DeleteCalendar(c *gin.Context) {
+	calendarID, ok := h.getCalendarID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	if err := h.calendar.DeleteCalendar(c.Request.Context(), calendarID, userID); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrCalendarNotFound):
+			response.NotFound(c)
+		case errors.Is(err, domain.ErrForbidden):
+			response.Forbidden(c, "only the owner can delete the calendar")
+		case errors.Is(err, domain.ErrDefaultCalendar):
+			response.Conflict(c, "DEFAULT_CALENDAR", "default calendar cannot be deleted")
+		default:
+			h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("calendar_id", calendarID.String()).Msg("failed to delete calendar")
+			response.InternalServerError(c)
+		}
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h *CalendarHandler) getCalendarID(c *gin.Context) (uuid.UUID, bool) {
+	calendarID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to parse calendar id into uuid")
+		response.BadRequest(c, "calendar id must be UUID format")
+		return uuid.Nil, false
+	}
+
+	return calendarID, true
+}
+
+func (h *CalendarHandler) getUserData(c *gin.Context) (uuid.UUID, bool) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		h.logger.Error().Ctx(c.Request.Context()).Msg("failed to get user_id from ctx")
+		response.Unauthorized(c, "missing user_id in token")
+		return uuid.Nil, false
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Any("user_id", userID).Msg("failed to parse user id into uuid")
+		response.Unauthorized(c, "user_id must be uuid format")
+		return uuid.Nil, false
+	}
+
+	return userUUID, true
+}
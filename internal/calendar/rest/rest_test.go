@@ -0,0 +1,197 @@
+package rest_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/calendar/mocks"
+	"github.com/ilam072/event-calendar/internal/calendar/rest"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var log = &logger.DummyLogger{}
+
+func TestCreateCalendar_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+
+	mockCalendar := mocks.NewMockCalendar(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
+	mockCalendar.EXPECT().
+		CreateCalendar(gomock.Any(), dto.CreateCalendarRequest{Name: "Work", Color: "#ff0000"}, userID).
+		Return(dto.Calendar{ID: uuid.New(), Name: "Work"}, nil)
+
+	h := rest.NewCalendarHandler(mockCalendar, mockValidator, log)
+	r := routerWithHandler(h, userID.String())
+
+	body := `{"name":"Work","color":"#ff0000"}`
+	req := httptest.NewRequest("POST", "/calendars", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestCreateCalendar_ValidationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockValidator := mocks.NewMockValidator(ctrl)
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(errors.New("bad color"))
+
+	h := rest.NewCalendarHandler(mocks.NewMockCalendar(ctrl), mockValidator, log)
+	r := routerWithHandler(h, uuid.New().String())
+
+	body := `{"name":"Work","color":"red"}`
+	req := httptest.NewRequest("POST", "/calendars", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGetCalendars_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+
+	mockCalendar := mocks.NewMockCalendar(ctrl)
+	mockCalendar.EXPECT().
+		GetCalendars(gomock.Any(), userID).
+		Return(dto.GetCalendarsResponse{Calendars: []dto.Calendar{{Name: "Personal", IsDefault: true}}}, nil)
+
+	h := rest.NewCalendarHandler(mockCalendar, mocks.NewMockValidator(ctrl), log)
+	r := routerWithHandler(h, userID.String())
+
+	req := httptest.NewRequest("GET", "/calendars", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"is_default":true`)
+}
+
+func TestGetCalendar_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCalendar := mocks.NewMockCalendar(ctrl)
+	mockCalendar.EXPECT().
+		GetCalendar(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.Calendar{}, domain.ErrCalendarNotFound)
+
+	h := rest.NewCalendarHandler(mockCalendar, mocks.NewMockValidator(ctrl), log)
+	r := routerWithHandler(h, uuid.New().String())
+
+	req := httptest.NewRequest("GET", "/calendars/"+uuid.New().String(), nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestGetCalendar_InvalidID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := rest.NewCalendarHandler(mocks.NewMockCalendar(ctrl), mocks.NewMockValidator(ctrl), log)
+	r := routerWithHandler(h, uuid.New().String())
+
+	req := httptest.NewRequest("GET", "/calendars/not-a-uuid", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestUpdateCalendar_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	calendarID, userID := uuid.New(), uuid.New()
+
+	mockCalendar := mocks.NewMockCalendar(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	req := dto.UpdateCalendarRequest{Name: "Work", Color: "#ff0000", Visibility: "public", IsDefault: true}
+
+	mockValidator.EXPECT().Validate(req).Return(nil)
+	mockCalendar.EXPECT().UpdateCalendar(gomock.Any(), req, calendarID, userID).Return(nil)
+
+	h := rest.NewCalendarHandler(mockCalendar, mockValidator, log)
+	r := routerWithHandler(h, userID.String())
+
+	body := `{"name":"Work","color":"#ff0000","visibility":"public","is_default":true}`
+	httpReq := httptest.NewRequest("PUT", "/calendars/"+calendarID.String(), bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, httpReq)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestDeleteCalendar_Default(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCalendar := mocks.NewMockCalendar(ctrl)
+	mockCalendar.EXPECT().
+		DeleteCalendar(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.ErrDefaultCalendar)
+
+	h := rest.NewCalendarHandler(mockCalendar, mocks.NewMockValidator(ctrl), log)
+	r := routerWithHandler(h, uuid.New().String())
+
+	req := httptest.NewRequest("DELETE", "/calendars/"+uuid.New().String(), nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestDeleteCalendar_InternalError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCalendar := mocks.NewMockCalendar(ctrl)
+	mockCalendar.EXPECT().
+		DeleteCalendar(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(errors.New("db down"))
+
+	h := rest.NewCalendarHandler(mockCalendar, mocks.NewMockValidator(ctrl), log)
+	r := routerWithHandler(h, uuid.New().String())
+
+	req := httptest.NewRequest("DELETE", "/calendars/"+uuid.New().String(), nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func routerWithHandler(h *rest.CalendarHandler, userID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+	})
+	r.POST("/calendars", h.CreateCalendar)
+	r.GET("/calendars", h.GetCalendars)
+	r.GET("/calendars/:id", h.GetCalendar)
+	r.PUT("/calendars/:id", h.UpdateCalendar)
+	r.DELETE("/calendars/:id", h.DeleteCalendar)
+
+	return r
+}
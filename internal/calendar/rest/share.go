@@ -0,0 +1,163 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/response"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"net/http"
+)
+
+//go:generate mockgen -source=share.go -destination=../mocks/share_rest_mocks.go -package=mocks
+type Share interface {
+	ShareCalendar(ctx context.Context, req dto.ShareCalendarRequest, calendarID uuid.UUID, userID uuid.UUID) (dto.CalendarShare, error)
+	GetShares(ctx context.Context, calendarID uuid.UUID, userID uuid.UUID) (dto.GetCalendarSharesResponse, error)
+	RevokeShare(ctx context.Context, calendarID uuid.UUID, shareID uuid.UUID, userID uuid.UUID) error
+}
+
+type ShareHandler struct {
+	share     Share
+	validator Validator
+	logger    logger.Logger
+}
+
+func NewShareHandler(share Share, validator Validator, logger logger.Logger) *ShareHandler {
+	return &ShareHandler{share: share, validator: validator, logger: logger}
+}
+
+func (h *ShareHandler) ShareCalendar(c *gin.Context) {
+	calendarID, ok := h.getCalendarID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ShareCalendarRequest
+	if err := c.BindJSON(&req); err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to bind share calendar json")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	share, err := h.share.ShareCalendar(c.Request.Context(), req, calendarID, userID)
+	if err != nil {
+		if !h.handleAccessError(c, err) {
+			h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("calendar_id", calendarID.String()).Msg("failed to share calendar")
+			response.InternalServerError(c)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, share)
+}
+
+func (h *ShareHandler) GetShares(c *gin.Context) {
+	calendarID, ok := h.getCalendarID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	shares, err := h.share.GetShares(c.Request.Context(), calendarID, userID)
+	if err != nil {
+		if !h.handleAccessError(c, err) {
+			h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("calendar_id", calendarID.String()).Msg("failed to get calendar shares")
+			response.InternalServerError(c)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, shares)
+}
+
+func (h *ShareHandler) RevokeShare(c *gin.Context) {
+	calendarID, ok := h.getCalendarID(c)
+	if !ok {
+		return
+	}
+
+	shareID, err := uuid.Parse(c.Param("share_id"))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to parse share id into uuid")
+		response.BadRequest(c, "share id must be UUID format")
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	if err = h.share.RevokeShare(c.Request.Context(), calendarID, shareID, userID); err != nil {
+		if !h.handleAccessError(c, err) {
+			h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("share_id", shareID.String()).Msg("failed to revoke calendar share")
+			response.InternalServerError(c)
+		}
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// handleAccessError writes the response for errors common to all share
+// endpoints and reports whether it did.
+func (h *ShareHandler) handleAccessError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrCalendarNotFound), errors.Is(err, domain.ErrShareNotFound):
+		response.NotFound(c)
+	case errors.Is(err, domain.ErrForbidden):
+		response.Forbidden(c, "only the owner or a manager can manage sharing")
+	case errors.Is(err, domain.ErrShareWithOwner):
+		response.BadRequest(c, "calendar cannot be shared with its owner")
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *ShareHandler) getCalendarID(c *gin.Context) (uuid.UUID, bool) {
+	calendarID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to parse calendar id into uuid")
+		response.BadRequest(c, "calendar id must be UUID format")
+		return uuid.Nil, false
+	}
+
+	return calendarID, true
+}
+
+func (h *ShareHandler) getUserData(c *gin.Context) (uuid.UUID, bool) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		h.logger.Error().Ctx(c.Request.Context()).Msg("failed to get user_id from ctx")
+		response.Unauthorized(c, "missing user_id in token")
+		return uuid.Nil, false
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Any("user_id", userID).Msg("failed to parse user id into uuid")
+		response.Unauthorized(c, "user_id must be uuid format")
+		return uuid.Nil, false
+	}
+
+	return userUUID, true
+}
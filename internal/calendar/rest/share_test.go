@@ -0,0 +1,114 @@
+package rest_test
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/calendar/mocks"
+	"github.com/ilam072/event-calendar/internal/calendar/rest"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestShareCalendar_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	calendarID, userID := uuid.New(), uuid.New()
+
+	mockShare := mocks.NewMockShare(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	req := dto.ShareCalendarRequest{Email: "bob@example.com", Role: "editor"}
+
+	mockValidator.EXPECT().Validate(req).Return(nil)
+	mockShare.EXPECT().
+		ShareCalendar(gomock.Any(), req, calendarID, userID).
+		Return(dto.CalendarShare{ID: uuid.New(), Email: "bob@example.com", Role: "editor", Pending: true}, nil)
+
+	h := rest.NewShareHandler(mockShare, mockValidator, log)
+	r := routerWithShareHandler(h, userID.String())
+
+	body := `{"email":"bob@example.com","role":"editor"}`
+	httpReq := httptest.NewRequest("POST", "/calendars/"+calendarID.String()+"/shares", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, httpReq)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"pending":true`)
+}
+
+func TestShareCalendar_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockShare := mocks.NewMockShare(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
+	mockShare.EXPECT().
+		ShareCalendar(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.CalendarShare{}, domain.ErrForbidden)
+
+	h := rest.NewShareHandler(mockShare, mockValidator, log)
+	r := routerWithShareHandler(h, uuid.New().String())
+
+	body := `{"email":"bob@example.com","role":"viewer"}`
+	httpReq := httptest.NewRequest("POST", "/calendars/"+uuid.New().String()+"/shares", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, httpReq)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestGetShares_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockShare := mocks.NewMockShare(ctrl)
+	mockShare.EXPECT().
+		GetShares(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.GetCalendarSharesResponse{}, domain.ErrCalendarNotFound)
+
+	h := rest.NewShareHandler(mockShare, mocks.NewMockValidator(ctrl), log)
+	r := routerWithShareHandler(h, uuid.New().String())
+
+	req := httptest.NewRequest("GET", "/calendars/"+uuid.New().String()+"/shares", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRevokeShare_InvalidShareID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := rest.NewShareHandler(mocks.NewMockShare(ctrl), mocks.NewMockValidator(ctrl), log)
+	r := routerWithShareHandler(h, uuid.New().String())
+
+	req := httptest.NewRequest("DELETE", "/calendars/"+uuid.New().String()+"/shares/nope", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func routerWithShareHandler(h *rest.ShareHandler, userID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+	})
+	r.GET("/calendars/:id/shares", h.GetShares)
+	r.POST("/calendars/:id/shares", h.ShareCalendar)
+	r.DELETE("/calendars/:id/shares/:share_id", h.RevokeShare)
+
+	return r
+}
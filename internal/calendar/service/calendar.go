@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/calendar/repo"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+)
+
+//go:generate mockgen -source=calendar.go -destination=../mocks/service_mocks.go -package=mocks
+type CalendarRepo interface {
+	CreateCalendar(ctx context.Context, calendar domain.Calendar) (domain.Calendar, error)
+	GetCalendars(ctx context.Context, userID uuid.UUID) ([]domain.Calendar, error)
+	GetCalendar(ctx context.Context, calendarID uuid.UUID, userID uuid.UUID) (domain.Calendar, error)
+	UpdateCalendar(ctx context.Context, calendar domain.Calendar) error
+	DeleteCalendar(ctx context.Context, calendarID uuid.UUID, userID uuid.UUID) error
+}
+
+type EventRepo interface {
+	TrashCalendarEvents(ctx context.Context, calendarID uuid.UUID, actorID uuid.UUID) error
+}
+
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type Calendar struct {
+	repo      CalendarRepo
+	eventRepo EventRepo
+	tx        Transactor
+}
+
+func NewCalendar(repo CalendarRepo, eventRepo EventRepo, tx Transactor) *Calendar {
+	return &Calendar{repo: repo, eventRepo: eventRepo, tx: tx}
+}
+
+func (s *Calendar) CreateCalendar(ctx context.Context, req dto.CreateCalendarRequest, userID uuid.UUID) (dto.Calendar, error) {
+	const op = "service.calendar.Create"
+
+	calendar := domain.Calendar{
+		UserID:      userID,
+		Name:        req.Name,
+		Color:       req.Color,
+		Description: req.Description,
+		Visibility:  req.Visibility,
+	}
+	if calendar.Color == "" {
+		calendar.Color = domain.DefaultCalendarColor
+	}
+	if calendar.Visibility == "" {
+		calendar.Visibility = domain.CalendarVisibilityPrivate
+	}
+
+	created, err := s.repo.CreateCalendar(ctx, calendar)
+	if err != nil {
+		return dto.Calendar{}, errutils.Wrap(op, err)
+	}
+
+	return domainToCalendar(created), nil
+}
+
+func (s *Calendar) GetCalendars(ctx context.Context, userID uuid.UUID) (dto.GetCalendarsResponse, error) {
+	const op = "service.calendar.GetAll"
+
+	calendars, err := s.repo.GetCalendars(ctx, userID)
+	if err != nil {
+		return dto.GetCalendarsResponse{}, errutils.Wrap(op, err)
+	}
+
+	resp := dto.GetCalendarsResponse{Calendars: make([]dto.Calendar, 0, len(calendars))}
+	for _, calendar := range calendars {
+		resp.Calendars = append(resp.Calendars, domainToCalendar(calendar))
+	}
+
+	return resp, nil
+}
+
+func (s *Calendar) GetCalendar(ctx context.Context, calendarID uuid.UUID, userID uuid.UUID) (dto.Calendar, error) {
+	const op = "service.calendar.Get"
+
+	calendar, err := s.repo.GetCalendar(ctx, calendarID, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrCalendarNotFound) {
+			return dto.Calendar{}, errutils.Wrap(op, domain.ErrCalendarNotFound)
+		}
+		return dto.Calendar{}, errutils.Wrap(op, err)
+	}
+
+	return domainToCalendar(calendar), nil
+}
+
+func (s *Calendar) UpdateCalendar(ctx context.Context, req dto.UpdateCalendarRequest, calendarID uuid.UUID, userID uuid.UUID) error {
+	const op = "service.calendar.Update"
+
+	if err := s.checkOwner(ctx, calendarID, userID); err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	calendar := domain.Calendar{
+		ID:          calendarID,
+		UserID:      userID,
+		Name:        req.Name,
+		Color:       req.Color,
+		Description: req.Description,
+		Visibility:  req.Visibility,
+		IsDefault:   req.IsDefault,
+	}
+
+	if err := s.repo.UpdateCalendar(ctx, calendar); err != nil {
+		if errors.Is(err, repo.ErrCalendarNotFound) {
+			return errutils.Wrap(op, domain.ErrCalendarNotFound)
+		}
+		return errutils.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// DeleteCalendar deletes a calendar owned by the user. Its events are moved
+// to the trash of the user's default calendar.
+func (s *Calendar) DeleteCalendar(ctx context.Context, calendarID uuid.UUID, userID uuid.UUID) error {
+	const op = "service.calendar.Delete"
+
+	if err := s.checkOwner(ctx, calendarID, userID); err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	// Events would otherwise go with the calendar, bypassing the trash and
+	// the history.
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.eventRepo.TrashCalendarEvents(ctx, calendarID, userID); err != nil {
+			return err
+		}
+		return s.repo.DeleteCalendar(ctx, calendarID, userID)
+	})
+	if err != nil {
+		if errors.Is(err, repo.ErrCalendarNotFound) {
+			return errutils.Wrap(op, domain.ErrCalendarNotFound)
+		}
+		if errors.Is(err, repo.ErrDefaultCalendar) {
+			return errutils.Wrap(op, domain.ErrDefaultCalendar)
+		}
+		return errutils.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// checkOwner lets only the owner change or delete a calendar; users it is
+// shared with get domain.ErrForbidden.
+func (s *Calendar) checkOwner(ctx context.Context, calendarID uuid.UUID, userID uuid.UUID) error {
+	calendar, err := s.repo.GetCalendar(ctx, calendarID, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrCalendarNotFound) {
+			return domain.ErrCalendarNotFound
+		}
+		return err
+	}
+
+	if calendar.Role != domain.CalendarRoleOwner {
+		return domain.ErrForbidden
+	}
+
+	return nil
+}
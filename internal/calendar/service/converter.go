@@ -0,0 +1,31 @@
+package service
+
+import (
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+)
+
+func domainToCalendar(calendar domain.Calendar) dto.Calendar {
+	return dto.Calendar{
+		ID:          calendar.ID,
+		Name:        calendar.Name,
+		Color:       calendar.Color,
+		Description: calendar.Description,
+		IsDefault:   calendar.IsDefault,
+		Visibility:  calendar.Visibility,
+		Role:        calendar.Role,
+		CreatedAt:   calendar.CreatedAt,
+		UpdatedAt:   calendar.UpdatedAt,
+	}
+}
+
+func domainToShare(share domain.CalendarShare) dto.CalendarShare {
+	return dto.CalendarShare{
+		ID:        share.ID,
+		UserID:    share.UserID,
+		Email:     share.Email,
+		Role:      share.Role,
+		Pending:   share.UserID == nil,
+		CreatedAt: share.CreatedAt,
+	}
+}
@@ -0,0 +1,227 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/ilam072/event-calendar/internal/calendar/mocks"
+	"github.com/ilam072/event-calendar/internal/calendar/repo"
+	"github.com/ilam072/event-calendar/internal/calendar/service"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+)
+
+func TestCreateCalendar_Defaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCalendarRepo(ctrl)
+	svc := service.NewCalendar(mockRepo, mocks.NewMockEventRepo(ctrl), mocks.NewMockTransactor(ctrl))
+
+	userID := uuid.New()
+
+	mockRepo.
+		EXPECT().
+		CreateCalendar(gomock.Any(), domain.Calendar{
+			UserID:     userID,
+			Name:       "Work",
+			Color:      domain.DefaultCalendarColor,
+			Visibility: domain.CalendarVisibilityPrivate,
+		}).
+		DoAndReturn(func(_ context.Context, calendar domain.Calendar) (domain.Calendar, error) {
+			calendar.ID = uuid.New()
+			return calendar, nil
+		})
+
+	calendar, err := svc.CreateCalendar(context.Background(), dto.CreateCalendarRequest{Name: "Work"}, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calendar.ID == uuid.Nil || calendar.Color != domain.DefaultCalendarColor {
+		t.Fatalf("unexpected calendar: %+v", calendar)
+	}
+}
+
+func TestGetCalendars(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCalendarRepo(ctrl)
+	svc := service.NewCalendar(mockRepo, mocks.NewMockEventRepo(ctrl), mocks.NewMockTransactor(ctrl))
+
+	userID := uuid.New()
+
+	mockRepo.
+		EXPECT().
+		GetCalendars(gomock.Any(), userID).
+		Return([]domain.Calendar{{ID: uuid.New(), IsDefault: true}, {ID: uuid.New()}}, nil)
+
+	resp, err := svc.GetCalendars(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Calendars) != 2 || !resp.Calendars[0].IsDefault {
+		t.Fatalf("unexpected calendars: %+v", resp.Calendars)
+	}
+}
+
+func TestGetCalendar_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCalendarRepo(ctrl)
+	svc := service.NewCalendar(mockRepo, mocks.NewMockEventRepo(ctrl), mocks.NewMockTransactor(ctrl))
+
+	mockRepo.
+		EXPECT().
+		GetCalendar(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.Calendar{}, repo.ErrCalendarNotFound)
+
+	_, err := svc.GetCalendar(context.Background(), uuid.New(), uuid.New())
+	if !errors.Is(err, domain.ErrCalendarNotFound) {
+		t.Fatalf("expected ErrCalendarNotFound, got %v", err)
+	}
+}
+
+func TestUpdateCalendar(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCalendarRepo(ctrl)
+	svc := service.NewCalendar(mockRepo, mocks.NewMockEventRepo(ctrl), mocks.NewMockTransactor(ctrl))
+
+	calendarID, userID := uuid.New(), uuid.New()
+	req := dto.UpdateCalendarRequest{
+		Name:       "Work",
+		Color:      "#ff0000",
+		Visibility: domain.CalendarVisibilityPublic,
+		IsDefault:  true,
+	}
+
+	mockRepo.
+		EXPECT().
+		GetCalendar(gomock.Any(), calendarID, userID).
+		Return(domain.Calendar{ID: calendarID, Role: domain.CalendarRoleOwner}, nil)
+	mockRepo.
+		EXPECT().
+		UpdateCalendar(gomock.Any(), domain.Calendar{
+			ID:         calendarID,
+			UserID:     userID,
+			Name:       "Work",
+			Color:      "#ff0000",
+			Visibility: domain.CalendarVisibilityPublic,
+			IsDefault:  true,
+		}).
+		Return(nil)
+
+	if err := svc.UpdateCalendar(context.Background(), req, calendarID, userID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUpdateCalendar_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCalendarRepo(ctrl)
+	svc := service.NewCalendar(mockRepo, mocks.NewMockEventRepo(ctrl), mocks.NewMockTransactor(ctrl))
+
+	mockRepo.
+		EXPECT().
+		GetCalendar(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.Calendar{}, repo.ErrCalendarNotFound)
+
+	err := svc.UpdateCalendar(context.Background(), dto.UpdateCalendarRequest{}, uuid.New(), uuid.New())
+	if !errors.Is(err, domain.ErrCalendarNotFound) {
+		t.Fatalf("expected ErrCalendarNotFound, got %v", err)
+	}
+}
+
+func TestDeleteCalendar_TrashesEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCalendarRepo(ctrl)
+	mockEvents := mocks.NewMockEventRepo(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	svc := service.NewCalendar(mockRepo, mockEvents, mockTx)
+
+	calendarID, userID := uuid.New(), uuid.New()
+
+	mockRepo.
+		EXPECT().
+		GetCalendar(gomock.Any(), calendarID, userID).
+		Return(domain.Calendar{Role: domain.CalendarRoleOwner}, nil)
+	mockTx.
+		EXPECT().
+		WithinTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+	gomock.InOrder(
+		mockEvents.EXPECT().TrashCalendarEvents(gomock.Any(), calendarID, userID).Return(nil),
+		mockRepo.EXPECT().DeleteCalendar(gomock.Any(), calendarID, userID).Return(nil),
+	)
+
+	if err := svc.DeleteCalendar(context.Background(), calendarID, userID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDeleteCalendar_Default(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCalendarRepo(ctrl)
+	mockEvents := mocks.NewMockEventRepo(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	svc := service.NewCalendar(mockRepo, mockEvents, mockTx)
+
+	mockRepo.
+		EXPECT().
+		GetCalendar(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.Calendar{Role: domain.CalendarRoleOwner}, nil)
+	mockTx.
+		EXPECT().
+		WithinTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+	mockEvents.
+		EXPECT().
+		TrashCalendarEvents(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil)
+	mockRepo.
+		EXPECT().
+		DeleteCalendar(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(repo.ErrDefaultCalendar)
+
+	err := svc.DeleteCalendar(context.Background(), uuid.New(), uuid.New())
+	if !errors.Is(err, domain.ErrDefaultCalendar) {
+		t.Fatalf("expected ErrDefaultCalendar, got %v", err)
+	}
+}
+
+func TestDeleteCalendar_NotOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCalendarRepo(ctrl)
+	svc := service.NewCalendar(mockRepo, mocks.NewMockEventRepo(ctrl), mocks.NewMockTransactor(ctrl))
+
+	mockRepo.
+		EXPECT().
+		GetCalendar(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.Calendar{Role: domain.CalendarRoleManager}, nil)
+
+	err := svc.DeleteCalendar(context.Background(), uuid.New(), uuid.New())
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+}
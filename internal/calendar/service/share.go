@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/calendar/repo"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"strings"
+)
+
+//go:generate mockgen -source=share.go -destination=../mocks/share_service_mocks.go -package=mocks
+type ShareRepo interface {
+	GetCalendar(ctx context.Context, calendarID uuid.UUID, userID uuid.UUID) (domain.Calendar, error)
+	UpsertShare(ctx context.Context, share domain.CalendarShare) (domain.CalendarShare, error)
+	GetShares(ctx context.Context, calendarID uuid.UUID) ([]domain.CalendarShare, error)
+	DeleteShare(ctx context.Context, calendarID uuid.UUID, shareID uuid.UUID) error
+}
+
+type Sender interface {
+	Send(subject string, message string, to string) error
+}
+
+// Share manages who else can access a calendar. Owners and managers may
+// grant and revoke access.
+type Share struct {
+	repo   ShareRepo
+	sender Sender
+	logger logger.Logger
+}
+
+func NewShare(repo ShareRepo, sender Sender, logger logger.Logger) *Share {
+	return &Share{repo: repo, sender: sender, logger: logger}
+}
+
+// ShareCalendar grants the email the requested role. Emails without a
+// verified account get an invitation and receive access once they sign up
+// and confirm the email.
+func (s *Share) ShareCalendar(ctx context.Context, req dto.ShareCalendarRequest, calendarID uuid.UUID, userID uuid.UUID) (dto.CalendarShare, error) {
+	const op = "service.share.Share"
+
+	calendar, err := s.managedCalendar(ctx, calendarID, userID)
+	if err != nil {
+		return dto.CalendarShare{}, errutils.Wrap(op, err)
+	}
+
+	share, err := s.repo.UpsertShare(ctx, domain.CalendarShare{
+		CalendarID: calendarID,
+		Email:      strings.ToLower(req.Email),
+		Role:       req.Role,
+		CreatedBy:  &userID,
+	})
+	if err != nil {
+		if errors.Is(err, repo.ErrShareWithOwner) {
+			return dto.CalendarShare{}, errutils.Wrap(op, domain.ErrShareWithOwner)
+		}
+		return dto.CalendarShare{}, errutils.Wrap(op, err)
+	}
+
+	// The invitation is sent in the background so that a slow mail server
+	// does not hold up the request.
+	if share.UserID == nil {
+		go s.sendInvitation(context.WithoutCancel(ctx), share, calendar)
+	}
+
+	return domainToShare(share), nil
+}
+
+func (s *Share) GetShares(ctx context.Context, calendarID uuid.UUID, userID uuid.UUID) (dto.GetCalendarSharesResponse, error) {
+	const op = "service.share.GetAll"
+
+	if _, err := s.managedCalendar(ctx, calendarID, userID); err != nil {
+		return dto.GetCalendarSharesResponse{}, errutils.Wrap(op, err)
+	}
+
+	shares, err := s.repo.GetShares(ctx, calendarID)
+	if err != nil {
+		return dto.GetCalendarSharesResponse{}, errutils.Wrap(op, err)
+	}
+
+	resp := dto.GetCalendarSharesResponse{Shares: make([]dto.CalendarShare, 0, len(shares))}
+	for _, share := range shares {
+		resp.Shares = append(resp.Shares, domainToShare(share))
+	}
+
+	return resp, nil
+}
+
+// RevokeShare removes the grant, including pending invitations.
+func (s *Share) RevokeShare(ctx context.Context, calendarID uuid.UUID, shareID uuid.UUID, userID uuid.UUID) error {
+	const op = "service.share.Revoke"
+
+	if _, err := s.managedCalendar(ctx, calendarID, userID); err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	if err := s.repo.DeleteShare(ctx, calendarID, shareID); err != nil {
+		if errors.Is(err, repo.ErrShareNotFound) {
+			return errutils.Wrap(op, domain.ErrShareNotFound)
+		}
+		return errutils.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (s *Share) managedCalendar(ctx context.Context, calendarID uuid.UUID, userID uuid.UUID) (domain.Calendar, error) {
+	calendar, err := s.repo.GetCalendar(ctx, calendarID, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrCalendarNotFound) {
+			return domain.Calendar{}, domain.ErrCalendarNotFound
+		}
+		return domain.Calendar{}, err
+	}
+
+	if !domain.CanManageShares(calendar.Role) {
+		return domain.Calendar{}, domain.ErrForbidden
+	}
+
+	return calendar, nil
+}
+
+func (s *Share) sendInvitation(ctx context.Context, share domain.CalendarShare, calendar domain.Calendar) {
+	message := fmt.Sprintf(
+		"You have been invited to the calendar %q as %s.\n\n"+
+			"Sign up to Event Calendar with this email address and confirm it to get access.",
+		calendar.Name, share.Role,
+	)
+
+	if err := s.sender.Send("You have been invited to a calendar", message, share.Email); err != nil {
+		s.logger.Error().Ctx(ctx).Err(err).Str("share_id", share.ID.String()).Msg("failed to send calendar invitation")
+	}
+}
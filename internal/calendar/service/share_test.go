@@ -0,0 +1,172 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ilam072/event-calendar/internal/calendar/mocks"
+	"github.com/ilam072/event-calendar/internal/calendar/repo"
+	"github.com/ilam072/event-calendar/internal/calendar/service"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/logger"
+)
+
+func TestShareCalendar_InvitesUnknownEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShareRepo(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	svc := service.NewShare(mockRepo, mockSender, &logger.DummyLogger{})
+
+	calendarID, userID := uuid.New(), uuid.New()
+	sent := make(chan struct{})
+
+	mockRepo.
+		EXPECT().
+		GetCalendar(gomock.Any(), calendarID, userID).
+		Return(domain.Calendar{ID: calendarID, Name: "Work", Role: domain.CalendarRoleOwner}, nil)
+	mockRepo.
+		EXPECT().
+		UpsertShare(gomock.Any(), domain.CalendarShare{
+			CalendarID: calendarID,
+			Email:      "bob@example.com",
+			Role:       domain.CalendarRoleEditor,
+			CreatedBy:  &userID,
+		}).
+		DoAndReturn(func(_ context.Context, share domain.CalendarShare) (domain.CalendarShare, error) {
+			share.ID = uuid.New()
+			return share, nil
+		})
+	mockSender.
+		EXPECT().
+		Send(gomock.Any(), gomock.Any(), "bob@example.com").
+		Do(func(string, string, string) { close(sent) }).
+		Return(nil)
+
+	share, err := svc.ShareCalendar(context.Background(), dto.ShareCalendarRequest{
+		Email: "Bob@Example.com",
+		Role:  domain.CalendarRoleEditor,
+	}, calendarID, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !share.Pending || share.Email != "bob@example.com" {
+		t.Fatalf("unexpected share: %+v", share)
+	}
+
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatalf("invitation was not sent")
+	}
+}
+
+func TestShareCalendar_ExistingUserNotInvited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShareRepo(ctrl)
+	svc := service.NewShare(mockRepo, mocks.NewMockSender(ctrl), &logger.DummyLogger{})
+
+	memberID := uuid.New()
+
+	mockRepo.
+		EXPECT().
+		GetCalendar(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.Calendar{Role: domain.CalendarRoleManager}, nil)
+	mockRepo.
+		EXPECT().
+		UpsertShare(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, share domain.CalendarShare) (domain.CalendarShare, error) {
+			share.UserID = &memberID
+			return share, nil
+		})
+
+	share, err := svc.ShareCalendar(context.Background(), dto.ShareCalendarRequest{
+		Email: "bob@example.com",
+		Role:  domain.CalendarRoleViewer,
+	}, uuid.New(), uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if share.Pending || share.UserID == nil || *share.UserID != memberID {
+		t.Fatalf("unexpected share: %+v", share)
+	}
+}
+
+func TestShareCalendar_Editor_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShareRepo(ctrl)
+	svc := service.NewShare(mockRepo, mocks.NewMockSender(ctrl), &logger.DummyLogger{})
+
+	mockRepo.
+		EXPECT().
+		GetCalendar(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.Calendar{Role: domain.CalendarRoleEditor}, nil)
+
+	_, err := svc.ShareCalendar(context.Background(), dto.ShareCalendarRequest{
+		Email: "bob@example.com",
+		Role:  domain.CalendarRoleViewer,
+	}, uuid.New(), uuid.New())
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestShareCalendar_WithOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShareRepo(ctrl)
+	svc := service.NewShare(mockRepo, mocks.NewMockSender(ctrl), &logger.DummyLogger{})
+
+	mockRepo.
+		EXPECT().
+		GetCalendar(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.Calendar{Role: domain.CalendarRoleOwner}, nil)
+	mockRepo.
+		EXPECT().
+		UpsertShare(gomock.Any(), gomock.Any()).
+		Return(domain.CalendarShare{}, repo.ErrShareWithOwner)
+
+	_, err := svc.ShareCalendar(context.Background(), dto.ShareCalendarRequest{
+		Email: "me@example.com",
+		Role:  domain.CalendarRoleViewer,
+	}, uuid.New(), uuid.New())
+	if !errors.Is(err, domain.ErrShareWithOwner) {
+		t.Fatalf("expected ErrShareWithOwner, got %v", err)
+	}
+}
+
+func TestRevokeShare_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShareRepo(ctrl)
+	svc := service.NewShare(mockRepo, mocks.NewMockSender(ctrl), &logger.DummyLogger{})
+
+	mockRepo.
+		EXPECT().
+		GetCalendar(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.Calendar{Role: domain.CalendarRoleOwner}, nil)
+	mockRepo.
+		EXPECT().
+		DeleteShare(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(repo.ErrShareNotFound)
+
+	err := svc.RevokeShare(context.Background(), uuid.New(), uuid.New(), uuid.New())
+	if !errors.Is(err, domain.ErrShareNotFound) {
+		t.Fatalf("expected ErrShareNotFound, got %v", err)
+	}
+}
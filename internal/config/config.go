@@ -11,20 +11,30 @@ type Config struct {
 	Server ServerConfig
 	SMTP   SMTPConfig
 	JWT    JWTConfig
+	MFA    MFAConfig
+	OIDC   OIDCConfig
 	Logger LoggerConfig
+	Trash  TrashConfig
+	Health HealthConfig
+	Trace  TraceConfig
 }
 
 type DBConfig struct {
-	PgUser     string `env:"PGUSER"`
-	PgPassword string `env:"PGPASSWORD"`
-	PgHost     string `env:"PGHOST"`
-	PgPort     uint16 `env:"PGPORT"`
-	PgDatabase string `env:"PGDATABASE"`
-	PgSSLMode  string `env:"PGSSLMODE"`
+	PgUser      string `env:"PGUSER"`
+	PgPassword  string `env:"PGPASSWORD"`
+	PgHost      string `env:"PGHOST"`
+	PgPort      uint16 `env:"PGPORT"`
+	PgDatabase  string `env:"PGDATABASE"`
+	PgSSLMode   string `env:"PGSSLMODE"`
+	AutoMigrate bool   `env:"MIGRATE_ON_STARTUP" envDefault:"false"`
 }
 
 type ServerConfig struct {
-	HTTPPort string `env:"HTTP_PORT"`
+	HTTPPort       string        `env:"HTTP_PORT"`
+	TrustedProxies []string      `env:"TRUSTED_PROXIES" envSeparator:","`
+	PublicURL      string        `env:"PUBLIC_URL" envDefault:"http://localhost:8080"`
+	IdempotencyTTL time.Duration `env:"IDEMPOTENCY_TTL" envDefault:"24h"`
+	ShutdownDelay  time.Duration `env:"SHUTDOWN_DELAY" envDefault:"5s"`
 }
 
 type SMTPConfig struct {
@@ -36,12 +46,55 @@ type SMTPConfig struct {
 }
 
 type JWTConfig struct {
-	Secret   string        `env:"SECRET"`
-	TokenTTL time.Duration `env:"TOKEN_TTL"`
+	Secret    string        `env:"SECRET"`
+	TokenTTL  time.Duration `env:"TOKEN_TTL"`
+	KeysDir   string        `env:"JWT_KEYS_DIR"`
+	ActiveKID string        `env:"JWT_ACTIVE_KID"`
+	// AcceptHS256 keeps HS256 tokens signed with Secret valid once keys are
+	// in use. Turn it off when every such token has expired.
+	AcceptHS256 bool `env:"JWT_ACCEPT_HS256" envDefault:"true"`
+}
+
+type MFAConfig struct {
+	TokenTTL   time.Duration `env:"MFA_TOKEN_TTL" envDefault:"5m"`
+	TOTPIssuer string        `env:"TOTP_ISSUER" envDefault:"Event Calendar"`
+}
+
+type OIDCConfig struct {
+	IssuerURL    string `env:"OIDC_ISSUER_URL"`
+	ClientID     string `env:"OIDC_CLIENT_ID"`
+	ClientSecret string `env:"OIDC_CLIENT_SECRET"`
+	RedirectURL  string `env:"OIDC_REDIRECT_URL"`
+}
+
+type TrashConfig struct {
+	Retention time.Duration `env:"TRASH_RETENTION" envDefault:"720h"`
+}
+
+type HealthConfig struct {
+	CheckSMTP    bool          `env:"HEALTH_CHECK_SMTP" envDefault:"false"`
+	SMTPCheckTTL time.Duration `env:"HEALTH_SMTP_CHECK_TTL" envDefault:"1m"`
+}
+
+type TraceConfig struct {
+	ServiceName string  `env:"OTEL_SERVICE_NAME" envDefault:"event-calendar"`
+	Exporter    string  `env:"OTEL_TRACES_EXPORTER" envDefault:"none"`
+	Protocol    string  `env:"OTEL_EXPORTER_OTLP_PROTOCOL" envDefault:"http/protobuf"`
+	Endpoint    string  `env:"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"`
+	SampleRatio float64 `env:"OTEL_TRACES_SAMPLER_ARG" envDefault:"1"`
 }
 
 type LoggerConfig struct {
-	File string `env:"LOG_FILE"`
+	Backend      string        `env:"LOG_BACKEND" envDefault:"async"`
+	Output       string        `env:"LOG_OUTPUT" envDefault:"file"`
+	File         string        `env:"LOG_FILE"`
+	Level        string        `env:"LOG_LEVEL" envDefault:"info"`
+	Buffer       int           `env:"LOG_BUFFER" envDefault:"1000"`
+	Block        bool          `env:"LOG_BLOCK_WHEN_FULL" envDefault:"false"`
+	MaxSizeMB    int64         `env:"LOG_MAX_SIZE_MB" envDefault:"100"`
+	MaxAge       time.Duration `env:"LOG_MAX_AGE" envDefault:"24h"`
+	MaxBackups   int           `env:"LOG_MAX_BACKUPS" envDefault:"7"`
+	MaxBackupAge time.Duration `env:"LOG_MAX_BACKUP_AGE" envDefault:"168h"`
 }
 
 func MustLoad() *Config {
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: attendee.go
+//
+// Generated by this command:
+//
+//	mockgen -source=attendee.go -destination=../mocks/attendee_rest_mocks.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	dto "github.com/ilam072/event-calendar/internal/types/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAttendee is a mock of Attendee interface.
+type MockAttendee struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttendeeMockRecorder
+	isgomock struct{}
+}
+
+// MockAttendeeMockRecorder is the mock recorder for MockAttendee.
+type MockAttendeeMockRecorder struct {
+	mock *MockAttendee
+}
+
+// NewMockAttendee creates a new mock instance.
+func NewMockAttendee(ctrl *gomock.Controller) *MockAttendee {
+	mock := &MockAttendee{ctrl: ctrl}
+	mock.recorder = &MockAttendeeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttendee) EXPECT() *MockAttendeeMockRecorder {
+	return m.recorder
+}
+
+// AddAttendees mocks base method.
+func (m *MockAttendee) AddAttendees(ctx context.Context, req dto.AddAttendeesRequest, eventID, userID uuid.UUID) (dto.GetAttendeesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAttendees", ctx, req, eventID, userID)
+	ret0, _ := ret[0].(dto.GetAttendeesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAttendees indicates an expected call of AddAttendees.
+func (mr *MockAttendeeMockRecorder) AddAttendees(ctx, req, eventID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAttendees", reflect.TypeOf((*MockAttendee)(nil).AddAttendees), ctx, req, eventID, userID)
+}
+
+// RemoveAttendee mocks base method.
+func (m *MockAttendee) RemoveAttendee(ctx context.Context, eventID, attendeeID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAttendee", ctx, eventID, attendeeID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAttendee indicates an expected call of RemoveAttendee.
+func (mr *MockAttendeeMockRecorder) RemoveAttendee(ctx, eventID, attendeeID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAttendee", reflect.TypeOf((*MockAttendee)(nil).RemoveAttendee), ctx, eventID, attendeeID, userID)
+}
+
+// Respond mocks base method.
+func (m *MockAttendee) Respond(ctx context.Context, req dto.RSVPRequest, eventID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Respond", ctx, req, eventID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Respond indicates an expected call of Respond.
+func (mr *MockAttendeeMockRecorder) Respond(ctx, req, eventID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Respond", reflect.TypeOf((*MockAttendee)(nil).Respond), ctx, req, eventID, userID)
+}
+
+// RespondByLink mocks base method.
+func (m *MockAttendee) RespondByLink(ctx context.Context, token string, req dto.RSVPRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondByLink", ctx, token, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RespondByLink indicates an expected call of RespondByLink.
+func (mr *MockAttendeeMockRecorder) RespondByLink(ctx, token, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondByLink", reflect.TypeOf((*MockAttendee)(nil).RespondByLink), ctx, token, req)
+}
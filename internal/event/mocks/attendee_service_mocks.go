@@ -0,0 +1,209 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: attendee.go
+//
+// Generated by this command:
+//
+//	mockgen -source=attendee.go -destination=../mocks/attendee_service_mocks.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	domain "github.com/ilam072/event-calendar/internal/types/domain"
+	jwt "github.com/ilam072/event-calendar/pkg/jwt"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAttendeeRepo is a mock of AttendeeRepo interface.
+type MockAttendeeRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttendeeRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockAttendeeRepoMockRecorder is the mock recorder for MockAttendeeRepo.
+type MockAttendeeRepoMockRecorder struct {
+	mock *MockAttendeeRepo
+}
+
+// NewMockAttendeeRepo creates a new mock instance.
+func NewMockAttendeeRepo(ctrl *gomock.Controller) *MockAttendeeRepo {
+	mock := &MockAttendeeRepo{ctrl: ctrl}
+	mock.recorder = &MockAttendeeRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttendeeRepo) EXPECT() *MockAttendeeRepoMockRecorder {
+	return m.recorder
+}
+
+// AddAttendees mocks base method.
+func (m *MockAttendeeRepo) AddAttendees(ctx context.Context, eventID, userID uuid.UUID, emails []string) ([]domain.Attendee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAttendees", ctx, eventID, userID, emails)
+	ret0, _ := ret[0].([]domain.Attendee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAttendees indicates an expected call of AddAttendees.
+func (mr *MockAttendeeRepoMockRecorder) AddAttendees(ctx, eventID, userID, emails any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAttendees", reflect.TypeOf((*MockAttendeeRepo)(nil).AddAttendees), ctx, eventID, userID, emails)
+}
+
+// GetEventByID mocks base method.
+func (m *MockAttendeeRepo) GetEventByID(ctx context.Context, eventID uuid.UUID) (domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventByID", ctx, eventID)
+	ret0, _ := ret[0].(domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventByID indicates an expected call of GetEventByID.
+func (mr *MockAttendeeRepoMockRecorder) GetEventByID(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventByID", reflect.TypeOf((*MockAttendeeRepo)(nil).GetEventByID), ctx, eventID)
+}
+
+// RemoveAttendee mocks base method.
+func (m *MockAttendeeRepo) RemoveAttendee(ctx context.Context, eventID, attendeeID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAttendee", ctx, eventID, attendeeID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAttendee indicates an expected call of RemoveAttendee.
+func (mr *MockAttendeeRepoMockRecorder) RemoveAttendee(ctx, eventID, attendeeID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAttendee", reflect.TypeOf((*MockAttendeeRepo)(nil).RemoveAttendee), ctx, eventID, attendeeID, userID)
+}
+
+// RespondAsAttendee mocks base method.
+func (m *MockAttendeeRepo) RespondAsAttendee(ctx context.Context, attendeeID uuid.UUID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondAsAttendee", ctx, attendeeID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RespondAsAttendee indicates an expected call of RespondAsAttendee.
+func (mr *MockAttendeeRepoMockRecorder) RespondAsAttendee(ctx, attendeeID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondAsAttendee", reflect.TypeOf((*MockAttendeeRepo)(nil).RespondAsAttendee), ctx, attendeeID, status)
+}
+
+// RespondAsUser mocks base method.
+func (m *MockAttendeeRepo) RespondAsUser(ctx context.Context, eventID, userID uuid.UUID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondAsUser", ctx, eventID, userID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RespondAsUser indicates an expected call of RespondAsUser.
+func (mr *MockAttendeeRepoMockRecorder) RespondAsUser(ctx, eventID, userID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondAsUser", reflect.TypeOf((*MockAttendeeRepo)(nil).RespondAsUser), ctx, eventID, userID, status)
+}
+
+// MockSender is a mock of Sender interface.
+type MockSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockSenderMockRecorder
+	isgomock struct{}
+}
+
+// MockSenderMockRecorder is the mock recorder for MockSender.
+type MockSenderMockRecorder struct {
+	mock *MockSender
+}
+
+// NewMockSender creates a new mock instance.
+func NewMockSender(ctrl *gomock.Controller) *MockSender {
+	mock := &MockSender{ctrl: ctrl}
+	mock.recorder = &MockSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSender) EXPECT() *MockSenderMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockSender) Send(subject, message, to string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", subject, message, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockSenderMockRecorder) Send(subject, message, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockSender)(nil).Send), subject, message, to)
+}
+
+// MockRSVPTokenManager is a mock of RSVPTokenManager interface.
+type MockRSVPTokenManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockRSVPTokenManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockRSVPTokenManagerMockRecorder is the mock recorder for MockRSVPTokenManager.
+type MockRSVPTokenManagerMockRecorder struct {
+	mock *MockRSVPTokenManager
+}
+
+// NewMockRSVPTokenManager creates a new mock instance.
+func NewMockRSVPTokenManager(ctrl *gomock.Controller) *MockRSVPTokenManager {
+	mock := &MockRSVPTokenManager{ctrl: ctrl}
+	mock.recorder = &MockRSVPTokenManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRSVPTokenManager) EXPECT() *MockRSVPTokenManagerMockRecorder {
+	return m.recorder
+}
+
+// NewRSVPToken mocks base method.
+func (m *MockRSVPTokenManager) NewRSVPToken(attendeeID string, ttl time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewRSVPToken", attendeeID, ttl)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewRSVPToken indicates an expected call of NewRSVPToken.
+func (mr *MockRSVPTokenManagerMockRecorder) NewRSVPToken(attendeeID, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewRSVPToken", reflect.TypeOf((*MockRSVPTokenManager)(nil).NewRSVPToken), attendeeID, ttl)
+}
+
+// ParseRSVPToken mocks base method.
+func (m *MockRSVPTokenManager) ParseRSVPToken(tokenStr string) (*jwt.TokenClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseRSVPToken", tokenStr)
+	ret0, _ := ret[0].(*jwt.TokenClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseRSVPToken indicates an expected call of ParseRSVPToken.
+func (mr *MockRSVPTokenManagerMockRecorder) ParseRSVPToken(tokenStr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseRSVPToken", reflect.TypeOf((*MockRSVPTokenManager)(nil).ParseRSVPToken), tokenStr)
+}
@@ -44,91 +44,152 @@ func (m *MockEvent) EXPECT() *MockEventMockRecorder {
 }
 
 // CreateEvent mocks base method.
-func (m *MockEvent) CreateEvent(ctx context.Context, event dto.CreateEventRequest, userID uuid.UUID) (uuid.UUID, error) {
+func (m *MockEvent) CreateEvent(ctx context.Context, event dto.CreateEventRequest, userID uuid.UUID, strict bool) (dto.CreateEventResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateEvent", ctx, event, userID)
-	ret0, _ := ret[0].(uuid.UUID)
+	ret := m.ctrl.Call(m, "CreateEvent", ctx, event, userID, strict)
+	ret0, _ := ret[0].(dto.CreateEventResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateEvent indicates an expected call of CreateEvent.
-func (mr *MockEventMockRecorder) CreateEvent(ctx, event, userID any) *gomock.Call {
+func (mr *MockEventMockRecorder) CreateEvent(ctx, event, userID, strict any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockEvent)(nil).CreateEvent), ctx, event, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockEvent)(nil).CreateEvent), ctx, event, userID, strict)
 }
 
 // DeleteEvent mocks base method.
-func (m *MockEvent) DeleteEvent(ctx context.Context, eventID, userID uuid.UUID) error {
+func (m *MockEvent) DeleteEvent(ctx context.Context, eventID, userID uuid.UUID, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteEvent", ctx, eventID, userID)
+	ret := m.ctrl.Call(m, "DeleteEvent", ctx, eventID, userID, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteEvent indicates an expected call of DeleteEvent.
-func (mr *MockEventMockRecorder) DeleteEvent(ctx, eventID, userID any) *gomock.Call {
+func (mr *MockEventMockRecorder) DeleteEvent(ctx, eventID, userID, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*MockEvent)(nil).DeleteEvent), ctx, eventID, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*MockEvent)(nil).DeleteEvent), ctx, eventID, userID, version)
+}
+
+// GetEvent mocks base method.
+func (m *MockEvent) GetEvent(ctx context.Context, eventID, userID uuid.UUID) (dto.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvent", ctx, eventID, userID)
+	ret0, _ := ret[0].(dto.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvent indicates an expected call of GetEvent.
+func (mr *MockEventMockRecorder) GetEvent(ctx, eventID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvent", reflect.TypeOf((*MockEvent)(nil).GetEvent), ctx, eventID, userID)
 }
 
 // GetEventsForDay mocks base method.
-func (m *MockEvent) GetEventsForDay(ctx context.Context, userID uuid.UUID, date time.Time) (dto.GetEventsResponse, error) {
+func (m *MockEvent) GetEventsForDay(ctx context.Context, userID uuid.UUID, date time.Time, calendarIDs []uuid.UUID) (dto.GetEventsResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventsForDay", ctx, userID, date)
+	ret := m.ctrl.Call(m, "GetEventsForDay", ctx, userID, date, calendarIDs)
 	ret0, _ := ret[0].(dto.GetEventsResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEventsForDay indicates an expected call of GetEventsForDay.
-func (mr *MockEventMockRecorder) GetEventsForDay(ctx, userID, date any) *gomock.Call {
+func (mr *MockEventMockRecorder) GetEventsForDay(ctx, userID, date, calendarIDs any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForDay", reflect.TypeOf((*MockEvent)(nil).GetEventsForDay), ctx, userID, date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForDay", reflect.TypeOf((*MockEvent)(nil).GetEventsForDay), ctx, userID, date, calendarIDs)
 }
 
 // GetEventsForMonth mocks base method.
-func (m *MockEvent) GetEventsForMonth(ctx context.Context, userID uuid.UUID, date time.Time) (dto.GetEventsResponse, error) {
+func (m *MockEvent) GetEventsForMonth(ctx context.Context, userID uuid.UUID, date time.Time, calendarIDs []uuid.UUID) (dto.GetEventsResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventsForMonth", ctx, userID, date)
+	ret := m.ctrl.Call(m, "GetEventsForMonth", ctx, userID, date, calendarIDs)
 	ret0, _ := ret[0].(dto.GetEventsResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEventsForMonth indicates an expected call of GetEventsForMonth.
-func (mr *MockEventMockRecorder) GetEventsForMonth(ctx, userID, date any) *gomock.Call {
+func (mr *MockEventMockRecorder) GetEventsForMonth(ctx, userID, date, calendarIDs any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForMonth", reflect.TypeOf((*MockEvent)(nil).GetEventsForMonth), ctx, userID, date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForMonth", reflect.TypeOf((*MockEvent)(nil).GetEventsForMonth), ctx, userID, date, calendarIDs)
 }
 
 // GetEventsForWeek mocks base method.
-func (m *MockEvent) GetEventsForWeek(ctx context.Context, userID uuid.UUID, date time.Time) (dto.GetEventsResponse, error) {
+func (m *MockEvent) GetEventsForWeek(ctx context.Context, userID uuid.UUID, date time.Time, calendarIDs []uuid.UUID) (dto.GetEventsResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventsForWeek", ctx, userID, date)
+	ret := m.ctrl.Call(m, "GetEventsForWeek", ctx, userID, date, calendarIDs)
 	ret0, _ := ret[0].(dto.GetEventsResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEventsForWeek indicates an expected call of GetEventsForWeek.
-func (mr *MockEventMockRecorder) GetEventsForWeek(ctx, userID, date any) *gomock.Call {
+func (mr *MockEventMockRecorder) GetEventsForWeek(ctx, userID, date, calendarIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForWeek", reflect.TypeOf((*MockEvent)(nil).GetEventsForWeek), ctx, userID, date, calendarIDs)
+}
+
+// GetHistory mocks base method.
+func (m *MockEvent) GetHistory(ctx context.Context, eventID, userID uuid.UUID) (dto.GetHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, eventID, userID)
+	ret0, _ := ret[0].(dto.GetHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockEventMockRecorder) GetHistory(ctx, eventID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockEvent)(nil).GetHistory), ctx, eventID, userID)
+}
+
+// PatchEvent mocks base method.
+func (m *MockEvent) PatchEvent(ctx context.Context, patch dto.PatchEventRequest, eventID, userID uuid.UUID, version int, strict bool) (dto.UpdateEventResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchEvent", ctx, patch, eventID, userID, version, strict)
+	ret0, _ := ret[0].(dto.UpdateEventResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchEvent indicates an expected call of PatchEvent.
+func (mr *MockEventMockRecorder) PatchEvent(ctx, patch, eventID, userID, version, strict any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchEvent", reflect.TypeOf((*MockEvent)(nil).PatchEvent), ctx, patch, eventID, userID, version, strict)
+}
+
+// RevertEvent mocks base method.
+func (m *MockEvent) RevertEvent(ctx context.Context, eventID uuid.UUID, revisionID int64, userID uuid.UUID, version int) (dto.UpdateEventResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertEvent", ctx, eventID, revisionID, userID, version)
+	ret0, _ := ret[0].(dto.UpdateEventResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertEvent indicates an expected call of RevertEvent.
+func (mr *MockEventMockRecorder) RevertEvent(ctx, eventID, revisionID, userID, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForWeek", reflect.TypeOf((*MockEvent)(nil).GetEventsForWeek), ctx, userID, date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertEvent", reflect.TypeOf((*MockEvent)(nil).RevertEvent), ctx, eventID, revisionID, userID, version)
 }
 
 // UpdateEvent mocks base method.
-func (m *MockEvent) UpdateEvent(ctx context.Context, event dto.UpdateEventRequest, eventID, userID uuid.UUID) error {
+func (m *MockEvent) UpdateEvent(ctx context.Context, event dto.UpdateEventRequest, eventID, userID uuid.UUID, version int, strict bool) (dto.UpdateEventResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateEvent", ctx, event, eventID, userID)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "UpdateEvent", ctx, event, eventID, userID, version, strict)
+	ret0, _ := ret[0].(dto.UpdateEventResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UpdateEvent indicates an expected call of UpdateEvent.
-func (mr *MockEventMockRecorder) UpdateEvent(ctx, event, eventID, userID any) *gomock.Call {
+func (mr *MockEventMockRecorder) UpdateEvent(ctx, event, eventID, userID, version, strict any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEvent", reflect.TypeOf((*MockEvent)(nil).UpdateEvent), ctx, event, eventID, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEvent", reflect.TypeOf((*MockEvent)(nil).UpdateEvent), ctx, event, eventID, userID, version, strict)
 }
 
 // MockValidator is a mock of Validator interface.
@@ -59,70 +59,161 @@ func (mr *MockEventRepoMockRecorder) CreateEvent(ctx, event any) *gomock.Call {
 }
 
 // DeleteEvent mocks base method.
-func (m *MockEventRepo) DeleteEvent(ctx context.Context, eventID, userID uuid.UUID) error {
+func (m *MockEventRepo) DeleteEvent(ctx context.Context, eventID, userID uuid.UUID, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteEvent", ctx, eventID, userID)
+	ret := m.ctrl.Call(m, "DeleteEvent", ctx, eventID, userID, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteEvent indicates an expected call of DeleteEvent.
-func (mr *MockEventRepoMockRecorder) DeleteEvent(ctx, eventID, userID any) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) DeleteEvent(ctx, eventID, userID, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*MockEventRepo)(nil).DeleteEvent), ctx, eventID, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*MockEventRepo)(nil).DeleteEvent), ctx, eventID, userID, version)
+}
+
+// GetAttendees mocks base method.
+func (m *MockEventRepo) GetAttendees(ctx context.Context, eventIDs []uuid.UUID) ([]domain.Attendee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttendees", ctx, eventIDs)
+	ret0, _ := ret[0].([]domain.Attendee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttendees indicates an expected call of GetAttendees.
+func (mr *MockEventRepoMockRecorder) GetAttendees(ctx, eventIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttendees", reflect.TypeOf((*MockEventRepo)(nil).GetAttendees), ctx, eventIDs)
+}
+
+// GetEvent mocks base method.
+func (m *MockEventRepo) GetEvent(ctx context.Context, eventID, userID uuid.UUID) (domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvent", ctx, eventID, userID)
+	ret0, _ := ret[0].(domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvent indicates an expected call of GetEvent.
+func (mr *MockEventRepoMockRecorder) GetEvent(ctx, eventID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvent", reflect.TypeOf((*MockEventRepo)(nil).GetEvent), ctx, eventID, userID)
 }
 
 // GetEventsForDay mocks base method.
-func (m *MockEventRepo) GetEventsForDay(ctx context.Context, userID uuid.UUID, date time.Time) ([]domain.Event, error) {
+func (m *MockEventRepo) GetEventsForDay(ctx context.Context, userID uuid.UUID, date time.Time, calendarIDs []uuid.UUID) ([]domain.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventsForDay", ctx, userID, date)
+	ret := m.ctrl.Call(m, "GetEventsForDay", ctx, userID, date, calendarIDs)
 	ret0, _ := ret[0].([]domain.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEventsForDay indicates an expected call of GetEventsForDay.
-func (mr *MockEventRepoMockRecorder) GetEventsForDay(ctx, userID, date any) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) GetEventsForDay(ctx, userID, date, calendarIDs any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForDay", reflect.TypeOf((*MockEventRepo)(nil).GetEventsForDay), ctx, userID, date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForDay", reflect.TypeOf((*MockEventRepo)(nil).GetEventsForDay), ctx, userID, date, calendarIDs)
 }
 
 // GetEventsForMonth mocks base method.
-func (m *MockEventRepo) GetEventsForMonth(ctx context.Context, userID uuid.UUID, start time.Time) ([]domain.Event, error) {
+func (m *MockEventRepo) GetEventsForMonth(ctx context.Context, userID uuid.UUID, start time.Time, calendarIDs []uuid.UUID) ([]domain.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventsForMonth", ctx, userID, start)
+	ret := m.ctrl.Call(m, "GetEventsForMonth", ctx, userID, start, calendarIDs)
 	ret0, _ := ret[0].([]domain.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEventsForMonth indicates an expected call of GetEventsForMonth.
-func (mr *MockEventRepoMockRecorder) GetEventsForMonth(ctx, userID, start any) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) GetEventsForMonth(ctx, userID, start, calendarIDs any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForMonth", reflect.TypeOf((*MockEventRepo)(nil).GetEventsForMonth), ctx, userID, start)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForMonth", reflect.TypeOf((*MockEventRepo)(nil).GetEventsForMonth), ctx, userID, start, calendarIDs)
 }
 
 // GetEventsForWeek mocks base method.
-func (m *MockEventRepo) GetEventsForWeek(ctx context.Context, userID uuid.UUID, start time.Time) ([]domain.Event, error) {
+func (m *MockEventRepo) GetEventsForWeek(ctx context.Context, userID uuid.UUID, start time.Time, calendarIDs []uuid.UUID) ([]domain.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventsForWeek", ctx, userID, start)
+	ret := m.ctrl.Call(m, "GetEventsForWeek", ctx, userID, start, calendarIDs)
 	ret0, _ := ret[0].([]domain.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEventsForWeek indicates an expected call of GetEventsForWeek.
-func (mr *MockEventRepoMockRecorder) GetEventsForWeek(ctx, userID, start any) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) GetEventsForWeek(ctx, userID, start, calendarIDs any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForWeek", reflect.TypeOf((*MockEventRepo)(nil).GetEventsForWeek), ctx, userID, start)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForWeek", reflect.TypeOf((*MockEventRepo)(nil).GetEventsForWeek), ctx, userID, start, calendarIDs)
+}
+
+// GetHistory mocks base method.
+func (m *MockEventRepo) GetHistory(ctx context.Context, eventID, userID uuid.UUID) ([]domain.EventRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, eventID, userID)
+	ret0, _ := ret[0].([]domain.EventRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockEventRepoMockRecorder) GetHistory(ctx, eventID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockEventRepo)(nil).GetHistory), ctx, eventID, userID)
+}
+
+// GetOverlappingEvents mocks base method.
+func (m *MockEventRepo) GetOverlappingEvents(ctx context.Context, userID uuid.UUID, startsAt, endsAt time.Time, excludeID uuid.UUID) ([]domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOverlappingEvents", ctx, userID, startsAt, endsAt, excludeID)
+	ret0, _ := ret[0].([]domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOverlappingEvents indicates an expected call of GetOverlappingEvents.
+func (mr *MockEventRepoMockRecorder) GetOverlappingEvents(ctx, userID, startsAt, endsAt, excludeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverlappingEvents", reflect.TypeOf((*MockEventRepo)(nil).GetOverlappingEvents), ctx, userID, startsAt, endsAt, excludeID)
+}
+
+// GetRevision mocks base method.
+func (m *MockEventRepo) GetRevision(ctx context.Context, eventID uuid.UUID, revisionID int64, userID uuid.UUID) (domain.EventRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, eventID, revisionID, userID)
+	ret0, _ := ret[0].(domain.EventRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockEventRepoMockRecorder) GetRevision(ctx, eventID, revisionID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockEventRepo)(nil).GetRevision), ctx, eventID, revisionID, userID)
+}
+
+// RevertEvent mocks base method.
+func (m *MockEventRepo) RevertEvent(ctx context.Context, event domain.Event) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertEvent", ctx, event)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertEvent indicates an expected call of RevertEvent.
+func (mr *MockEventRepoMockRecorder) RevertEvent(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertEvent", reflect.TypeOf((*MockEventRepo)(nil).RevertEvent), ctx, event)
 }
 
 // UpdateEvent mocks base method.
-func (m *MockEventRepo) UpdateEvent(ctx context.Context, event domain.Event) error {
+func (m *MockEventRepo) UpdateEvent(ctx context.Context, event domain.Event) (int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateEvent", ctx, event)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UpdateEvent indicates an expected call of UpdateEvent.
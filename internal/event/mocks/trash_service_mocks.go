@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: trash.go
+//
+// Generated by this command:
+//
+//	mockgen -source=trash.go -destination=../mocks/trash_service_mocks.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	domain "github.com/ilam072/event-calendar/internal/types/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTrashRepo is a mock of TrashRepo interface.
+type MockTrashRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrashRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockTrashRepoMockRecorder is the mock recorder for MockTrashRepo.
+type MockTrashRepoMockRecorder struct {
+	mock *MockTrashRepo
+}
+
+// NewMockTrashRepo creates a new mock instance.
+func NewMockTrashRepo(ctrl *gomock.Controller) *MockTrashRepo {
+	mock := &MockTrashRepo{ctrl: ctrl}
+	mock.recorder = &MockTrashRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrashRepo) EXPECT() *MockTrashRepoMockRecorder {
+	return m.recorder
+}
+
+// EmptyTrash mocks base method.
+func (m *MockTrashRepo) EmptyTrash(ctx context.Context, userID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmptyTrash", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EmptyTrash indicates an expected call of EmptyTrash.
+func (mr *MockTrashRepoMockRecorder) EmptyTrash(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmptyTrash", reflect.TypeOf((*MockTrashRepo)(nil).EmptyTrash), ctx, userID)
+}
+
+// GetTrash mocks base method.
+func (m *MockTrashRepo) GetTrash(ctx context.Context, userID uuid.UUID) ([]domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", ctx, userID)
+	ret0, _ := ret[0].([]domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockTrashRepoMockRecorder) GetTrash(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockTrashRepo)(nil).GetTrash), ctx, userID)
+}
+
+// RestoreEvent mocks base method.
+func (m *MockTrashRepo) RestoreEvent(ctx context.Context, eventID, userID uuid.UUID) (domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreEvent", ctx, eventID, userID)
+	ret0, _ := ret[0].(domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreEvent indicates an expected call of RestoreEvent.
+func (mr *MockTrashRepoMockRecorder) RestoreEvent(ctx, eventID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreEvent", reflect.TypeOf((*MockTrashRepo)(nil).RestoreEvent), ctx, eventID, userID)
+}
@@ -0,0 +1,201 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"github.com/jackc/pgx/v5"
+)
+
+// AddAttendees invites the emails to the event and returns the attendees
+// that were not invited before. Emails that belong to an account with a
+// verified email are linked to it right away. The user needs write access to the event's calendar.
+func (r *EventRepo) AddAttendees(ctx context.Context, eventID uuid.UUID, userID uuid.UUID, emails []string) ([]domain.Attendee, error) {
+	if err := r.checkEventWritable(ctx, eventID, userID); err != nil {
+		return nil, errutils.Wrap("failed to add attendees", err)
+	}
+
+	query := `
+		INSERT INTO event_attendees (event_id, user_id, email)
+		SELECT $1::uuid, u.id, e.email
+		FROM unnest($2::varchar[]) AS e(email)
+		LEFT JOIN users u ON lower(u.email) = e.email AND u.email_verified_at IS NOT NULL
+		ON CONFLICT (event_id, email) DO NOTHING
+		RETURNING id, event_id, user_id, email, status, responded_at, created_at;
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, eventID, emails)
+	if err != nil {
+		return nil, errutils.Wrap("failed to add attendees", err)
+	}
+	defer rows.Close()
+
+	attendees, err := scanAttendees(rows)
+	if err != nil {
+		return nil, errutils.Wrap("failed to add attendees", err)
+	}
+
+	return attendees, nil
+}
+
+// GetAttendees returns the attendees of all the given events.
+func (r *EventRepo) GetAttendees(ctx context.Context, eventIDs []uuid.UUID) ([]domain.Attendee, error) {
+	query := `
+		SELECT id, event_id, user_id, email, status, responded_at, created_at
+		FROM event_attendees
+		WHERE event_id = ANY($1)
+		ORDER BY created_at, email;
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, eventIDs)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get attendees", err)
+	}
+	defer rows.Close()
+
+	attendees, err := scanAttendees(rows)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get attendees", err)
+	}
+
+	return attendees, nil
+}
+
+// GetUserInvitations returns the invitations linked to the user. Invitations
+// of an email are only linked once the user has verified it.
+func (r *EventRepo) GetUserInvitations(ctx context.Context, userID uuid.UUID) ([]domain.Attendee, error) {
+	query := `
+		SELECT id, event_id, user_id, email, status, responded_at, created_at
+		FROM event_attendees
+		WHERE user_id = $1
+		ORDER BY created_at;
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, userID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get invitations", err)
+	}
+	defer rows.Close()
+
+	attendees, err := scanAttendees(rows)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get invitations", err)
+	}
+
+	return attendees, nil
+}
+
+func (r *EventRepo) RemoveAttendee(ctx context.Context, eventID uuid.UUID, attendeeID uuid.UUID, userID uuid.UUID) error {
+	if err := r.checkEventWritable(ctx, eventID, userID); err != nil {
+		return errutils.Wrap("failed to remove attendee", err)
+	}
+
+	query := `DELETE FROM event_attendees WHERE id = $1 AND event_id = $2;`
+
+	res, err := r.conn(ctx).Exec(ctx, query, attendeeID, eventID)
+	if err != nil {
+		return errutils.Wrap("failed to remove attendee", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrAttendeeNotFound
+	}
+
+	return nil
+}
+
+// RespondAsUser records the RSVP of the user invited to the event. Events in
+// the trash can not be responded to.
+func (r *EventRepo) RespondAsUser(ctx context.Context, eventID uuid.UUID, userID uuid.UUID, status string) error {
+	query := `
+		UPDATE event_attendees a
+		SET status = $3, responded_at = now()
+		FROM events e
+		WHERE a.event_id = $1 AND a.user_id = $2
+		  AND e.id = a.event_id AND e.deleted_at IS NULL;
+	`
+
+	res, err := r.conn(ctx).Exec(ctx, query, eventID, userID, status)
+	if err != nil {
+		return errutils.Wrap("failed to respond to invitation", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrAttendeeNotFound
+	}
+
+	return nil
+}
+
+// RespondAsAttendee records the RSVP of an attendee identified by a signed
+// link, which works for external emails as well. Events in the trash can not
+// be responded to.
+func (r *EventRepo) RespondAsAttendee(ctx context.Context, attendeeID uuid.UUID, status string) error {
+	query := `
+		UPDATE event_attendees a
+		SET status = $2, responded_at = now()
+		FROM events e
+		WHERE a.id = $1
+		  AND e.id = a.event_id AND e.deleted_at IS NULL;
+	`
+
+	res, err := r.conn(ctx).Exec(ctx, query, attendeeID, status)
+	if err != nil {
+		return errutils.Wrap("failed to respond to invitation", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrAttendeeNotFound
+	}
+
+	return nil
+}
+
+// checkEventWritable returns ErrEventNotFound or ErrReadOnly unless the user
+// may change the event.
+func (r *EventRepo) checkEventWritable(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) error {
+	query := `
+		SELECT a.role
+		FROM events e
+		JOIN calendar_access a ON a.calendar_id = e.calendar_id
+		WHERE e.id = $1 AND a.user_id = $2 AND e.deleted_at IS NULL;
+	`
+
+	var role string
+	if err := r.conn(ctx).QueryRow(ctx, query, eventID, userID).Scan(&role); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrEventNotFound
+		}
+		return errutils.Wrap("failed to check event access", err)
+	}
+
+	if !domain.CanWriteEvents(role) {
+		return ErrReadOnly
+	}
+
+	return nil
+}
+
+func scanAttendees(rows pgx.Rows) ([]domain.Attendee, error) {
+	var attendees []domain.Attendee
+	for rows.Next() {
+		var attendee domain.Attendee
+		if err := rows.Scan(
+			&attendee.ID,
+			&attendee.EventID,
+			&attendee.UserID,
+			&attendee.Email,
+			&attendee.Status,
+			&attendee.RespondedAt,
+			&attendee.CreatedAt,
+		); err != nil {
+			return nil, errutils.Wrap("failed to scan", err)
+		}
+		attendees = append(attendees, attendee)
+	}
+
+	return attendees, rows.Err()
+}
@@ -0,0 +1,96 @@
+package repo
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"time"
+)
+
+// GetBusyEvents returns the events that keep the users with the given emails
+// busy between from and to: events in their own calendars and invitations
+// they accepted. Only calendars the requester owns, has been shared or that
+// are public are taken into account. All-day events are returned for one
+// extra day on each side, as their bounds depend on the caller's time zone.
+func (r *EventRepo) GetBusyEvents(ctx context.Context, requesterID uuid.UUID, emails []string, from time.Time, to time.Time) ([]domain.BusyEvent, error) {
+	query := `
+		WITH visible AS (
+			SELECT id, user_id
+			FROM calendars
+			WHERE visibility = 'public'
+			   OR id IN (SELECT calendar_id FROM calendar_access WHERE user_id = $1)
+		), in_range AS (
+			SELECT id, calendar_id, event_date, starts_at, ends_at
+			FROM events
+			WHERE deleted_at IS NULL
+			  AND ((starts_at IS NULL AND event_date BETWEEN $3::timestamptz::date - 1 AND $4::timestamptz::date + 1)
+			    OR (starts_at < $4 AND ends_at > $3))
+		)
+		SELECT lower(u.email), e.event_date, e.starts_at, e.ends_at
+		FROM in_range e
+		JOIN visible v ON v.id = e.calendar_id
+		JOIN users u ON u.id = v.user_id
+		WHERE lower(u.email) = ANY($2::text[])
+		UNION ALL
+		SELECT a.email, e.event_date, e.starts_at, e.ends_at
+		FROM in_range e
+		JOIN event_attendees a ON a.event_id = e.id
+		WHERE a.email = ANY($2::text[])
+		  AND a.user_id IS NOT NULL
+		  AND a.status = 'accepted'
+		  AND (a.user_id = $1 OR e.calendar_id IN (SELECT id FROM visible));
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, requesterID, emails, from, to)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get busy events", err)
+	}
+	defer rows.Close()
+
+	var events []domain.BusyEvent
+	for rows.Next() {
+		var event domain.BusyEvent
+		if err := rows.Scan(&event.Email, &event.Date, &event.StartsAt, &event.EndsAt); err != nil {
+			return nil, errutils.Wrap("failed to scan", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// GetVisibleEmails returns which of the given emails belong to the requester
+// or to a user owning at least one calendar the requester can see. Anyone
+// else, including emails without an account, cannot be vouched for as free.
+func (r *EventRepo) GetVisibleEmails(ctx context.Context, requesterID uuid.UUID, emails []string) ([]string, error) {
+	query := `
+		SELECT DISTINCT lower(u.email)
+		FROM users u
+		WHERE lower(u.email) = ANY($2::text[])
+		  AND (u.id = $1 OR EXISTS (
+			SELECT 1
+			FROM calendars c
+			WHERE c.user_id = u.id
+			  AND (c.visibility = 'public'
+			    OR c.id IN (SELECT calendar_id FROM calendar_access WHERE user_id = $1))
+		  ));
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, requesterID, emails)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get visible emails", err)
+	}
+	defer rows.Close()
+
+	var visible []string
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, errutils.Wrap("failed to scan", err)
+		}
+		visible = append(visible, email)
+	}
+
+	return visible, rows.Err()
+}
@@ -0,0 +1,222 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/db"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"github.com/ilam072/event-calendar/pkg/requestid"
+	"github.com/jackc/pgx/v5"
+)
+
+// snapshotSQL builds a domain.EventSnapshot from an events row, for writes
+// that affect many events at once. Dates and reminder times are stored
+// without a time zone, so they are read as UTC like pgx does.
+const snapshotSQL = `jsonb_build_object(
+		'calendar_id', calendar_id,
+		'date', event_date::timestamp AT TIME ZONE 'UTC',
+		'starts_at', starts_at,
+		'ends_at', ends_at,
+		'description', description,
+		'remind_at', remind_at AT TIME ZONE 'UTC',
+		'deleted_at', deleted_at AT TIME ZONE 'UTC')`
+
+// inTx runs fn in the transaction of ctx, or in a new one, so that a write
+// and its history entry are committed together.
+func (r *EventRepo) inTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return db.NewTransactor(r.db).WithinTx(ctx, fn)
+}
+
+// lockEvent returns the event, trashed or not, and locks it until the end of
+// the transaction.
+func (r *EventRepo) lockEvent(ctx context.Context, eventID uuid.UUID) (domain.Event, error) {
+	query := `
+		SELECT id, user_id, calendar_id, event_date, starts_at, ends_at, description, remind_at, sent, created_at, updated_at, version, deleted_at
+		FROM events
+		WHERE id = $1
+		FOR UPDATE;
+	`
+
+	var event domain.Event
+	err := r.conn(ctx).QueryRow(ctx, query, eventID).
+		Scan(&event.ID, &event.UserID, &event.CalendarID, &event.Date, &event.StartsAt, &event.EndsAt, &event.Description, &event.RemindAt, &event.Sent, &event.CreatedAt, &event.UpdatedAt, &event.Version, &event.DeletedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Event{}, ErrEventNotFound
+		}
+		return domain.Event{}, err
+	}
+
+	return event, nil
+}
+
+// recordHistory appends a history entry for the change from before to after,
+// either of which may be nil. The version is the one the change produced.
+func (r *EventRepo) recordHistory(ctx context.Context, action string, actorID uuid.UUID, before *domain.Event, after *domain.Event) error {
+	query := `
+		INSERT INTO event_history (event_id, action, actor_id, version, before, after, changes, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''));
+	`
+
+	var eventID uuid.UUID
+	var version int
+	var from, to *domain.EventSnapshot
+	if before != nil {
+		eventID, version, from = before.ID, before.Version, domain.NewEventSnapshot(*before)
+	}
+	if after != nil {
+		eventID, version, to = after.ID, after.Version, domain.NewEventSnapshot(*after)
+	}
+
+	var actor *uuid.UUID
+	if actorID != uuid.Nil {
+		actor = &actorID
+	}
+
+	_, err := r.conn(ctx).Exec(ctx, query, eventID, action, actor, version, from, to, domain.Diff(from, to), requestid.FromContext(ctx))
+	if err != nil {
+		return errutils.Wrap("failed to record history", err)
+	}
+
+	return nil
+}
+
+// checkEventReadable returns ErrEventNotFound unless the event, trashed or
+// not, is in a calendar the user has access to.
+func (r *EventRepo) checkEventReadable(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) error {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM events e
+			JOIN calendar_access a ON a.calendar_id = e.calendar_id
+			WHERE e.id = $1 AND a.user_id = $2
+		);
+	`
+
+	var exists bool
+	if err := r.conn(ctx).QueryRow(ctx, query, eventID, userID).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return ErrEventNotFound
+	}
+
+	return nil
+}
+
+// GetHistory returns the history of the event, oldest first.
+func (r *EventRepo) GetHistory(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) ([]domain.EventRevision, error) {
+	if err := r.checkEventReadable(ctx, eventID, userID); err != nil {
+		return nil, errutils.Wrap("failed to get history", err)
+	}
+
+	query := `
+		SELECT id, event_id, action, actor_id, version, before, after, changes, COALESCE(request_id, ''), created_at
+		FROM event_history
+		WHERE event_id = $1
+		ORDER BY id;
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, eventID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get history", err)
+	}
+	defer rows.Close()
+
+	revisions, err := scanRevisions(rows)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get history", err)
+	}
+
+	return revisions, nil
+}
+
+// GetUserHistory returns the history of the user's events, including the
+// archived ones, and the changes the user made to other events, oldest first.
+func (r *EventRepo) GetUserHistory(ctx context.Context, userID uuid.UUID) ([]domain.EventRevision, error) {
+	query := `
+		SELECT id, event_id, action, actor_id, version, before, after, changes, COALESCE(request_id, ''), created_at
+		FROM event_history
+		WHERE actor_id = $1
+		   OR event_id IN (
+		       SELECT id FROM events WHERE user_id = $1
+		       UNION
+		       SELECT id FROM events_archive WHERE user_id = $1
+		   )
+		ORDER BY id;
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, userID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get user history", err)
+	}
+	defer rows.Close()
+
+	revisions, err := scanRevisions(rows)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get user history", err)
+	}
+
+	return revisions, nil
+}
+
+// GetRevision returns a single history entry of the event.
+func (r *EventRepo) GetRevision(ctx context.Context, eventID uuid.UUID, revisionID int64, userID uuid.UUID) (domain.EventRevision, error) {
+	if err := r.checkEventReadable(ctx, eventID, userID); err != nil {
+		return domain.EventRevision{}, errutils.Wrap("failed to get revision", err)
+	}
+
+	query := `
+		SELECT id, event_id, action, actor_id, version, before, after, changes, COALESCE(request_id, ''), created_at
+		FROM event_history
+		WHERE event_id = $1 AND id = $2;
+	`
+
+	var revision domain.EventRevision
+	err := r.conn(ctx).QueryRow(ctx, query, eventID, revisionID).
+		Scan(&revision.ID, &revision.EventID, &revision.Action, &revision.ActorID, &revision.Version, &revision.Before, &revision.After, &revision.Changes, &revision.RequestID, &revision.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.EventRevision{}, errutils.Wrap("failed to get revision", ErrRevisionNotFound)
+		}
+		return domain.EventRevision{}, errutils.Wrap("failed to get revision", err)
+	}
+
+	return revision, nil
+}
+
+// RevertEvent is UpdateEvent recorded as a revert in the history.
+func (r *EventRepo) RevertEvent(ctx context.Context, event domain.Event) (int, error) {
+	return r.updateEvent(ctx, event, domain.HistoryRevert)
+}
+
+func scanRevisions(rows pgx.Rows) ([]domain.EventRevision, error) {
+	var revisions []domain.EventRevision
+	for rows.Next() {
+		var revision domain.EventRevision
+		if err := rows.Scan(
+			&revision.ID,
+			&revision.EventID,
+			&revision.Action,
+			&revision.ActorID,
+			&revision.Version,
+			&revision.Before,
+			&revision.After,
+			&revision.Changes,
+			&revision.RequestID,
+			&revision.CreatedAt,
+		); err != nil {
+			return nil, errutils.Wrap("failed to scan", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("rows iteration error", err)
+	}
+
+	return revisions, nil
+}
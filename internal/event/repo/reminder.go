@@ -0,0 +1,81 @@
+package repo
+
+import (
+	"context"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"time"
+)
+
+// GetPendingReminders returns the unsent reminders that are due after now,
+// soonest first.
+func (r *EventRepo) GetPendingReminders(ctx context.Context, now time.Time) ([]domain.Event, error) {
+	query := `
+		SELECT id, user_id, calendar_id, event_date, starts_at, ends_at, description, remind_at, sent, created_at, updated_at, version
+		FROM events
+		WHERE NOT sent AND deleted_at IS NULL AND remind_at >= $1
+		ORDER BY remind_at;
+	`
+
+	events, err := r.getReminders(ctx, query, now)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get pending reminders", err)
+	}
+
+	return events, nil
+}
+
+// GetOverdueReminders returns the reminders that were due before the given
+// time but have not been sent, most recent first. Failed sends are not
+// recorded, so these may have failed or still be on their way.
+func (r *EventRepo) GetOverdueReminders(ctx context.Context, before time.Time) ([]domain.Event, error) {
+	query := `
+		SELECT id, user_id, calendar_id, event_date, starts_at, ends_at, description, remind_at, sent, created_at, updated_at, version
+		FROM events
+		WHERE NOT sent AND deleted_at IS NULL AND remind_at < $1
+		ORDER BY remind_at DESC;
+	`
+
+	events, err := r.getReminders(ctx, query, before)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get overdue reminders", err)
+	}
+
+	return events, nil
+}
+
+func (r *EventRepo) getReminders(ctx context.Context, query string, now time.Time) ([]domain.Event, error) {
+	rows, err := r.conn(ctx).Query(ctx, query, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.Event
+	for rows.Next() {
+		var event domain.Event
+		if err := rows.Scan(
+			&event.ID,
+			&event.UserID,
+			&event.CalendarID,
+			&event.Date,
+			&event.StartsAt,
+			&event.EndsAt,
+			&event.Description,
+			&event.RemindAt,
+			&event.Sent,
+			&event.CreatedAt,
+			&event.UpdatedAt,
+			&event.Version,
+		); err != nil {
+			return nil, errutils.Wrap("failed to scan", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("rows iteration error", err)
+	}
+
+	return events, nil
+}
@@ -6,13 +6,19 @@ import (
 	"errors"
 	"github.com/google/uuid"
 	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/db"
 	"github.com/ilam072/event-calendar/pkg/errutils"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"time"
 )
 
 var (
-	ErrEventNotFound = errors.New("event not found")
+	ErrEventNotFound    = errors.New("event not found")
+	ErrCalendarNotFound = errors.New("calendar not found")
+	ErrReadOnly         = errors.New("read-only calendar access")
+	ErrAttendeeNotFound = errors.New("attendee not found")
+	ErrVersionMismatch  = errors.New("event version mismatch")
+	ErrRevisionNotFound = errors.New("revision not found")
 )
 
 type EventRepo struct {
@@ -23,16 +29,54 @@ func NewEventRepo(db *pgxpool.Pool) *EventRepo {
 	return &EventRepo{db: db}
 }
 
+// conn makes the queries join the transaction in ctx, if any.
+func (r *EventRepo) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, r.db)
+}
+
+// CreateEvent stores the event in its calendar, or in the user's default
+// calendar when CalendarID is not set. The user needs write access to the
+// calendar through ownership or a share.
 func (r *EventRepo) CreateEvent(ctx context.Context, event domain.Event) (uuid.UUID, error) {
 	query := `
-		INSERT INTO events (user_id, event_date, description, remind_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO events (user_id, calendar_id, event_date, starts_at, ends_at, description, remind_at)
+		SELECT $1::uuid, c.id, $3::date, $6::timestamptz, $7::timestamptz, $4::text, $5::timestamp
+		FROM calendars c
+		JOIN calendar_access a ON a.calendar_id = c.id
+		WHERE a.user_id = $1
+		  AND a.role IN ('owner', 'manager', 'editor')
+		  AND (c.id = $2 OR ($2 IS NULL AND c.user_id = $1 AND c.is_default))
 		RETURNING id;
 	`
 
+	var calendarID *uuid.UUID
+	if event.CalendarID != uuid.Nil {
+		calendarID = &event.CalendarID
+	}
+
 	var ID uuid.UUID
-	if err := r.db.QueryRow(ctx, query, event.UserID, event.Date, event.Description, event.RemindAt).Scan(&ID); err != nil {
-		return uuid.Nil, errutils.Wrap("failed to create user", err)
+	err := r.inTx(ctx, func(ctx context.Context) error {
+		if err := r.conn(ctx).QueryRow(ctx, query, event.UserID, calendarID, event.Date, event.Description, event.RemindAt, event.StartsAt, event.EndsAt).Scan(&ID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				if calendarID != nil {
+					if role, err := r.calendarRole(ctx, *calendarID, event.UserID); err == nil && role != "" {
+						return errutils.Wrap("failed to create event", ErrReadOnly)
+					}
+				}
+				return errutils.Wrap("failed to create event", ErrCalendarNotFound)
+			}
+			return errutils.Wrap("failed to create event", err)
+		}
+
+		created, err := r.GetEventByID(ctx, ID)
+		if err != nil {
+			return err
+		}
+
+		return r.recordHistory(ctx, domain.HistoryCreate, event.UserID, nil, &created)
+	})
+	if err != nil {
+		return uuid.Nil, err
 	}
 
 	return ID, nil
@@ -41,14 +85,14 @@ func (r *EventRepo) CreateEvent(ctx context.Context, event domain.Event) (uuid.U
 
 func (r *EventRepo) GetEventByID(ctx context.Context, eventID uuid.UUID) (domain.Event, error) {
 	query := `
-		SELECT id, user_id, event_date, description, remind_at, sent, created_at, updated_at
+		SELECT id, user_id, calendar_id, event_date, starts_at, ends_at, description, remind_at, sent, created_at, updated_at, version, deleted_at
 		FROM events
 		WHERE id = $1;
 	`
 
 	var event domain.Event
-	err := r.db.QueryRow(ctx, query, eventID).
-		Scan(&event.ID, &event.UserID, &event.Date, &event.Description, &event.RemindAt, &event.Sent, &event.CreatedAt, &event.UpdatedAt)
+	err := r.conn(ctx).QueryRow(ctx, query, eventID).
+		Scan(&event.ID, &event.UserID, &event.CalendarID, &event.Date, &event.StartsAt, &event.EndsAt, &event.Description, &event.RemindAt, &event.Sent, &event.CreatedAt, &event.UpdatedAt, &event.Version, &event.DeletedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.Event{}, errutils.Wrap("failed to get event", ErrEventNotFound)
@@ -60,67 +104,203 @@ func (r *EventRepo) GetEventByID(ctx context.Context, eventID uuid.UUID) (domain
 
 }
 
-func (r *EventRepo) UpdateEvent(ctx context.Context, event domain.Event) error {
+// GetEvent returns the event if the user can see it through calendar access
+// or an accepted invitation.
+func (r *EventRepo) GetEvent(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) (domain.Event, error) {
+	query := `
+		SELECT id, user_id, calendar_id, event_date, starts_at, ends_at, description, remind_at, sent, created_at, updated_at, version
+		FROM events
+		WHERE id = $1 AND deleted_at IS NULL
+		  AND (calendar_id IN (SELECT calendar_id FROM calendar_access WHERE user_id = $2)
+		    OR id IN (SELECT event_id FROM event_attendees WHERE user_id = $2 AND status = 'accepted'));
+	`
+
+	var event domain.Event
+	err := r.conn(ctx).QueryRow(ctx, query, eventID, userID).
+		Scan(&event.ID, &event.UserID, &event.CalendarID, &event.Date, &event.StartsAt, &event.EndsAt, &event.Description, &event.RemindAt, &event.Sent, &event.CreatedAt, &event.UpdatedAt, &event.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Event{}, errutils.Wrap("failed to get event", ErrEventNotFound)
+		}
+		return domain.Event{}, errutils.Wrap("failed to get event", err)
+	}
+
+	return event, nil
+}
+
+// UpdateEvent keeps the event in its calendar unless CalendarID is set, in
+// which case the event is moved to that calendar. The user needs write access
+// to both calendars. If Version is set, the event is only updated if it has
+// not changed since that version. Returns the new version.
+func (r *EventRepo) UpdateEvent(ctx context.Context, event domain.Event) (int, error) {
+	return r.updateEvent(ctx, event, domain.HistoryUpdate)
+}
+
+func (r *EventRepo) updateEvent(ctx context.Context, event domain.Event, action string) (int, error) {
+	var calendarID *uuid.UUID
+	if event.CalendarID != uuid.Nil {
+		calendarID = &event.CalendarID
+
+		role, err := r.calendarRole(ctx, event.CalendarID, event.UserID)
+		if err != nil {
+			return 0, errutils.Wrap("failed to check calendar", err)
+		}
+
+		if role == "" {
+			return 0, ErrCalendarNotFound
+		}
+		if !domain.CanWriteEvents(role) {
+			return 0, ErrReadOnly
+		}
+	}
+
 	query := `
         UPDATE events
         SET event_date = $1,
         	description = $2,
         	remind_at = $3,
+        	sent = sent AND remind_at IS NOT DISTINCT FROM $3,
+        	calendar_id = COALESCE($6, calendar_id),
+        	starts_at = $7,
+        	ends_at = $8,
+        	version = version + 1,
         	updated_at = now()
-        WHERE id = $4 AND user_id = $5;
+        WHERE id = $4 AND calendar_id IN (
+        	SELECT calendar_id FROM calendar_access
+        	WHERE user_id = $5 AND role IN ('owner', 'manager', 'editor')
+        ) AND ($9 = 0 OR version = $9) AND deleted_at IS NULL
+        RETURNING version;
     `
 
-	res, err := r.db.Exec(
-		ctx,
-		query,
-		event.Date,
-		event.Description,
-		event.RemindAt,
-		event.ID,
-		event.UserID,
-	)
+	var version int
+	err := r.inTx(ctx, func(ctx context.Context) error {
+		before, err := r.lockEvent(ctx, event.ID)
+		if err != nil {
+			if errors.Is(err, ErrEventNotFound) {
+				return err
+			}
+			return errutils.Wrap("failed to update event", err)
+		}
+
+		err = r.conn(ctx).QueryRow(
+			ctx,
+			query,
+			event.Date,
+			event.Description,
+			event.RemindAt,
+			event.ID,
+			event.UserID,
+			calendarID,
+			event.StartsAt,
+			event.EndsAt,
+			event.Version,
+		).Scan(&version)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return r.writeErr(ctx, event.ID, event.UserID)
+			}
+			return errutils.Wrap("failed to update event", err)
+		}
+
+		after, err := r.GetEventByID(ctx, event.ID)
+		if err != nil {
+			return err
+		}
+
+		return r.recordHistory(ctx, action, event.UserID, &before, &after)
+	})
 	if err != nil {
-		return errutils.Wrap("failed to update event", err)
+		return 0, err
 	}
 
-	if res.RowsAffected() == 0 {
-		return ErrEventNotFound
-	}
+	return version, nil
+}
 
-	return nil
+// DeleteEvent moves the event to the trash, only if it is still at version
+// when version is not 0.
+func (r *EventRepo) DeleteEvent(ctx context.Context, eventID uuid.UUID, userID uuid.UUID, version int) error {
+	query := `
+		UPDATE events
+		SET deleted_at = now(), version = version + 1, updated_at = now()
+		WHERE id = $1 AND calendar_id IN (
+			SELECT calendar_id FROM calendar_access
+			WHERE user_id = $2 AND role IN ('owner', 'manager', 'editor')
+		) AND ($3 = 0 OR version = $3) AND deleted_at IS NULL
+		RETURNING version, deleted_at;
+	`
+
+	return r.inTx(ctx, func(ctx context.Context) error {
+		before, err := r.lockEvent(ctx, eventID)
+		if err != nil {
+			if errors.Is(err, ErrEventNotFound) {
+				return err
+			}
+			return errutils.Wrap("failed to delete event", err)
+		}
+
+		after := before
+		err = r.conn(ctx).QueryRow(ctx, query, eventID, userID, version).Scan(&after.Version, &after.DeletedAt)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return r.writeErr(ctx, eventID, userID)
+			}
+			return errutils.Wrap("failed to delete event", err)
+		}
+
+		return r.recordHistory(ctx, domain.HistoryDelete, userID, &before, &after)
+	})
 }
 
-func (r *EventRepo) DeleteEvent(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) error {
-	query := `DELETE FROM events WHERE id = $1 AND user_id = $2;`
+// calendarRole returns the user's role in the calendar, or an empty string
+// if the calendar does not exist or is not shared with the user.
+func (r *EventRepo) calendarRole(ctx context.Context, calendarID uuid.UUID, userID uuid.UUID) (string, error) {
+	query := `SELECT role FROM calendar_access WHERE calendar_id = $1 AND user_id = $2;`
 
-	res, err := r.db.Exec(ctx, query, eventID, userID)
-	if err != nil {
-		return errutils.Wrap("failed to delete event", err)
+	var role string
+	if err := r.conn(ctx).QueryRow(ctx, query, calendarID, userID).Scan(&role); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
 	}
 
-	if res.RowsAffected() == 0 {
-		return ErrEventNotFound
+	return role, nil
+}
+
+// writeErr explains why a write matched no rows: the user cannot see the
+// event, can only read it, or the event changed since the expected version.
+func (r *EventRepo) writeErr(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) error {
+	if err := r.checkEventWritable(ctx, eventID, userID); err != nil {
+		return err
 	}
 
-	return nil
+	return ErrVersionMismatch
 }
 
-func (r *EventRepo) GetEventsForDay(ctx context.Context, userID uuid.UUID, date time.Time) ([]domain.Event, error) {
+func (r *EventRepo) GetEventsForDay(ctx context.Context, userID uuid.UUID, date time.Time, calendarIDs []uuid.UUID) ([]domain.Event, error) {
 	query := `
 		SELECT 
 		    id, 
 		    user_id, 
+		    calendar_id,
 		    event_date, 
+		    starts_at,
+		    ends_at,
 		    description, 
 		    remind_at, 
 		    sent,
 		    created_at,
-		    updated_at
+		    updated_at,
+		    version
 		FROM events
-		WHERE user_id = $1 AND event_date = $2
+		WHERE deleted_at IS NULL
+		  AND (calendar_id IN (SELECT calendar_id FROM calendar_access WHERE user_id = $1)
+		    OR id IN (SELECT event_id FROM event_attendees WHERE user_id = $1 AND status = 'accepted'))
+		  AND event_date = $2
+		  AND (COALESCE(cardinality($3::uuid[]), 0) = 0 OR calendar_id = ANY($3))
 	`
 
-	rows, err := r.db.Query(ctx, query, userID, date)
+	rows, err := r.conn(ctx).Query(ctx, query, userID, date, calendarIDs)
 	if err != nil {
 		return nil, errutils.Wrap("failed to get events for day", err)
 	}
@@ -132,12 +312,16 @@ func (r *EventRepo) GetEventsForDay(ctx context.Context, userID uuid.UUID, date
 		if err := rows.Scan(
 			&event.ID,
 			&event.UserID,
+			&event.CalendarID,
 			&event.Date,
+			&event.StartsAt,
+			&event.EndsAt,
 			&event.Description,
 			&event.RemindAt,
 			&event.Sent,
 			&event.CreatedAt,
 			&event.UpdatedAt,
+			&event.Version,
 		); err != nil {
 			return nil, errutils.Wrap("failed to scan", err)
 		}
@@ -147,24 +331,32 @@ func (r *EventRepo) GetEventsForDay(ctx context.Context, userID uuid.UUID, date
 	return events, nil
 }
 
-func (r *EventRepo) GetEventsForWeek(ctx context.Context, userID uuid.UUID, start time.Time) ([]domain.Event, error) {
+func (r *EventRepo) GetEventsForWeek(ctx context.Context, userID uuid.UUID, start time.Time, calendarIDs []uuid.UUID) ([]domain.Event, error) {
 	end := start.AddDate(0, 0, 7)
 
 	query := `
 		SELECT 
 		    id, 
 		    user_id, 
+		    calendar_id,
 		    event_date, 
+		    starts_at,
+		    ends_at,
 		    description, 
 		    remind_at, 
 		    sent,
 		    created_at,
-		    updated_at
+		    updated_at,
+		    version
 		FROM events
-		WHERE user_id = $1 AND event_date >= $2 AND event_date < $3
+		WHERE deleted_at IS NULL
+		  AND (calendar_id IN (SELECT calendar_id FROM calendar_access WHERE user_id = $1)
+		    OR id IN (SELECT event_id FROM event_attendees WHERE user_id = $1 AND status = 'accepted'))
+		  AND event_date >= $2 AND event_date < $3
+		  AND (COALESCE(cardinality($4::uuid[]), 0) = 0 OR calendar_id = ANY($4))
 	`
 
-	rows, err := r.db.Query(ctx, query, userID, start, end)
+	rows, err := r.conn(ctx).Query(ctx, query, userID, start, end, calendarIDs)
 	if err != nil {
 		return nil, errutils.Wrap("failed to get events for week", err)
 	}
@@ -176,12 +368,16 @@ func (r *EventRepo) GetEventsForWeek(ctx context.Context, userID uuid.UUID, star
 		if err := rows.Scan(
 			&event.ID,
 			&event.UserID,
+			&event.CalendarID,
 			&event.Date,
+			&event.StartsAt,
+			&event.EndsAt,
 			&event.Description,
 			&event.RemindAt,
 			&event.Sent,
 			&event.CreatedAt,
 			&event.UpdatedAt,
+			&event.Version,
 		); err != nil {
 			return nil, errutils.Wrap("failed to scan", err)
 		}
@@ -191,24 +387,32 @@ func (r *EventRepo) GetEventsForWeek(ctx context.Context, userID uuid.UUID, star
 	return events, nil
 }
 
-func (r *EventRepo) GetEventsForMonth(ctx context.Context, userID uuid.UUID, start time.Time) ([]domain.Event, error) {
+func (r *EventRepo) GetEventsForMonth(ctx context.Context, userID uuid.UUID, start time.Time, calendarIDs []uuid.UUID) ([]domain.Event, error) {
 	end := start.AddDate(0, 1, 0)
 
 	query := `
 		SELECT 
 		    id, 
 		    user_id, 
+		    calendar_id,
 		    event_date, 
+		    starts_at,
+		    ends_at,
 		    description, 
 		    remind_at, 
 		    sent,
 		    created_at,
-		    updated_at
+		    updated_at,
+		    version
 		FROM events
-		WHERE user_id = $1 AND event_date >= $2 AND event_date < $3
+		WHERE deleted_at IS NULL
+		  AND (calendar_id IN (SELECT calendar_id FROM calendar_access WHERE user_id = $1)
+		    OR id IN (SELECT event_id FROM event_attendees WHERE user_id = $1 AND status = 'accepted'))
+		  AND event_date >= $2 AND event_date < $3
+		  AND (COALESCE(cardinality($4::uuid[]), 0) = 0 OR calendar_id = ANY($4))
 	`
 
-	rows, err := r.db.Query(ctx, query, userID, start, end)
+	rows, err := r.conn(ctx).Query(ctx, query, userID, start, end, calendarIDs)
 	if err != nil {
 		return nil, errutils.Wrap("failed to get events for month", err)
 	}
@@ -220,12 +424,74 @@ func (r *EventRepo) GetEventsForMonth(ctx context.Context, userID uuid.UUID, sta
 		if err := rows.Scan(
 			&event.ID,
 			&event.UserID,
+			&event.CalendarID,
+			&event.Date,
+			&event.StartsAt,
+			&event.EndsAt,
+			&event.Description,
+			&event.RemindAt,
+			&event.Sent,
+			&event.CreatedAt,
+			&event.UpdatedAt,
+			&event.Version,
+		); err != nil {
+			return nil, errutils.Wrap("failed to scan", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// GetOverlappingEvents returns the timed events the user can see that
+// overlap [startsAt, endsAt), leaving out excludeID. All-day events do not
+// take part in overlaps.
+func (r *EventRepo) GetOverlappingEvents(ctx context.Context, userID uuid.UUID, startsAt time.Time, endsAt time.Time, excludeID uuid.UUID) ([]domain.Event, error) {
+	query := `
+		SELECT 
+		    id, 
+		    user_id, 
+		    calendar_id,
+		    event_date, 
+		    starts_at,
+		    ends_at,
+		    description, 
+		    remind_at, 
+		    sent,
+		    created_at,
+		    updated_at,
+		    version
+		FROM events
+		WHERE deleted_at IS NULL
+		  AND (calendar_id IN (SELECT calendar_id FROM calendar_access WHERE user_id = $1)
+		    OR id IN (SELECT event_id FROM event_attendees WHERE user_id = $1 AND status = 'accepted'))
+		  AND starts_at < $3 AND ends_at > $2
+		  AND id <> $4
+		ORDER BY starts_at
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, userID, startsAt, endsAt, excludeID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get overlapping events", err)
+	}
+	defer rows.Close()
+
+	var events []domain.Event
+	for rows.Next() {
+		var event domain.Event
+		if err := rows.Scan(
+			&event.ID,
+			&event.UserID,
+			&event.CalendarID,
 			&event.Date,
+			&event.StartsAt,
+			&event.EndsAt,
 			&event.Description,
 			&event.RemindAt,
 			&event.Sent,
 			&event.CreatedAt,
 			&event.UpdatedAt,
+			&event.Version,
 		); err != nil {
 			return nil, errutils.Wrap("failed to scan", err)
 		}
@@ -235,18 +501,55 @@ func (r *EventRepo) GetEventsForMonth(ctx context.Context, userID uuid.UUID, sta
 	return events, nil
 }
 
-func (r *EventRepo) MarkReminderSent(ctx context.Context, eventID uuid.UUID) error {
-	query := `UPDATE events SET sent = true, updated_at = now() WHERE id = $1;`
-	if _, err := r.db.Exec(ctx, query, eventID); err != nil {
-		return errutils.Wrap("failed to set sent to 'true'", err)
+// ClaimReminder marks the reminder of the event as sent, as long as it was
+// not and the event is still at version, and reports whether it did. Only
+// the caller that claims a reminder sends it, so that replicas and
+// calendarctl never send the same reminder twice.
+func (r *EventRepo) ClaimReminder(ctx context.Context, eventID uuid.UUID, version int) (bool, error) {
+	query := `
+		UPDATE events SET sent = true, updated_at = now()
+		WHERE id = $1 AND NOT sent AND version = $2
+		RETURNING id;
+	`
+
+	var id uuid.UUID
+	if err := r.conn(ctx).QueryRow(ctx, query, eventID, version).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, errutils.Wrap("failed to claim reminder", err)
+	}
+
+	return true, nil
+}
+
+// ReleaseReminder marks a claimed reminder as unsent again, after sending
+// it failed.
+func (r *EventRepo) ReleaseReminder(ctx context.Context, eventID uuid.UUID, version int) error {
+	query := `UPDATE events SET sent = false, updated_at = now() WHERE id = $1 AND sent AND version = $2;`
+	if _, err := r.conn(ctx).Exec(ctx, query, eventID, version); err != nil {
+		return errutils.Wrap("failed to release reminder", err)
 	}
 	return nil
 }
 
-func (r *EventRepo) ArchiveOldEvents(ctx context.Context) error {
+// CountArchivableEvents returns how many events ArchiveOldEvents would
+// archive now.
+func (r *EventRepo) CountArchivableEvents(ctx context.Context) (int64, error) {
+	query := `SELECT count(*) FROM events WHERE event_date < CURRENT_DATE AND deleted_at IS NULL;`
+
+	var count int64
+	if err := r.conn(ctx).QueryRow(ctx, query).Scan(&count); err != nil {
+		return 0, errutils.Wrap("failed to count archivable events", err)
+	}
+
+	return count, nil
+}
+
+func (r *EventRepo) ArchiveOldEvents(ctx context.Context) (int64, error) {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return errutils.Wrap("failed to begin tx", err)
+		return 0, errutils.Wrap("failed to begin tx", err)
 	}
 
 	defer func() {
@@ -254,26 +557,139 @@ func (r *EventRepo) ArchiveOldEvents(ctx context.Context) error {
 	}()
 
 	query := `
-        INSERT INTO events_archive (id, user_id, event_date, description, archived_at, original_created_at, original_updated_at)
-        SELECT id, user_id, event_date, description, NOW(), created_at, updated_at
+        INSERT INTO events_archive (id, user_id, calendar_id, event_date, starts_at, ends_at, description, archived_at, original_created_at, original_updated_at)
+        SELECT id, user_id, calendar_id, event_date, starts_at, ends_at, description, NOW(), created_at, updated_at
         FROM events
-        WHERE event_date < CURRENT_DATE;
+        WHERE event_date < CURRENT_DATE AND deleted_at IS NULL;
     `
 	if _, err = tx.Exec(ctx, query); err != nil {
-		return errutils.Wrap("failed to archive events", err)
+		return 0, errutils.Wrap("failed to archive events", err)
 	}
 
 	query = `
-        DELETE FROM events 
-        WHERE event_date < CURRENT_DATE;
+        INSERT INTO event_history (event_id, action, version, before)
+        SELECT id, 'archive', version, ` + snapshotSQL + `
+        FROM events
+        WHERE event_date < CURRENT_DATE AND deleted_at IS NULL;
     `
 	if _, err = tx.Exec(ctx, query); err != nil {
-		return errutils.Wrap("failed to delete old events", err)
+		return 0, errutils.Wrap("failed to record history", err)
+	}
+
+	query = `
+        DELETE FROM events 
+        WHERE event_date < CURRENT_DATE AND deleted_at IS NULL;
+    `
+	res, err := tx.Exec(ctx, query)
+	if err != nil {
+		return 0, errutils.Wrap("failed to delete old events", err)
 	}
 
 	if err = tx.Commit(ctx); err != nil {
-		return errutils.Wrap("failed to commit tx", err)
+		return 0, errutils.Wrap("failed to commit tx", err)
 	}
 
-	return nil
+	return res.RowsAffected(), nil
+}
+
+// GetUserEvents returns every event the user created, including those in
+// the trash, for the personal data export.
+func (r *EventRepo) GetUserEvents(ctx context.Context, userID uuid.UUID) ([]domain.Event, error) {
+	query := `
+		SELECT 
+		    id, 
+		    user_id, 
+		    calendar_id,
+		    event_date, 
+		    starts_at,
+		    ends_at,
+		    description, 
+		    remind_at, 
+		    sent,
+		    created_at,
+		    updated_at,
+		    version,
+		    deleted_at
+		FROM events
+		WHERE user_id = $1
+		ORDER BY event_date, created_at
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, userID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get user events", err)
+	}
+	defer rows.Close()
+
+	var events []domain.Event
+	for rows.Next() {
+		var event domain.Event
+		if err := rows.Scan(
+			&event.ID,
+			&event.UserID,
+			&event.CalendarID,
+			&event.Date,
+			&event.StartsAt,
+			&event.EndsAt,
+			&event.Description,
+			&event.RemindAt,
+			&event.Sent,
+			&event.CreatedAt,
+			&event.UpdatedAt,
+			&event.Version,
+			&event.DeletedAt,
+		); err != nil {
+			return nil, errutils.Wrap("failed to scan", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (r *EventRepo) GetArchivedUserEvents(ctx context.Context, userID uuid.UUID) ([]domain.ArchivedEvent, error) {
+	query := `
+		SELECT 
+		    id, 
+		    user_id, 
+		    calendar_id,
+		    event_date, 
+		    starts_at,
+		    ends_at,
+		    description, 
+		    archived_at,
+		    original_created_at,
+		    original_updated_at
+		FROM events_archive
+		WHERE user_id = $1
+		ORDER BY event_date, archived_at
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, userID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get archived user events", err)
+	}
+	defer rows.Close()
+
+	var events []domain.ArchivedEvent
+	for rows.Next() {
+		var event domain.ArchivedEvent
+		if err := rows.Scan(
+			&event.ID,
+			&event.UserID,
+			&event.CalendarID,
+			&event.Date,
+			&event.StartsAt,
+			&event.EndsAt,
+			&event.Description,
+			&event.ArchivedAt,
+			&event.OriginalCreatedAt,
+			&event.OriginalUpdatedAt,
+		); err != nil {
+			return nil, errutils.Wrap("failed to scan", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
 }
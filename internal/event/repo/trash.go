@@ -0,0 +1,204 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"github.com/ilam072/event-calendar/pkg/requestid"
+	"time"
+)
+
+// GetTrash returns the deleted events of the calendars the user can write
+// to, most recently deleted first.
+func (r *EventRepo) GetTrash(ctx context.Context, userID uuid.UUID) ([]domain.Event, error) {
+	query := `
+		SELECT id, user_id, calendar_id, event_date, starts_at, ends_at, description, remind_at, sent, created_at, updated_at, version, deleted_at
+		FROM events
+		WHERE deleted_at IS NOT NULL AND calendar_id IN (
+			SELECT calendar_id FROM calendar_access
+			WHERE user_id = $1 AND role IN ('owner', 'manager', 'editor')
+		)
+		ORDER BY deleted_at DESC;
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, userID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get trash", err)
+	}
+	defer rows.Close()
+
+	var events []domain.Event
+	for rows.Next() {
+		var event domain.Event
+		if err := rows.Scan(
+			&event.ID,
+			&event.UserID,
+			&event.CalendarID,
+			&event.Date,
+			&event.StartsAt,
+			&event.EndsAt,
+			&event.Description,
+			&event.RemindAt,
+			&event.Sent,
+			&event.CreatedAt,
+			&event.UpdatedAt,
+			&event.Version,
+			&event.DeletedAt,
+		); err != nil {
+			return nil, errutils.Wrap("failed to scan", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("rows iteration error", err)
+	}
+
+	return events, nil
+}
+
+// RestoreEvent takes the event out of the trash and returns it.
+func (r *EventRepo) RestoreEvent(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) (domain.Event, error) {
+	query := `
+		UPDATE events
+		SET deleted_at = NULL, version = version + 1, updated_at = now()
+		WHERE id = $1 AND deleted_at IS NOT NULL AND calendar_id IN (
+			SELECT calendar_id FROM calendar_access
+			WHERE user_id = $2 AND role IN ('owner', 'manager', 'editor')
+		)
+		RETURNING id, user_id, calendar_id, event_date, starts_at, ends_at, description, remind_at, sent, created_at, updated_at, version;
+	`
+
+	var event domain.Event
+	err := r.inTx(ctx, func(ctx context.Context) error {
+		before, err := r.lockEvent(ctx, eventID)
+		if err != nil {
+			return errutils.Wrap("failed to restore event", err)
+		}
+
+		err = r.conn(ctx).QueryRow(ctx, query, eventID, userID).
+			Scan(&event.ID, &event.UserID, &event.CalendarID, &event.Date, &event.StartsAt, &event.EndsAt, &event.Description, &event.RemindAt, &event.Sent, &event.CreatedAt, &event.UpdatedAt, &event.Version)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errutils.Wrap("failed to restore event", ErrEventNotFound)
+			}
+			return errutils.Wrap("failed to restore event", err)
+		}
+
+		return r.recordHistory(ctx, domain.HistoryRestore, userID, &before, &event)
+	})
+	if err != nil {
+		return domain.Event{}, err
+	}
+
+	return event, nil
+}
+
+// TrashCalendarEvents moves every event of a calendar that is about to be
+// deleted to its owner's default calendar and puts it in the trash, so that
+// the events can still be restored. Events already in the trash keep their
+// deletion time.
+func (r *EventRepo) TrashCalendarEvents(ctx context.Context, calendarID uuid.UUID, actorID uuid.UUID) error {
+	idsQuery := `SELECT id FROM events WHERE calendar_id = $1 ORDER BY id FOR UPDATE;`
+
+	query := `
+		UPDATE events
+		SET calendar_id = (
+			SELECT d.id FROM calendars d
+			JOIN calendars c ON c.user_id = d.user_id
+			WHERE c.id = $2 AND d.is_default
+		), deleted_at = COALESCE(deleted_at, now()), version = version + 1, updated_at = now()
+		WHERE id = $1
+		RETURNING calendar_id, version, deleted_at;
+	`
+
+	return r.inTx(ctx, func(ctx context.Context) error {
+		rows, err := r.conn(ctx).Query(ctx, idsQuery, calendarID)
+		if err != nil {
+			return errutils.Wrap("failed to get calendar events", err)
+		}
+
+		var ids []uuid.UUID
+		for rows.Next() {
+			var id uuid.UUID
+			if err = rows.Scan(&id); err != nil {
+				rows.Close()
+				return errutils.Wrap("failed to scan", err)
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return errutils.Wrap("rows iteration error", err)
+		}
+
+		for _, id := range ids {
+			before, err := r.lockEvent(ctx, id)
+			if err != nil {
+				return errutils.Wrap("failed to trash calendar events", err)
+			}
+
+			after := before
+			if err = r.conn(ctx).QueryRow(ctx, query, id, calendarID).Scan(&after.CalendarID, &after.Version, &after.DeletedAt); err != nil {
+				return errutils.Wrap("failed to trash calendar events", err)
+			}
+
+			action := domain.HistoryDelete
+			if before.DeletedAt != nil {
+				action = domain.HistoryUpdate
+			}
+			if err = r.recordHistory(ctx, action, actorID, &before, &after); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// EmptyTrash permanently deletes the trashed events of the calendars the user
+// can write to and returns how many were deleted.
+func (r *EventRepo) EmptyTrash(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query := `
+		WITH purged AS (
+			DELETE FROM events
+			WHERE deleted_at IS NOT NULL AND calendar_id IN (
+				SELECT calendar_id FROM calendar_access
+				WHERE user_id = $1 AND role IN ('owner', 'manager', 'editor')
+			)
+			RETURNING id, version, ` + snapshotSQL + ` AS snapshot
+		)
+		INSERT INTO event_history (event_id, action, actor_id, version, before, request_id)
+		SELECT id, 'purge', $1, version, snapshot, NULLIF($2, '') FROM purged;
+	`
+
+	res, err := r.conn(ctx).Exec(ctx, query, userID, requestid.FromContext(ctx))
+	if err != nil {
+		return 0, errutils.Wrap("failed to empty trash", err)
+	}
+
+	return res.RowsAffected(), nil
+}
+
+// PurgeTrash permanently deletes events that have been in the trash for
+// longer than retention.
+func (r *EventRepo) PurgeTrash(ctx context.Context, retention time.Duration) error {
+	query := `
+		WITH purged AS (
+			DELETE FROM events
+			WHERE deleted_at < now() - make_interval(secs => $1)
+			RETURNING id, version, ` + snapshotSQL + ` AS snapshot
+		)
+		INSERT INTO event_history (event_id, action, version, before)
+		SELECT id, 'purge', version, snapshot FROM purged;
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, retention.Seconds()); err != nil {
+		return errutils.Wrap("failed to purge trash", err)
+	}
+
+	return nil
+}
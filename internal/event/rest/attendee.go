@@ -0,0 +1,219 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/response"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"net/http"
+)
+
+//go:generate mockgen -source=attendee.go -destination=../mocks/attendee_rest_mocks.go -package=mocks
+type Attendee interface {
+	AddAttendees(ctx context.Context, req dto.AddAttendeesRequest, eventID uuid.UUID, userID uuid.UUID) (dto.GetAttendeesResponse, error)
+	RemoveAttendee(ctx context.Context, eventID uuid.UUID, attendeeID uuid.UUID, userID uuid.UUID) error
+	Respond(ctx context.Context, req dto.RSVPRequest, eventID uuid.UUID, userID uuid.UUID) error
+	RespondByLink(ctx context.Context, token string, req dto.RSVPRequest) error
+}
+
+type AttendeeHandler struct {
+	attendee  Attendee
+	validator Validator
+	logger    logger.Logger
+}
+
+func NewAttendeeHandler(attendee Attendee, validator Validator, logger logger.Logger) *AttendeeHandler {
+	return &AttendeeHandler{attendee: attendee, validator: validator, logger: logger}
+}
+
+func (h *AttendeeHandler) AddAttendees(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to parse event id into uuid")
+		response.BadRequest(c, "event id must be UUID format")
+		return
+	}
+
+	var req dto.AddAttendeesRequest
+	if err = c.BindJSON(&req); err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to bind add attendees json")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err = h.validator.Validate(req); err != nil {
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	attendees, err := h.attendee.AddAttendees(c.Request.Context(), req, eventID, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEventNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			response.Forbidden(c, "read-only access to calendar")
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("event_id", eventID.String()).Msg("failed to add attendees")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, attendees)
+}
+
+func (h *AttendeeHandler) RemoveAttendee(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to parse event id into uuid")
+		response.BadRequest(c, "event id must be UUID format")
+		return
+	}
+
+	attendeeID, err := uuid.Parse(c.Param("attendee_id"))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to parse attendee id into uuid")
+		response.BadRequest(c, "attendee id must be UUID format")
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	if err = h.attendee.RemoveAttendee(c.Request.Context(), eventID, attendeeID, userID); err != nil {
+		if errors.Is(err, domain.ErrEventNotFound) || errors.Is(err, domain.ErrAttendeeNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			response.Forbidden(c, "read-only access to calendar")
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("attendee_id", attendeeID.String()).Msg("failed to remove attendee")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Respond lets a signed-in invitee accept, decline or tentatively accept.
+func (h *AttendeeHandler) Respond(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to parse event id into uuid")
+		response.BadRequest(c, "event id must be UUID format")
+		return
+	}
+
+	var req dto.RSVPRequest
+	if err = c.BindJSON(&req); err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to bind rsvp json")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err = h.validator.Validate(req); err != nil {
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	if err = h.attendee.Respond(c.Request.Context(), req, eventID, userID); err != nil {
+		if errors.Is(err, domain.ErrAttendeeNotFound) {
+			response.NotFound(c)
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("event_id", eventID.String()).Msg("failed to respond to invitation")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// rsvpButtons labels the confirmation button of the RSVP page by the answer.
+var rsvpButtons = map[string]string{
+	domain.AttendeeStatusAccepted:  "Accept",
+	domain.AttendeeStatusTentative: "Accept tentatively",
+	domain.AttendeeStatusDeclined:  "Decline",
+}
+
+// RSVPPage is where the RSVP links from invitation emails lead. The answer is
+// only recorded once the page is submitted to RespondByLink.
+func (h *AttendeeHandler) RSVPPage(c *gin.Context) {
+	req := dto.RSVPRequest{Status: c.Query("status")}
+	if err := h.validator.Validate(req); err != nil {
+		response.Page(c, http.StatusBadRequest, "Invalid link", "This link is invalid or has expired.")
+		return
+	}
+
+	response.ConfirmPage(c,
+		"Respond to the invitation",
+		"Confirm your answer to the event invitation.",
+		rsvpButtons[req.Status], c.Request.URL.Path,
+		map[string]string{"token": c.Query("token"), "status": req.Status},
+	)
+}
+
+// RespondByLink records the answer submitted from RSVPPage. It does not
+// require authentication, the signed token identifies the attendee.
+func (h *AttendeeHandler) RespondByLink(c *gin.Context) {
+	req := dto.RSVPRequest{Status: c.PostForm("status")}
+	if err := h.validator.Validate(req); err != nil {
+		response.Page(c, http.StatusBadRequest, "Invalid link", "This link is invalid or has expired.")
+		return
+	}
+
+	if err := h.attendee.RespondByLink(c.Request.Context(), c.PostForm("token"), req); err != nil {
+		if errors.Is(err, domain.ErrInvalidRSVPLink) {
+			response.Page(c, http.StatusBadRequest, "Invalid link", "This link is invalid or has expired.")
+			return
+		}
+		if errors.Is(err, domain.ErrAttendeeNotFound) {
+			response.Page(c, http.StatusNotFound, "Invitation not found", "This invitation was withdrawn or the event was deleted.")
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Msg("failed to respond to invitation by link")
+		response.Page(c, http.StatusInternalServerError, "Something went wrong", "Your answer could not be recorded, try again later.")
+		return
+	}
+
+	response.Page(c, http.StatusOK, "Answer recorded", "Thank you, your answer was sent to the organizer.")
+}
+
+func (h *AttendeeHandler) getUserData(c *gin.Context) (uuid.UUID, bool) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		h.logger.Error().Ctx(c.Request.Context()).Msg("failed to get user_id from ctx")
+		response.Unauthorized(c, "missing user_id in token")
+		return uuid.Nil, false
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Any("user_id", userID).Msg("failed to parse user id into uuid")
+		response.Unauthorized(c, "user_id must be uuid format")
+		return uuid.Nil, false
+	}
+
+	return userUUID, true
+}
@@ -0,0 +1,191 @@
+package rest_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/event/mocks"
+	"github.com/ilam072/event-calendar/internal/event/rest"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAddAttendees_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventID, userID := uuid.New(), uuid.New()
+
+	mockAttendee := mocks.NewMockAttendee(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	req := dto.AddAttendeesRequest{Emails: []string{"bob@example.com"}}
+
+	mockValidator.EXPECT().Validate(req).Return(nil)
+	mockAttendee.EXPECT().
+		AddAttendees(gomock.Any(), req, eventID, userID).
+		Return(dto.GetAttendeesResponse{Attendees: []dto.Attendee{{ID: uuid.New(), Email: "bob@example.com", Status: "needs_action"}}}, nil)
+
+	h := rest.NewAttendeeHandler(mockAttendee, mockValidator, log)
+	r := routerWithAttendeeHandler(h, userID.String())
+
+	body := `{"emails":["bob@example.com"]}`
+	httpReq := httptest.NewRequest("POST", "/events/"+eventID.String()+"/attendees", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, httpReq)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"needs_action"`)
+}
+
+func TestAddAttendees_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAttendee := mocks.NewMockAttendee(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
+	mockAttendee.EXPECT().
+		AddAttendees(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.GetAttendeesResponse{}, domain.ErrForbidden)
+
+	h := rest.NewAttendeeHandler(mockAttendee, mockValidator, log)
+	r := routerWithAttendeeHandler(h, uuid.New().String())
+
+	body := `{"emails":["bob@example.com"]}`
+	httpReq := httptest.NewRequest("POST", "/events/"+uuid.New().String()+"/attendees", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, httpReq)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestRespond_NotInvited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAttendee := mocks.NewMockAttendee(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	mockValidator.EXPECT().Validate(dto.RSVPRequest{Status: "accepted"}).Return(nil)
+	mockAttendee.EXPECT().
+		Respond(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.ErrAttendeeNotFound)
+
+	h := rest.NewAttendeeHandler(mockAttendee, mockValidator, log)
+	r := routerWithAttendeeHandler(h, uuid.New().String())
+
+	httpReq := httptest.NewRequest("PUT", "/events/"+uuid.New().String()+"/rsvp", bytes.NewBufferString(`{"status":"accepted"}`))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, httpReq)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRSVPPage_DoesNotRespond(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockValidator := mocks.NewMockValidator(ctrl)
+	mockValidator.EXPECT().Validate(dto.RSVPRequest{Status: "accepted"}).Return(nil)
+
+	// the attendee mock fails the test if the answer is recorded
+	h := rest.NewAttendeeHandler(mocks.NewMockAttendee(ctrl), mockValidator, log)
+	r := routerWithAttendeeHandler(h, "")
+
+	httpReq := httptest.NewRequest("GET", "/rsvp?token=signed&status=accepted", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, httpReq)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `method="post"`)
+	assert.Contains(t, rec.Body.String(), `value="signed"`)
+}
+
+func TestRespondByLink_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAttendee := mocks.NewMockAttendee(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	req := dto.RSVPRequest{Status: "declined"}
+
+	mockValidator.EXPECT().Validate(req).Return(nil)
+	mockAttendee.EXPECT().RespondByLink(gomock.Any(), "signed", req).Return(nil)
+
+	h := rest.NewAttendeeHandler(mockAttendee, mockValidator, log)
+	r := routerWithAttendeeHandler(h, "")
+
+	httpReq := httptest.NewRequest("POST", "/rsvp", strings.NewReader("token=signed&status=declined"))
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, httpReq)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRespondByLink_InvalidLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAttendee := mocks.NewMockAttendee(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
+	mockAttendee.EXPECT().
+		RespondByLink(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.ErrInvalidRSVPLink)
+
+	h := rest.NewAttendeeHandler(mockAttendee, mockValidator, log)
+	r := routerWithAttendeeHandler(h, "")
+
+	httpReq := httptest.NewRequest("POST", "/rsvp", strings.NewReader("token=forged&status=accepted"))
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, httpReq)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestRespondByLink_InvalidStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockValidator := mocks.NewMockValidator(ctrl)
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(errors.New("bad status"))
+
+	h := rest.NewAttendeeHandler(mocks.NewMockAttendee(ctrl), mockValidator, log)
+	r := routerWithAttendeeHandler(h, "")
+
+	httpReq := httptest.NewRequest("GET", "/rsvp?token=signed&status=maybe", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, httpReq)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func routerWithAttendeeHandler(h *rest.AttendeeHandler, userID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.GET("/rsvp", h.RSVPPage)
+	r.POST("/rsvp", h.RespondByLink)
+
+	api := r.Group("", func(c *gin.Context) {
+		c.Set("user_id", userID)
+	})
+	api.POST("/events/:id/attendees", h.AddAttendees)
+	api.DELETE("/events/:id/attendees/:attendee_id", h.RemoveAttendee)
+	api.PUT("/events/:id/rsvp", h.Respond)
+
+	return r
+}
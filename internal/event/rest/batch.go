@@ -0,0 +1,115 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/response"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"net/http"
+)
+
+//go:generate mockgen -source=batch.go -destination=../mocks/batch_rest_mocks.go -package=mocks
+type Batch interface {
+	Batch(ctx context.Context, req dto.BatchRequest, userID uuid.UUID) (dto.BatchResponse, error)
+}
+
+type BatchHandler struct {
+	batch     Batch
+	validator Validator
+	logger    logger.Logger
+}
+
+func NewBatchHandler(batch Batch, validator Validator, logger logger.Logger) *BatchHandler {
+	return &BatchHandler{batch: batch, validator: validator, logger: logger}
+}
+
+// Batch serves POST /events:batch. Gin cannot route a literal colon, so the
+// custom method arrives as the "action" path param.
+func (h *BatchHandler) Batch(c *gin.Context) {
+	if c.Param("action") != ":batch" {
+		response.NotFound(c)
+		return
+	}
+
+	var req dto.BatchRequest
+	if err := c.BindJSON(&req); err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to bind batch json")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.batch.Batch(c.Request.Context(), req, userID)
+	if err != nil {
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("user_id", userID.String()).Msg("failed to run batch")
+		response.InternalServerError(c)
+		return
+	}
+
+	for i := range resp.Results {
+		h.setResultStatus(c.Request.Context(), &resp.Results[i])
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// setResultStatus reports the outcome of an operation the way the single
+// event endpoints would.
+func (h *BatchHandler) setResultStatus(ctx context.Context, result *dto.BatchResult) {
+	err := result.Err
+	if err == nil {
+		result.Status = http.StatusOK
+		return
+	}
+
+	status, code, message := http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error, try again later"
+	switch {
+	case errors.Is(err, domain.ErrBatchRolledBack):
+		status, code, message = http.StatusFailedDependency, "ROLLED_BACK", err.Error()
+	case errors.Is(err, domain.ErrEventConflict):
+		status, code, message = http.StatusConflict, "EVENT_CONFLICT", "event overlaps existing events"
+	case errors.Is(err, domain.ErrVersionMismatch):
+		status, code, message = http.StatusPreconditionFailed, "PRECONDITION_FAILED", "event has been modified, fetch it again and retry"
+	case errors.Is(err, domain.ErrEventNotFound), errors.Is(err, domain.ErrCalendarNotFound):
+		status, code, message = http.StatusNotFound, "NOT_FOUND", "resource not found"
+	case errors.Is(err, domain.ErrForbidden):
+		status, code, message = http.StatusForbidden, "FORBIDDEN", "read-only access to calendar"
+	default:
+		h.logger.Error().Ctx(ctx).Err(err).Any("index", result.Index).Msg("failed to run batch operation")
+	}
+
+	result.Status = status
+	result.Error = &dto.BatchError{Code: code, Message: message}
+}
+
+func (h *BatchHandler) getUserData(c *gin.Context) (uuid.UUID, bool) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		h.logger.Error().Ctx(c.Request.Context()).Msg("failed to get user_id from ctx")
+		response.Unauthorized(c, "missing user_id in token")
+		return uuid.Nil, false
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Any("user_id", userID).Msg("failed to parse user id into uuid")
+		response.Unauthorized(c, "user_id must be uuid format")
+		return uuid.Nil, false
+	}
+
+	return userUUID, true
+}
@@ -0,0 +1,95 @@
+package rest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/event/mocks"
+	"github.com/ilam072/event-calendar/internal/event/rest"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func batchRouter(h *rest.BatchHandler, userID uuid.UUID) *gin.Engine {
+	r := gin.New()
+	r.POST("/events:action", func(c *gin.Context) {
+		c.Set("user_id", userID.String())
+		h.Batch(c)
+	})
+	return r
+}
+
+func TestBatch_PerItemResults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	eventID := uuid.New()
+
+	mockBatch := mocks.NewMockBatch(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
+	mockBatch.EXPECT().
+		Batch(gomock.Any(), gomock.Any(), userID).
+		Return(dto.BatchResponse{Mode: dto.BatchBestEffort, Results: []dto.BatchResult{
+			{Index: 0, EventID: &eventID, Version: 1},
+			{Index: 1, Err: domain.ErrVersionMismatch},
+			{Index: 2, Err: domain.ErrForbidden},
+		}}, nil)
+
+	h := rest.NewBatchHandler(mockBatch, mockValidator, log)
+
+	body := `{"mode":"best_effort","operations":[{"method":"create","create":{"date":"2025-01-01T00:00:00Z","description":"x"}}]}`
+	req := httptest.NewRequest("POST", "/events:batch", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	batchRouter(h, userID).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp dto.BatchResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusOK, resp.Results[0].Status)
+	assert.Nil(t, resp.Results[0].Error)
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Results[1].Status)
+	assert.Equal(t, "PRECONDITION_FAILED", resp.Results[1].Error.Code)
+	assert.Equal(t, http.StatusForbidden, resp.Results[2].Status)
+}
+
+func TestBatch_UnknownAction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := rest.NewBatchHandler(mocks.NewMockBatch(ctrl), mocks.NewMockValidator(ctrl), log)
+
+	req := httptest.NewRequest("POST", "/events:import", bytes.NewBufferString(`{}`))
+	rec := httptest.NewRecorder()
+
+	batchRouter(h, uuid.New()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestBatch_ValidationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockValidator := mocks.NewMockValidator(ctrl)
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(assert.AnError)
+
+	h := rest.NewBatchHandler(mocks.NewMockBatch(ctrl), mockValidator, log)
+
+	req := httptest.NewRequest("POST", "/events:batch", bytes.NewBufferString(`{"operations":[]}`))
+	rec := httptest.NewRecorder()
+
+	batchRouter(h, uuid.New()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
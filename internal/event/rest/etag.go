@@ -0,0 +1,91 @@
+package rest
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/response"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var errInvalidIfMatch = errors.New("invalid If-Match header")
+
+// etag renders an event version as a strong entity tag.
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// parseIfMatch returns the event versions listed in If-Match. Nil means
+// the header is absent or "*", so the write is unconditional. Weak tags
+// never pass the strong comparison If-Match requires, so they are
+// validated but left out; a header made only of weak tags yields an empty,
+// non-nil list.
+func parseIfMatch(c *gin.Context) ([]int, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	versions := make([]int, 0, 1)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		weak := strings.HasPrefix(tag, "W/")
+		tag = strings.TrimPrefix(tag, "W/")
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			return nil, errInvalidIfMatch
+		}
+
+		version, err := strconv.Atoi(tag[1 : len(tag)-1])
+		if err != nil || version < 1 {
+			return nil, errInvalidIfMatch
+		}
+		if !weak {
+			versions = append(versions, version)
+		}
+	}
+
+	return versions, nil
+}
+
+// ifMatchVersion resolves If-Match to the version a write must expect,
+// zero for an unconditional one. When several tags are listed the current
+// version is looked up and picked if it is one of them; the write still
+// checks it, so a concurrent change yields a mismatch as usual. It writes
+// the error response itself and reports false in that case.
+func (h *EventHandler) ifMatchVersion(c *gin.Context, eventID, userID uuid.UUID) (int, bool) {
+	versions, err := parseIfMatch(c)
+	if err != nil {
+		response.BadRequest(c, "invalid If-Match header: must be an ETag returned by the server")
+		return 0, false
+	}
+
+	switch {
+	case versions == nil:
+		return 0, true
+	case len(versions) == 1:
+		return versions[0], true
+	case len(versions) == 0:
+		response.PreconditionFailed(c, "event has been modified, fetch it again and retry")
+		return 0, false
+	}
+
+	event, err := h.event.GetEvent(c.Request.Context(), eventID, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEventNotFound) {
+			response.NotFound(c)
+			return 0, false
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("event_id", eventID.String()).Msg("failed to get event")
+		response.InternalServerError(c)
+		return 0, false
+	}
+	if !slices.Contains(versions, event.Version) {
+		response.PreconditionFailed(c, "event has been modified, fetch it again and retry")
+		return 0, false
+	}
+
+	return event.Version, true
+}
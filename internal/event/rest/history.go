@@ -0,0 +1,85 @@
+package rest
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/response"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"net/http"
+	"strconv"
+)
+
+func (h *EventHandler) GetHistory(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to parse event id into uuid")
+		response.BadRequest(c, "event id must be UUID format")
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	history, err := h.event.GetHistory(c.Request.Context(), eventID, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEventNotFound) {
+			response.NotFound(c)
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("event_id", eventID.String()).Msg("failed to get event history")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *EventHandler) RevertEvent(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to parse event id into uuid")
+		response.BadRequest(c, "event id must be UUID format")
+		return
+	}
+
+	revisionID, err := strconv.ParseInt(c.Param("revision_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "revision id must be an integer")
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	version, ok := h.ifMatchVersion(c, eventID, userID)
+	if !ok {
+		return
+	}
+
+	resp, err := h.event.RevertEvent(c.Request.Context(), eventID, revisionID, userID, version)
+	if err != nil {
+		if errors.Is(err, domain.ErrVersionMismatch) {
+			response.PreconditionFailed(c, "event has been modified, fetch it again and retry")
+			return
+		}
+		if errors.Is(err, domain.ErrEventNotFound) || errors.Is(err, domain.ErrRevisionNotFound) || errors.Is(err, domain.ErrCalendarNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			response.Forbidden(c, "read-only access to calendar")
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("event_id", eventID.String()).Msg("failed to revert event")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.Header("ETag", etag(resp.Version))
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,124 @@
+package rest_test
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/event/mocks"
+	"github.com/ilam072/event-calendar/internal/event/rest"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func historyRouter(h *rest.EventHandler, userID uuid.UUID) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", userID.String())
+	})
+	r.GET("/events/:id/history", h.GetHistory)
+	r.POST("/events/:id/history/:revision_id/revert", h.RevertEvent)
+	return r
+}
+
+func TestGetHistory_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventID := uuid.New()
+	userID := uuid.New()
+
+	mockEvent := mocks.NewMockEvent(ctrl)
+	mockEvent.EXPECT().
+		GetHistory(gomock.Any(), eventID, userID).
+		Return(dto.GetHistoryResponse{Revisions: []dto.EventRevision{{ID: 1, Action: domain.HistoryCreate}}}, nil)
+
+	req := httptest.NewRequest("GET", "/events/"+eventID.String()+"/history", nil)
+	rec := httptest.NewRecorder()
+	historyRouter(rest.NewEventHandler(mockEvent, mocks.NewMockValidator(ctrl), log), userID).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"revision_id":1`)
+}
+
+func TestGetHistory_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvent := mocks.NewMockEvent(ctrl)
+	mockEvent.EXPECT().
+		GetHistory(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.GetHistoryResponse{}, domain.ErrEventNotFound)
+
+	req := httptest.NewRequest("GET", "/events/"+uuid.New().String()+"/history", nil)
+	rec := httptest.NewRecorder()
+	historyRouter(rest.NewEventHandler(mockEvent, mocks.NewMockValidator(ctrl), log), uuid.New()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRevertEvent_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventID := uuid.New()
+
+	mockEvent := mocks.NewMockEvent(ctrl)
+	mockEvent.EXPECT().
+		RevertEvent(gomock.Any(), eventID, int64(3), gomock.Any(), 4).
+		Return(dto.UpdateEventResponse{Version: 5}, nil)
+
+	req := httptest.NewRequest("POST", "/events/"+eventID.String()+"/history/3/revert", nil)
+	req.Header.Set("If-Match", `"4"`)
+	rec := httptest.NewRecorder()
+	historyRouter(rest.NewEventHandler(mockEvent, mocks.NewMockValidator(ctrl), log), uuid.New()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"5"`, rec.Header().Get("ETag"))
+}
+
+func TestRevertEvent_InvalidRevisionID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	req := httptest.NewRequest("POST", "/events/"+uuid.New().String()+"/history/abc/revert", nil)
+	rec := httptest.NewRecorder()
+	historyRouter(rest.NewEventHandler(mocks.NewMockEvent(ctrl), mocks.NewMockValidator(ctrl), log), uuid.New()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestRevertEvent_RevisionNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvent := mocks.NewMockEvent(ctrl)
+	mockEvent.EXPECT().
+		RevertEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.UpdateEventResponse{}, domain.ErrRevisionNotFound)
+
+	req := httptest.NewRequest("POST", "/events/"+uuid.New().String()+"/history/3/revert", nil)
+	rec := httptest.NewRecorder()
+	historyRouter(rest.NewEventHandler(mockEvent, mocks.NewMockValidator(ctrl), log), uuid.New()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRevertEvent_VersionMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvent := mocks.NewMockEvent(ctrl)
+	mockEvent.EXPECT().
+		RevertEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.UpdateEventResponse{}, domain.ErrVersionMismatch)
+
+	req := httptest.NewRequest("POST", "/events/"+uuid.New().String()+"/history/3/revert", nil)
+	rec := httptest.NewRecorder()
+	historyRouter(rest.NewEventHandler(mockEvent, mocks.NewMockValidator(ctrl), log), uuid.New()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+}
@@ -11,18 +11,23 @@ import (
 	"github.com/ilam072/event-calendar/internal/types/dto"
 	"github.com/ilam072/event-calendar/pkg/logger"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
 
 //go:generate mockgen -source=rest.go -destination=../mocks/rest_mocks.go -package=mocks
 type Event interface {
-	CreateEvent(ctx context.Context, event dto.CreateEventRequest, userID uuid.UUID) (uuid.UUID, error)
-	UpdateEvent(ctx context.Context, event dto.UpdateEventRequest, eventID uuid.UUID, userID uuid.UUID) error
-	DeleteEvent(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) error
-	GetEventsForDay(ctx context.Context, userID uuid.UUID, date time.Time) (dto.GetEventsResponse, error)
-	GetEventsForWeek(ctx context.Context, userID uuid.UUID, date time.Time) (dto.GetEventsResponse, error)
-	GetEventsForMonth(ctx context.Context, userID uuid.UUID, date time.Time) (dto.GetEventsResponse, error)
+	CreateEvent(ctx context.Context, event dto.CreateEventRequest, userID uuid.UUID, strict bool) (dto.CreateEventResponse, error)
+	GetEvent(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) (dto.Event, error)
+	UpdateEvent(ctx context.Context, event dto.UpdateEventRequest, eventID uuid.UUID, userID uuid.UUID, version int, strict bool) (dto.UpdateEventResponse, error)
+	PatchEvent(ctx context.Context, patch dto.PatchEventRequest, eventID uuid.UUID, userID uuid.UUID, version int, strict bool) (dto.UpdateEventResponse, error)
+	DeleteEvent(ctx context.Context, eventID uuid.UUID, userID uuid.UUID, version int) error
+	GetEventsForDay(ctx context.Context, userID uuid.UUID, date time.Time, calendarIDs []uuid.UUID) (dto.GetEventsResponse, error)
+	GetEventsForWeek(ctx context.Context, userID uuid.UUID, date time.Time, calendarIDs []uuid.UUID) (dto.GetEventsResponse, error)
+	GetEventsForMonth(ctx context.Context, userID uuid.UUID, date time.Time, calendarIDs []uuid.UUID) (dto.GetEventsResponse, error)
+	GetHistory(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) (dto.GetHistoryResponse, error)
+	RevertEvent(ctx context.Context, eventID uuid.UUID, revisionID int64, userID uuid.UUID, version int) (dto.UpdateEventResponse, error)
 }
 
 type Validator interface {
@@ -42,7 +47,7 @@ func NewEventHandler(event Event, validator Validator, logger logger.Logger) *Ev
 func (h *EventHandler) CreateEvent(c *gin.Context) {
 	var event dto.CreateEventRequest
 	if err := c.BindJSON(&event); err != nil {
-		h.logger.Warn().Err(err).Msg("failed to bind create event json")
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to bind create event json")
 		response.BadRequest(c, "invalid request body")
 		return
 	}
@@ -52,19 +57,65 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
 		return
 	}
 
+	strict, err := strconv.ParseBool(c.DefaultQuery("strict", "false"))
+	if err != nil {
+		response.BadRequest(c, "invalid query param 'strict': must be 'true' or 'false'")
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.event.CreateEvent(c.Request.Context(), event, userID, strict)
+	if err != nil {
+		if errors.Is(err, domain.ErrEventConflict) {
+			conflict(c, resp.Conflicts)
+			return
+		}
+		if errors.Is(err, domain.ErrCalendarNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			response.Forbidden(c, "read-only access to calendar")
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Any("event", event).Msg("failed to create event")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *EventHandler) GetEvent(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to parse event id into uuid")
+		response.BadRequest(c, "event id must be UUID format")
+		return
+	}
+
 	userID, ok := h.getUserData(c)
 	if !ok {
 		return
 	}
 
-	eventID, err := h.event.CreateEvent(c.Request.Context(), event, userID)
+	event, err := h.event.GetEvent(c.Request.Context(), eventID, userID)
 	if err != nil {
-		h.logger.Error().Err(err).Any("event", event).Msg("failed to create event")
+		if errors.Is(err, domain.ErrEventNotFound) {
+			response.NotFound(c)
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("event_id", eventID.String()).Msg("failed to get event")
 		response.InternalServerError(c)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"event_id": eventID})
+	c.Header("ETag", etag(event.Version))
+	c.JSON(http.StatusOK, event)
 }
 
 func (h *EventHandler) GetEvents(c *gin.Context) {
@@ -80,6 +131,12 @@ func (h *EventHandler) GetEvents(c *gin.Context) {
 		return
 	}
 
+	calendarIDs, err := parseCalendarIDs(c)
+	if err != nil {
+		response.BadRequest(c, "invalid query param 'calendar_id': must be UUID format")
+		return
+	}
+
 	userID, ok := h.getUserData(c)
 	if !ok {
 		return
@@ -88,17 +145,17 @@ func (h *EventHandler) GetEvents(c *gin.Context) {
 	var events dto.GetEventsResponse
 	switch strings.ToLower(period) {
 	case "day":
-		events, err = h.event.GetEventsForDay(c.Request.Context(), userID, date)
+		events, err = h.event.GetEventsForDay(c.Request.Context(), userID, date, calendarIDs)
 	case "week":
-		events, err = h.event.GetEventsForWeek(c.Request.Context(), userID, date)
+		events, err = h.event.GetEventsForWeek(c.Request.Context(), userID, date, calendarIDs)
 	case "month":
-		events, err = h.event.GetEventsForMonth(c.Request.Context(), userID, date)
+		events, err = h.event.GetEventsForMonth(c.Request.Context(), userID, date, calendarIDs)
 	default:
 		response.BadRequest(c, "unexpected query param 'period': must be 'day', 'week' or 'month'")
 		return
 	}
 	if err != nil {
-		h.logger.Error().
+		h.logger.Error().Ctx(c.Request.Context()).
 			Err(err).
 			Any("period", period).
 			Str("date", date.String()).
@@ -114,14 +171,14 @@ func (h *EventHandler) GetEvents(c *gin.Context) {
 func (h *EventHandler) UpdateEvent(c *gin.Context) {
 	eventID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		h.logger.Warn().Err(err).Msg("failed to parse event id into uuid")
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to parse event id into uuid")
 		response.BadRequest(c, "event id must be UUID format")
 		return
 	}
 
 	var event dto.UpdateEventRequest
 	if err = c.BindJSON(&event); err != nil {
-		h.logger.Warn().Err(err).Msg("failed to bind update event json")
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to bind update event json")
 		response.BadRequest(c, "invalid request body")
 		return
 	}
@@ -131,28 +188,121 @@ func (h *EventHandler) UpdateEvent(c *gin.Context) {
 		return
 	}
 
+	strict, err := strconv.ParseBool(c.DefaultQuery("strict", "false"))
+	if err != nil {
+		response.BadRequest(c, "invalid query param 'strict': must be 'true' or 'false'")
+		return
+	}
+
 	userID, ok := h.getUserData(c)
 	if !ok {
 		return
 	}
 
-	if err = h.event.UpdateEvent(c.Request.Context(), event, eventID, userID); err != nil {
-		if errors.Is(err, domain.ErrEventNotFound) {
+	version, ok := h.ifMatchVersion(c, eventID, userID)
+	if !ok {
+		return
+	}
+
+	resp, err := h.event.UpdateEvent(c.Request.Context(), event, eventID, userID, version, strict)
+	if err != nil {
+		if errors.Is(err, domain.ErrEventConflict) {
+			conflict(c, resp.Conflicts)
+			return
+		}
+		if errors.Is(err, domain.ErrVersionMismatch) {
+			response.PreconditionFailed(c, "event has been modified, fetch it again and retry")
+			return
+		}
+		if errors.Is(err, domain.ErrEventNotFound) || errors.Is(err, domain.ErrCalendarNotFound) {
 			response.NotFound(c)
 			return
 		}
-		h.logger.Error().Err(err).Any("event", event).Str("event_id", eventID.String()).Msg("failed to update event")
+		if errors.Is(err, domain.ErrForbidden) {
+			response.Forbidden(c, "read-only access to calendar")
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Any("event", event).Str("event_id", eventID.String()).Msg("failed to update event")
 		response.InternalServerError(c)
 		return
 	}
 
-	c.Status(http.StatusOK)
+	c.Header("ETag", etag(resp.Version))
+	c.JSON(http.StatusOK, resp)
+}
+
+// PatchEvent updates only the members present in a JSON Merge Patch body.
+func (h *EventHandler) PatchEvent(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to parse event id into uuid")
+		response.BadRequest(c, "event id must be UUID format")
+		return
+	}
+
+	var patch dto.PatchEventRequest
+	if err = c.BindJSON(&patch); err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to bind patch event json")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err = h.validator.Validate(patch); err != nil {
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	strict, err := strconv.ParseBool(c.DefaultQuery("strict", "false"))
+	if err != nil {
+		response.BadRequest(c, "invalid query param 'strict': must be 'true' or 'false'")
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	version, ok := h.ifMatchVersion(c, eventID, userID)
+	if !ok {
+		return
+	}
+
+	resp, err := h.event.PatchEvent(c.Request.Context(), patch, eventID, userID, version, strict)
+	if err != nil {
+		if errors.Is(err, domain.ErrEventConflict) {
+			conflict(c, resp.Conflicts)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidTimeRange) {
+			response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+			return
+		}
+		if errors.Is(err, domain.ErrVersionMismatch) {
+			response.PreconditionFailed(c, "event has been modified, fetch it again and retry")
+			return
+		}
+		if errors.Is(err, domain.ErrEventNotFound) || errors.Is(err, domain.ErrCalendarNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			response.Forbidden(c, "read-only access to calendar")
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("event_id", eventID.String()).Msg("failed to patch event")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.Header("ETag", etag(resp.Version))
+	c.JSON(http.StatusOK, resp)
 }
 
 func (h *EventHandler) DeleteEvent(c *gin.Context) {
 	eventID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		h.logger.Warn().Err(err).Msg("failed to parse event id into uuid")
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to parse event id into uuid")
 		response.BadRequest(c, "event id must be UUID format")
 		return
 	}
@@ -162,12 +312,25 @@ func (h *EventHandler) DeleteEvent(c *gin.Context) {
 		return
 	}
 
-	if err = h.event.DeleteEvent(c.Request.Context(), eventID, userID); err != nil {
+	version, ok := h.ifMatchVersion(c, eventID, userID)
+	if !ok {
+		return
+	}
+
+	if err := h.event.DeleteEvent(c.Request.Context(), eventID, userID, version); err != nil {
 		if errors.Is(err, domain.ErrEventNotFound) {
 			response.NotFound(c)
 			return
 		}
-		h.logger.Error().Err(err).Str("event_id", eventID.String()).Msg("failed to delete event")
+		if errors.Is(err, domain.ErrVersionMismatch) {
+			response.PreconditionFailed(c, "event has been modified, fetch it again and retry")
+			return
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			response.Forbidden(c, "read-only access to calendar")
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("event_id", eventID.String()).Msg("failed to delete event")
 		response.InternalServerError(c)
 		return
 	}
@@ -175,17 +338,43 @@ func (h *EventHandler) DeleteEvent(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
+// conflict rejects an event that overlaps others in strict mode.
+func conflict(c *gin.Context, conflicts []dto.Event) {
+	response.ConflictWithDetails(c, "EVENT_CONFLICT", "event overlaps existing events", gin.H{"conflicts": conflicts})
+}
+
+// parseCalendarIDs reads the optional calendar filter, given either as
+// repeated ?calendar_id= params or as a comma-separated list.
+func parseCalendarIDs(c *gin.Context) ([]uuid.UUID, error) {
+	var calendarIDs []uuid.UUID
+	for _, param := range c.QueryArray("calendar_id") {
+		for _, raw := range strings.Split(param, ",") {
+			if raw = strings.TrimSpace(raw); raw == "" {
+				continue
+			}
+
+			calendarID, err := uuid.Parse(raw)
+			if err != nil {
+				return nil, err
+			}
+			calendarIDs = append(calendarIDs, calendarID)
+		}
+	}
+
+	return calendarIDs, nil
+}
+
 func (h *EventHandler) getUserData(c *gin.Context) (uuid.UUID, bool) {
 	userID, ok := c.Get("user_id")
 	if !ok {
-		h.logger.Error().Msg("failed to get user_id from ctx")
+		h.logger.Error().Ctx(c.Request.Context()).Msg("failed to get user_id from ctx")
 		response.Unauthorized(c, "missing user_id in token")
 		return uuid.Nil, false
 	}
 
 	userUUID, err := uuid.Parse(userID.(string))
 	if err != nil {
-		h.logger.Warn().Any("user_id", userID).Msg("failed to parse user id into uuid")
+		h.logger.Warn().Ctx(c.Request.Context()).Any("user_id", userID).Msg("failed to parse user id into uuid")
 		response.Unauthorized(c, "user_id must be uuid format")
 		return uuid.Nil, false
 	}
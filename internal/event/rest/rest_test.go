@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/ilam072/event-calendar/internal/event/mocks"
@@ -103,8 +104,8 @@ func TestCreateEvent_Success(t *testing.T) {
 
 	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
 	mockEvent.EXPECT().
-		CreateEvent(gomock.Any(), gomock.Any(), userID).
-		Return(uuid.New(), nil)
+		CreateEvent(gomock.Any(), gomock.Any(), userID, false).
+		Return(dto.CreateEventResponse{ID: uuid.New()}, nil)
 
 	h := rest.NewEventHandler(mockEvent, mockValidator, log)
 
@@ -134,8 +135,8 @@ func TestCreateEvent_ServiceError(t *testing.T) {
 
 	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
 	mockEvent.EXPECT().
-		CreateEvent(gomock.Any(), gomock.Any(), gomock.Any()).
-		Return(uuid.Nil, errors.New("boom"))
+		CreateEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.CreateEventResponse{}, errors.New("boom"))
 
 	h := rest.NewEventHandler(mockEvent, mockValidator, log)
 	r := gin.New()
@@ -153,6 +154,58 @@ func TestCreateEvent_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
 }
 
+func TestCreateEvent_StrictConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvent := mocks.NewMockEvent(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	conflictID := uuid.New()
+
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
+	mockEvent.EXPECT().
+		CreateEvent(gomock.Any(), gomock.Any(), gomock.Any(), true).
+		Return(dto.CreateEventResponse{Conflicts: []dto.Event{{ID: conflictID}}}, domain.ErrEventConflict)
+
+	h := rest.NewEventHandler(mockEvent, mockValidator, log)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", uuid.New().String())
+	})
+	r.POST("/event", h.CreateEvent)
+
+	body := `{"starts_at":"2025-01-01T10:00:00Z","ends_at":"2025-01-01T11:00:00Z","description":"test"}`
+	req := httptest.NewRequest("POST", "/event?strict=true", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"EVENT_CONFLICT"`)
+	assert.Contains(t, rec.Body.String(), conflictID.String())
+}
+
+func TestCreateEvent_InvalidStrict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockValidator := mocks.NewMockValidator(ctrl)
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
+
+	h := rest.NewEventHandler(mocks.NewMockEvent(ctrl), mockValidator, log)
+	r := gin.New()
+	r.POST("/event", h.CreateEvent)
+
+	body := `{"date":"2025-01-01T00:00:00Z","description":"test"}`
+	req := httptest.NewRequest("POST", "/event?strict=maybe", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
 //
 // --------------------------------------------------------------------------------------------
 // GetEvents
@@ -241,7 +294,7 @@ func TestGetEvents_Success_Day(t *testing.T) {
 
 	mockEvent := mocks.NewMockEvent(ctrl)
 	mockEvent.EXPECT().
-		GetEventsForDay(gomock.Any(), gomock.Any(), gomock.Any()).
+		GetEventsForDay(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(dto.GetEventsResponse{}, nil)
 
 	h := rest.NewEventHandler(mockEvent, mocks.NewMockValidator(ctrl), log)
@@ -259,13 +312,59 @@ func TestGetEvents_Success_Day(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
 
+func TestGetEvents_CalendarFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+
+	mockEvent := mocks.NewMockEvent(ctrl)
+	mockEvent.EXPECT().
+		GetEventsForWeek(gomock.Any(), gomock.Any(), gomock.Any(), []uuid.UUID{first, second, third}).
+		Return(dto.GetEventsResponse{}, nil)
+
+	h := rest.NewEventHandler(mockEvent, mocks.NewMockValidator(ctrl), log)
+	r := gin.New()
+	r.GET("/event", func(c *gin.Context) {
+		c.Set("user_id", uuid.New().String())
+		h.GetEvents(c)
+	})
+
+	url := fmt.Sprintf("/event?period=week&date=2025-01-01&calendar_id=%s,%s&calendar_id=%s", first, second, third)
+	req := httptest.NewRequest("GET", url, nil)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestGetEvents_InvalidCalendarID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := rest.NewEventHandler(mocks.NewMockEvent(ctrl), mocks.NewMockValidator(ctrl), log)
+	r := gin.New()
+	r.GET("/event", func(c *gin.Context) {
+		c.Set("user_id", uuid.New().String())
+		h.GetEvents(c)
+	})
+
+	req := httptest.NewRequest("GET", "/event?period=day&date=2025-01-01&calendar_id=nope", nil)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
 func TestGetEvents_ServiceError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockEvent := mocks.NewMockEvent(ctrl)
 	mockEvent.EXPECT().
-		GetEventsForDay(gomock.Any(), gomock.Any(), gomock.Any()).
+		GetEventsForDay(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(dto.GetEventsResponse{}, errors.New("boom"))
 
 	h := rest.NewEventHandler(mockEvent, mocks.NewMockValidator(ctrl), log)
@@ -366,8 +465,8 @@ func TestUpdateEvent_NotFound(t *testing.T) {
 
 	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
 	mockEvent.EXPECT().
-		UpdateEvent(gomock.Any(), gomock.Any(), eventID, userID).
-		Return(domain.ErrEventNotFound)
+		UpdateEvent(gomock.Any(), gomock.Any(), eventID, userID, 0, false).
+		Return(dto.UpdateEventResponse{}, domain.ErrEventNotFound)
 
 	h := rest.NewEventHandler(mockEvent, mockValidator, log)
 
@@ -395,8 +494,8 @@ func TestUpdateEvent_Success(t *testing.T) {
 
 	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
 	mockEvent.EXPECT().
-		UpdateEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-		Return(nil)
+		UpdateEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.UpdateEventResponse{Version: 2}, nil)
 
 	h := rest.NewEventHandler(mockEvent, mockValidator, log)
 	r := gin.New()
@@ -411,9 +510,124 @@ func TestUpdateEvent_Success(t *testing.T) {
 
 	r.ServeHTTP(rec, req)
 
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+}
+
+func TestUpdateEvent_IfMatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvent := mocks.NewMockEvent(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
+	mockEvent.EXPECT().
+		UpdateEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), 3, false).
+		Return(dto.UpdateEventResponse{}, domain.ErrVersionMismatch)
+
+	h := rest.NewEventHandler(mockEvent, mockValidator, log)
+	r := gin.New()
+	r.PUT("/event/:id", func(c *gin.Context) {
+		c.Set("user_id", uuid.New().String())
+		h.UpdateEvent(c)
+	})
+
+	body := `{"date":"2025-01-01T00:00:00Z","description":"x"}`
+	req := httptest.NewRequest("PUT", "/event/"+uuid.New().String(), bytes.NewBufferString(body))
+	req.Header.Set("If-Match", `"3"`)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+}
+
+func TestUpdateEvent_InvalidIfMatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockValidator := mocks.NewMockValidator(ctrl)
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
+
+	h := rest.NewEventHandler(mocks.NewMockEvent(ctrl), mockValidator, log)
+	r := gin.New()
+	r.PUT("/event/:id", func(c *gin.Context) {
+		c.Set("user_id", uuid.New().String())
+		h.UpdateEvent(c)
+	})
+
+	body := `{"date":"2025-01-01T00:00:00Z","description":"x"}`
+	req := httptest.NewRequest("PUT", "/event/"+uuid.New().String(), bytes.NewBufferString(body))
+	req.Header.Set("If-Match", "3")
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestUpdateEvent_IfMatchList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvent := mocks.NewMockEvent(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
+	mockEvent.EXPECT().
+		GetEvent(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.Event{Version: 3}, nil)
+	mockEvent.EXPECT().
+		UpdateEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), 3, false).
+		Return(dto.UpdateEventResponse{}, nil)
+
+	h := rest.NewEventHandler(mockEvent, mockValidator, log)
+	r := gin.New()
+	r.PUT("/event/:id", func(c *gin.Context) {
+		c.Set("user_id", uuid.New().String())
+		h.UpdateEvent(c)
+	})
+
+	body := `{"date":"2025-01-01T00:00:00Z","description":"x"}`
+	req := httptest.NewRequest("PUT", "/event/"+uuid.New().String(), bytes.NewBufferString(body))
+	req.Header.Set("If-Match", `"2", W/"4", "3"`)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
 
+func TestUpdateEvent_IfMatchListMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvent := mocks.NewMockEvent(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
+	mockEvent.EXPECT().
+		GetEvent(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.Event{Version: 4}, nil)
+
+	h := rest.NewEventHandler(mockEvent, mockValidator, log)
+	r := gin.New()
+	r.PUT("/event/:id", func(c *gin.Context) {
+		c.Set("user_id", uuid.New().String())
+		h.UpdateEvent(c)
+	})
+
+	body := `{"date":"2025-01-01T00:00:00Z","description":"x"}`
+	req := httptest.NewRequest("PUT", "/event/"+uuid.New().String(), bytes.NewBufferString(body))
+	req.Header.Set("If-Match", `"2","3"`)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+}
+
 func TestUpdateEvent_ServiceError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -423,8 +637,8 @@ func TestUpdateEvent_ServiceError(t *testing.T) {
 
 	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
 	mockEvent.EXPECT().
-		UpdateEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-		Return(errors.New("boom"))
+		UpdateEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.UpdateEventResponse{}, errors.New("boom"))
 
 	h := rest.NewEventHandler(mockEvent, mockValidator, log)
 	r := gin.New()
@@ -442,6 +656,89 @@ func TestUpdateEvent_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
 }
 
+//
+// --------------------------------------------------------------------------------------------
+// PatchEvent
+// --------------------------------------------------------------------------------------------
+
+func TestPatchEvent_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvent := mocks.NewMockEvent(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
+	mockEvent.EXPECT().
+		PatchEvent(gomock.Any(), gomock.Cond(func(p dto.PatchEventRequest) bool {
+			return p.RemindAt.Set && p.RemindAt.Null && p.Description == nil
+		}), gomock.Any(), gomock.Any(), 2, false).
+		Return(dto.UpdateEventResponse{Version: 3}, nil)
+
+	h := rest.NewEventHandler(mockEvent, mockValidator, log)
+	r := gin.New()
+	r.PATCH("/event/:id", func(c *gin.Context) {
+		c.Set("user_id", uuid.New().String())
+		h.PatchEvent(c)
+	})
+
+	req := httptest.NewRequest("PATCH", "/event/"+uuid.New().String(), bytes.NewBufferString(`{"remind_at":null}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	req.Header.Set("If-Match", `"2"`)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+}
+
+func TestPatchEvent_InvalidTimeRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvent := mocks.NewMockEvent(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
+	mockEvent.EXPECT().
+		PatchEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.UpdateEventResponse{}, domain.ErrInvalidTimeRange)
+
+	h := rest.NewEventHandler(mockEvent, mockValidator, log)
+	r := gin.New()
+	r.PATCH("/event/:id", func(c *gin.Context) {
+		c.Set("user_id", uuid.New().String())
+		h.PatchEvent(c)
+	})
+
+	req := httptest.NewRequest("PATCH", "/event/"+uuid.New().String(), bytes.NewBufferString(`{"ends_at":null}`))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestPatchEvent_ValidationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockValidator := mocks.NewMockValidator(ctrl)
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(errors.New("description too short"))
+
+	h := rest.NewEventHandler(mocks.NewMockEvent(ctrl), mockValidator, log)
+	r := gin.New()
+	r.PATCH("/event/:id", h.PatchEvent)
+
+	req := httptest.NewRequest("PATCH", "/event/"+uuid.New().String(), bytes.NewBufferString(`{"description":""}`))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
 //
 // --------------------------------------------------------------------------------------------
 // DeleteEvent
@@ -471,7 +768,7 @@ func TestDeleteEvent_NotFound(t *testing.T) {
 
 	mockEvent := mocks.NewMockEvent(ctrl)
 	mockEvent.EXPECT().
-		DeleteEvent(gomock.Any(), gomock.Any(), gomock.Any()).
+		DeleteEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(domain.ErrEventNotFound)
 
 	h := rest.NewEventHandler(mockEvent, mocks.NewMockValidator(ctrl), log)
@@ -489,13 +786,37 @@ func TestDeleteEvent_NotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, rec.Code)
 }
 
+func TestDeleteEvent_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvent := mocks.NewMockEvent(ctrl)
+	mockEvent.EXPECT().
+		DeleteEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.ErrForbidden)
+
+	h := rest.NewEventHandler(mockEvent, mocks.NewMockValidator(ctrl), log)
+	r := gin.New()
+	r.DELETE("/event/:id", func(c *gin.Context) {
+		c.Set("user_id", uuid.New().String())
+		h.DeleteEvent(c)
+	})
+
+	req := httptest.NewRequest("DELETE", "/event/"+uuid.New().String(), nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
 func TestDeleteEvent_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockEvent := mocks.NewMockEvent(ctrl)
 	mockEvent.EXPECT().
-		DeleteEvent(gomock.Any(), gomock.Any(), gomock.Any()).
+		DeleteEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(nil)
 
 	h := rest.NewEventHandler(mockEvent, mocks.NewMockValidator(ctrl), log)
@@ -518,7 +839,7 @@ func TestDeleteEvent_ServiceError(t *testing.T) {
 
 	mockEvent := mocks.NewMockEvent(ctrl)
 	mockEvent.EXPECT().
-		DeleteEvent(gomock.Any(), gomock.Any(), gomock.Any()).
+		DeleteEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(errors.New("boom"))
 
 	h := rest.NewEventHandler(mockEvent, mocks.NewMockValidator(ctrl), log)
@@ -535,6 +856,104 @@ func TestDeleteEvent_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
 }
 
+func TestDeleteEvent_IfMatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvent := mocks.NewMockEvent(ctrl)
+	mockEvent.EXPECT().
+		DeleteEvent(gomock.Any(), gomock.Any(), gomock.Any(), 5).
+		Return(domain.ErrVersionMismatch)
+
+	h := rest.NewEventHandler(mockEvent, mocks.NewMockValidator(ctrl), log)
+	r := gin.New()
+	r.DELETE("/event/:id", func(c *gin.Context) {
+		c.Set("user_id", uuid.New().String())
+		h.DeleteEvent(c)
+	})
+
+	req := httptest.NewRequest("DELETE", "/event/"+uuid.New().String(), nil)
+	req.Header.Set("If-Match", `"5"`)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+}
+
+func TestDeleteEvent_WeakIfMatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := rest.NewEventHandler(mocks.NewMockEvent(ctrl), mocks.NewMockValidator(ctrl), log)
+	r := gin.New()
+	r.DELETE("/event/:id", func(c *gin.Context) {
+		c.Set("user_id", uuid.New().String())
+		h.DeleteEvent(c)
+	})
+
+	req := httptest.NewRequest("DELETE", "/event/"+uuid.New().String(), nil)
+	req.Header.Set("If-Match", `W/"5"`)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+}
+
+//
+// --------------------------------------------------------------------------------------------
+// GetEvent
+// --------------------------------------------------------------------------------------------
+
+func TestGetEvent_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventID := uuid.New()
+	userID := uuid.New()
+
+	mockEvent := mocks.NewMockEvent(ctrl)
+	mockEvent.EXPECT().
+		GetEvent(gomock.Any(), eventID, userID).
+		Return(dto.Event{ID: eventID, Version: 7}, nil)
+
+	h := rest.NewEventHandler(mockEvent, mocks.NewMockValidator(ctrl), log)
+	r := gin.New()
+	r.GET("/event/:id", func(c *gin.Context) {
+		c.Set("user_id", userID.String())
+		h.GetEvent(c)
+	})
+
+	req := httptest.NewRequest("GET", "/event/"+eventID.String(), nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"7"`, rec.Header().Get("ETag"))
+}
+
+func TestGetEvent_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvent := mocks.NewMockEvent(ctrl)
+	mockEvent.EXPECT().
+		GetEvent(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.Event{}, domain.ErrEventNotFound)
+
+	h := rest.NewEventHandler(mockEvent, mocks.NewMockValidator(ctrl), log)
+	r := gin.New()
+	r.GET("/event/:id", func(c *gin.Context) {
+		c.Set("user_id", uuid.New().String())
+		h.GetEvent(c)
+	})
+
+	req := httptest.NewRequest("GET", "/event/"+uuid.New().String(), nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
 func addUserID(req *http.Request) *http.Request {
 	ctx := context.WithValue(req.Context(), "user_id", uuid.New().String())
 	return req.WithContext(ctx)
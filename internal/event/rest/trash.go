@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/response"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"net/http"
+)
+
+//go:generate mockgen -source=trash.go -destination=../mocks/trash_rest_mocks.go -package=mocks
+type Trash interface {
+	GetTrash(ctx context.Context, userID uuid.UUID) (dto.GetEventsResponse, error)
+	RestoreEvent(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) (dto.Event, error)
+	EmptyTrash(ctx context.Context, userID uuid.UUID) (dto.EmptyTrashResponse, error)
+}
+
+type TrashHandler struct {
+	trash  Trash
+	logger logger.Logger
+}
+
+func NewTrashHandler(trash Trash, logger logger.Logger) *TrashHandler {
+	return &TrashHandler{trash: trash, logger: logger}
+}
+
+func (h *TrashHandler) GetTrash(c *gin.Context) {
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	events, err := h.trash.GetTrash(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("user_id", userID.String()).Msg("failed to get trash")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+func (h *TrashHandler) RestoreEvent(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to parse event id into uuid")
+		response.BadRequest(c, "event id must be UUID format")
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	event, err := h.trash.RestoreEvent(c.Request.Context(), eventID, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEventNotFound) {
+			response.NotFound(c)
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("event_id", eventID.String()).Msg("failed to restore event")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.Header("ETag", etag(event.Version))
+	c.JSON(http.StatusOK, event)
+}
+
+func (h *TrashHandler) EmptyTrash(c *gin.Context) {
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.trash.EmptyTrash(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("user_id", userID.String()).Msg("failed to empty trash")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *TrashHandler) getUserData(c *gin.Context) (uuid.UUID, bool) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		h.logger.Error().Ctx(c.Request.Context()).Msg("failed to get user_id from ctx")
+		response.Unauthorized(c, "missing user_id in token")
+		return uuid.Nil, false
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Any("user_id", userID).Msg("failed to parse user id into uuid")
+		response.Unauthorized(c, "user_id must be uuid format")
+		return uuid.Nil, false
+	}
+
+	return userUUID, true
+}
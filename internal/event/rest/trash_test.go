@@ -0,0 +1,107 @@
+package rest_test
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/event/mocks"
+	"github.com/ilam072/event-calendar/internal/event/rest"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func trashRouter(h *rest.TrashHandler, userID uuid.UUID) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", userID.String())
+	})
+	r.GET("/trash", h.GetTrash)
+	r.POST("/trash/:id/restore", h.RestoreEvent)
+	r.DELETE("/trash", h.EmptyTrash)
+	return r
+}
+
+func TestGetTrash_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+
+	mockTrash := mocks.NewMockTrash(ctrl)
+	mockTrash.EXPECT().
+		GetTrash(gomock.Any(), userID).
+		Return(dto.GetEventsResponse{Events: []dto.Event{}}, nil)
+
+	req := httptest.NewRequest("GET", "/trash", nil)
+	rec := httptest.NewRecorder()
+	trashRouter(rest.NewTrashHandler(mockTrash, log), userID).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRestoreEvent_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventID := uuid.New()
+
+	mockTrash := mocks.NewMockTrash(ctrl)
+	mockTrash.EXPECT().
+		RestoreEvent(gomock.Any(), eventID, gomock.Any()).
+		Return(dto.Event{ID: eventID, Version: 3}, nil)
+
+	req := httptest.NewRequest("POST", "/trash/"+eventID.String()+"/restore", nil)
+	rec := httptest.NewRecorder()
+	trashRouter(rest.NewTrashHandler(mockTrash, log), uuid.New()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+}
+
+func TestRestoreEvent_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTrash := mocks.NewMockTrash(ctrl)
+	mockTrash.EXPECT().
+		RestoreEvent(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.Event{}, domain.ErrEventNotFound)
+
+	req := httptest.NewRequest("POST", "/trash/"+uuid.New().String()+"/restore", nil)
+	rec := httptest.NewRecorder()
+	trashRouter(rest.NewTrashHandler(mockTrash, log), uuid.New()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRestoreEvent_InvalidUUID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	req := httptest.NewRequest("POST", "/trash/invalid/restore", nil)
+	rec := httptest.NewRecorder()
+	trashRouter(rest.NewTrashHandler(mocks.NewMockTrash(ctrl), log), uuid.New()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestEmptyTrash_ServiceError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTrash := mocks.NewMockTrash(ctrl)
+	mockTrash.EXPECT().
+		EmptyTrash(gomock.Any(), gomock.Any()).
+		Return(dto.EmptyTrashResponse{}, errors.New("boom"))
+
+	req := httptest.NewRequest("DELETE", "/trash", nil)
+	rec := httptest.NewRecorder()
+	trashRouter(rest.NewTrashHandler(mockTrash, log), uuid.New()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/event/repo"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"github.com/ilam072/event-calendar/pkg/jwt"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"net/url"
+	"strings"
+	"time"
+)
+
+//go:generate mockgen -source=attendee.go -destination=../mocks/attendee_service_mocks.go -package=mocks
+type AttendeeRepo interface {
+	GetEventByID(ctx context.Context, eventID uuid.UUID) (domain.Event, error)
+	AddAttendees(ctx context.Context, eventID uuid.UUID, userID uuid.UUID, emails []string) ([]domain.Attendee, error)
+	RemoveAttendee(ctx context.Context, eventID uuid.UUID, attendeeID uuid.UUID, userID uuid.UUID) error
+	RespondAsUser(ctx context.Context, eventID uuid.UUID, userID uuid.UUID, status string) error
+	RespondAsAttendee(ctx context.Context, attendeeID uuid.UUID, status string) error
+}
+
+type Sender interface {
+	Send(subject string, message string, to string) error
+}
+
+type RSVPTokenManager interface {
+	NewRSVPToken(attendeeID string, ttl time.Duration) (string, error)
+	ParseRSVPToken(tokenStr string) (*jwt.TokenClaims, error)
+}
+
+// Attendee invites people to events and records their responses.
+type Attendee struct {
+	repo    AttendeeRepo
+	sender  Sender
+	tokens  RSVPTokenManager
+	baseURL string
+	logger  logger.Logger
+}
+
+// NewAttendee creates the service. baseURL is the public address of the API
+// used to build the RSVP links in invitation emails.
+func NewAttendee(repo AttendeeRepo, sender Sender, tokens RSVPTokenManager, baseURL string, logger logger.Logger) *Attendee {
+	return &Attendee{
+		repo:    repo,
+		sender:  sender,
+		tokens:  tokens,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		logger:  logger,
+	}
+}
+
+// AddAttendees invites the emails to the event and sends each new attendee
+// an invitation with RSVP links in the background. Emails that are already
+// invited are skipped.
+func (a *Attendee) AddAttendees(ctx context.Context, req dto.AddAttendeesRequest, eventID uuid.UUID, userID uuid.UUID) (dto.GetAttendeesResponse, error) {
+	const op = "service.attendee.Add"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	emails := make([]string, 0, len(req.Emails))
+	seen := make(map[string]struct{}, len(req.Emails))
+	for _, email := range req.Emails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if _, ok := seen[email]; ok {
+			continue
+		}
+		seen[email] = struct{}{}
+		emails = append(emails, email)
+	}
+
+	attendees, err := a.repo.AddAttendees(ctx, eventID, userID, emails)
+	if err != nil {
+		if errors.Is(err, repo.ErrEventNotFound) {
+			return dto.GetAttendeesResponse{}, errutils.Wrap(op, domain.ErrEventNotFound)
+		}
+		if errors.Is(err, repo.ErrReadOnly) {
+			return dto.GetAttendeesResponse{}, errutils.Wrap(op, domain.ErrForbidden)
+		}
+		return dto.GetAttendeesResponse{}, errutils.Wrap(op, err)
+	}
+
+	if len(attendees) > 0 {
+		event, err := a.repo.GetEventByID(ctx, eventID)
+		if err != nil {
+			return dto.GetAttendeesResponse{}, errutils.Wrap(op, err)
+		}
+
+		// Invitations to an event that is over would carry expired links.
+		if time.Until(rsvpExpiry(event)) > 0 {
+			go a.sendInvitations(context.WithoutCancel(ctx), attendees, event)
+		}
+	}
+
+	resp := dto.GetAttendeesResponse{Attendees: domainToAttendees(attendees)}
+	if resp.Attendees == nil {
+		resp.Attendees = []dto.Attendee{}
+	}
+
+	return resp, nil
+}
+
+func (a *Attendee) RemoveAttendee(ctx context.Context, eventID uuid.UUID, attendeeID uuid.UUID, userID uuid.UUID) error {
+	const op = "service.attendee.Remove"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	if err := a.repo.RemoveAttendee(ctx, eventID, attendeeID, userID); err != nil {
+		switch {
+		case errors.Is(err, repo.ErrEventNotFound):
+			return errutils.Wrap(op, domain.ErrEventNotFound)
+		case errors.Is(err, repo.ErrAttendeeNotFound):
+			return errutils.Wrap(op, domain.ErrAttendeeNotFound)
+		case errors.Is(err, repo.ErrReadOnly):
+			return errutils.Wrap(op, domain.ErrForbidden)
+		}
+		return errutils.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Respond records the answer of the signed-in user to an invitation.
+func (a *Attendee) Respond(ctx context.Context, req dto.RSVPRequest, eventID uuid.UUID, userID uuid.UUID) error {
+	const op = "service.attendee.Respond"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	if err := a.repo.RespondAsUser(ctx, eventID, userID, req.Status); err != nil {
+		if errors.Is(err, repo.ErrAttendeeNotFound) {
+			return errutils.Wrap(op, domain.ErrAttendeeNotFound)
+		}
+		return errutils.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// RespondByLink records the answer given through the signed link from an
+// invitation email, so that external attendees can respond without an
+// account.
+func (a *Attendee) RespondByLink(ctx context.Context, token string, req dto.RSVPRequest) error {
+	const op = "service.attendee.RespondByLink"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	claims, err := a.tokens.ParseRSVPToken(token)
+	if err != nil {
+		return errutils.Wrap(op, domain.ErrInvalidRSVPLink)
+	}
+
+	attendeeID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return errutils.Wrap(op, domain.ErrInvalidRSVPLink)
+	}
+
+	if err = a.repo.RespondAsAttendee(ctx, attendeeID, req.Status); err != nil {
+		if errors.Is(err, repo.ErrAttendeeNotFound) {
+			return errutils.Wrap(op, domain.ErrAttendeeNotFound)
+		}
+		return errutils.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// sendInvitations emails the attendees one after another. It runs in the
+// background, so a slow mail server does not hold up the request.
+func (a *Attendee) sendInvitations(ctx context.Context, attendees []domain.Attendee, event domain.Event) {
+	for _, attendee := range attendees {
+		a.sendInvitation(ctx, attendee, event)
+	}
+}
+
+func (a *Attendee) sendInvitation(ctx context.Context, attendee domain.Attendee, event domain.Event) {
+	token, err := a.tokens.NewRSVPToken(attendee.ID.String(), time.Until(rsvpExpiry(event)))
+	if err != nil {
+		a.logger.Error().Ctx(ctx).Err(err).Str("attendee_id", attendee.ID.String()).Msg("failed to create rsvp token")
+		return
+	}
+
+	message := fmt.Sprintf(
+		"You have been invited to %q on %s.\n\n"+
+			"Accept: %s\nTentative: %s\nDecline: %s",
+		event.Description, event.Date.Format(time.DateOnly),
+		a.rsvpLink(token, domain.AttendeeStatusAccepted),
+		a.rsvpLink(token, domain.AttendeeStatusTentative),
+		a.rsvpLink(token, domain.AttendeeStatusDeclined),
+	)
+
+	if err = a.sender.Send("Event invitation", message, attendee.Email); err != nil {
+		a.logger.Error().Ctx(ctx).Err(err).Str("attendee_id", attendee.ID.String()).Msg("failed to send event invitation")
+	}
+}
+
+// rsvpExpiry is when the links of an invitation stop working: at the end of
+// the day after the event.
+func rsvpExpiry(event domain.Event) time.Time {
+	return event.Date.AddDate(0, 0, 2)
+}
+
+func (a *Attendee) rsvpLink(token string, status string) string {
+	query := url.Values{}
+	query.Set("token", token)
+	query.Set("status", status)
+
+	return a.baseURL + "/rsvp?" + query.Encode()
+}
@@ -0,0 +1,180 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ilam072/event-calendar/internal/event/mocks"
+	"github.com/ilam072/event-calendar/internal/event/repo"
+	"github.com/ilam072/event-calendar/internal/event/service"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/jwt"
+	"github.com/ilam072/event-calendar/pkg/logger"
+)
+
+func TestAddAttendees_SendsInvitations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAttendeeRepo(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	mockTokens := mocks.NewMockRSVPTokenManager(ctrl)
+	svc := service.NewAttendee(mockRepo, mockSender, mockTokens, "https://calendar.example.com/", &logger.DummyLogger{})
+
+	eventID, userID, attendeeID := uuid.New(), uuid.New(), uuid.New()
+	sent := make(chan string, 1)
+
+	mockRepo.
+		EXPECT().
+		AddAttendees(gomock.Any(), eventID, userID, []string{"bob@example.com"}).
+		Return([]domain.Attendee{
+			{ID: attendeeID, EventID: eventID, Email: "bob@example.com", Status: domain.AttendeeStatusNeedsAction},
+		}, nil)
+	mockRepo.
+		EXPECT().
+		GetEventByID(gomock.Any(), eventID).
+		Return(domain.Event{ID: eventID, Date: time.Now(), Description: "Planning"}, nil)
+	mockTokens.
+		EXPECT().
+		NewRSVPToken(attendeeID.String(), gomock.Any()).
+		Return("signed", nil)
+	mockSender.
+		EXPECT().
+		Send(gomock.Any(), gomock.Any(), "bob@example.com").
+		Do(func(_ string, message string, _ string) { sent <- message }).
+		Return(nil)
+
+	resp, err := svc.AddAttendees(context.Background(), dto.AddAttendeesRequest{
+		Emails: []string{"Bob@Example.com", "bob@example.com"},
+	}, eventID, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Attendees) != 1 || resp.Attendees[0].ID != attendeeID {
+		t.Fatalf("unexpected attendees: %+v", resp.Attendees)
+	}
+
+	select {
+	case message := <-sent:
+		if !strings.Contains(message, "https://calendar.example.com/rsvp?status=accepted&token=signed") {
+			t.Fatalf("missing rsvp link in message: %s", message)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("invitation was not sent")
+	}
+}
+
+func TestAddAttendees_PastEventNotInvited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAttendeeRepo(ctrl)
+	svc := service.NewAttendee(mockRepo, mocks.NewMockSender(ctrl), mocks.NewMockRSVPTokenManager(ctrl), "https://calendar.example.com/", &logger.DummyLogger{})
+
+	eventID, userID := uuid.New(), uuid.New()
+
+	mockRepo.
+		EXPECT().
+		AddAttendees(gomock.Any(), eventID, userID, []string{"bob@example.com"}).
+		Return([]domain.Attendee{
+			{ID: uuid.New(), EventID: eventID, Email: "bob@example.com", Status: domain.AttendeeStatusNeedsAction},
+		}, nil)
+	mockRepo.
+		EXPECT().
+		GetEventByID(gomock.Any(), eventID).
+		Return(domain.Event{ID: eventID, Date: time.Now().AddDate(0, 0, -3), Description: "Retro"}, nil)
+
+	resp, err := svc.AddAttendees(context.Background(), dto.AddAttendeesRequest{
+		Emails: []string{"bob@example.com"},
+	}, eventID, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Attendees) != 1 {
+		t.Fatalf("unexpected attendees: %+v", resp.Attendees)
+	}
+}
+
+func TestAddAttendees_ReadOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAttendeeRepo(ctrl)
+	svc := service.NewAttendee(mockRepo, mocks.NewMockSender(ctrl), mocks.NewMockRSVPTokenManager(ctrl), "", &logger.DummyLogger{})
+
+	mockRepo.
+		EXPECT().
+		AddAttendees(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, repo.ErrReadOnly)
+
+	_, err := svc.AddAttendees(context.Background(), dto.AddAttendeesRequest{Emails: []string{"bob@example.com"}}, uuid.New(), uuid.New())
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestRespond_NotInvited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAttendeeRepo(ctrl)
+	svc := service.NewAttendee(mockRepo, mocks.NewMockSender(ctrl), mocks.NewMockRSVPTokenManager(ctrl), "", &logger.DummyLogger{})
+
+	mockRepo.
+		EXPECT().
+		RespondAsUser(gomock.Any(), gomock.Any(), gomock.Any(), domain.AttendeeStatusDeclined).
+		Return(repo.ErrAttendeeNotFound)
+
+	err := svc.Respond(context.Background(), dto.RSVPRequest{Status: domain.AttendeeStatusDeclined}, uuid.New(), uuid.New())
+	if !errors.Is(err, domain.ErrAttendeeNotFound) {
+		t.Fatalf("expected ErrAttendeeNotFound, got %v", err)
+	}
+}
+
+func TestRespondByLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAttendeeRepo(ctrl)
+	mockTokens := mocks.NewMockRSVPTokenManager(ctrl)
+	svc := service.NewAttendee(mockRepo, mocks.NewMockSender(ctrl), mockTokens, "", &logger.DummyLogger{})
+
+	attendeeID := uuid.New()
+
+	claims := &jwt.TokenClaims{Purpose: jwt.PurposeRSVP}
+	claims.Subject = attendeeID.String()
+
+	mockTokens.EXPECT().ParseRSVPToken("signed").Return(claims, nil)
+	mockRepo.
+		EXPECT().
+		RespondAsAttendee(gomock.Any(), attendeeID, domain.AttendeeStatusTentative).
+		Return(nil)
+
+	if err := svc.RespondByLink(context.Background(), "signed", dto.RSVPRequest{Status: domain.AttendeeStatusTentative}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRespondByLink_InvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokens := mocks.NewMockRSVPTokenManager(ctrl)
+	svc := service.NewAttendee(mocks.NewMockAttendeeRepo(ctrl), mocks.NewMockSender(ctrl), mockTokens, "", &logger.DummyLogger{})
+
+	mockTokens.EXPECT().ParseRSVPToken(gomock.Any()).Return(nil, jwt.ErrUnexpectedPurpose)
+
+	err := svc.RespondByLink(context.Background(), "access-token", dto.RSVPRequest{Status: domain.AttendeeStatusAccepted})
+	if !errors.Is(err, domain.ErrInvalidRSVPLink) {
+		t.Fatalf("expected ErrInvalidRSVPLink, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/event/worker/reminder"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+)
+
+//go:generate mockgen -source=batch.go -destination=../mocks/batch_service_mocks.go -package=mocks
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+var (
+	// errBatchFailed rolls back an atomic batch once one of its operations
+	// has failed.
+	errBatchFailed   = errors.New("batch operation failed")
+	errUnknownMethod = errors.New("unknown batch method")
+)
+
+type Batch struct {
+	event *Event
+	tx    Transactor
+}
+
+func NewBatch(event *Event, tx Transactor) *Batch {
+	return &Batch{event: event, tx: tx}
+}
+
+// Batch applies the operations in order and reports the outcome of each in
+// its result. Reminders are only scheduled once the events are stored for
+// good.
+func (b *Batch) Batch(ctx context.Context, req dto.BatchRequest, userID uuid.UUID) (dto.BatchResponse, error) {
+	const op = "service.event.Batch"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	mode := req.Mode
+	if mode == "" {
+		mode = dto.BatchAtomic
+	}
+
+	results := make([]dto.BatchResult, len(req.Operations))
+	reminders := make([]*reminder.Task, len(req.Operations))
+
+	if mode == dto.BatchBestEffort {
+		for i, operation := range req.Operations {
+			results[i], reminders[i] = b.apply(ctx, i, operation, userID, req.Strict)
+		}
+	} else {
+		err := b.tx.WithinTx(ctx, func(ctx context.Context) error {
+			for i, operation := range req.Operations {
+				results[i], reminders[i] = b.apply(ctx, i, operation, userID, req.Strict)
+				if results[i].Err != nil {
+					return errBatchFailed
+				}
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errBatchFailed) {
+			return dto.BatchResponse{}, errutils.Wrap(op, err)
+		}
+
+		if err != nil {
+			for i := range results {
+				if results[i].Err == nil {
+					results[i] = dto.BatchResult{Index: i, Err: domain.ErrBatchRolledBack}
+				}
+			}
+		}
+	}
+
+	for i := range results {
+		if results[i].Err == nil {
+			scheduleReminder(ctx, b.event.reminders, reminders[i])
+		}
+	}
+
+	return dto.BatchResponse{Mode: mode, Results: results}, nil
+}
+
+func (b *Batch) apply(ctx context.Context, index int, operation dto.BatchOperation, userID uuid.UUID, strict bool) (dto.BatchResult, *reminder.Task) {
+	result := dto.BatchResult{Index: index}
+	var pending *reminder.Task
+
+	switch operation.Method {
+	case dto.BatchCreate:
+		var resp dto.CreateEventResponse
+		resp, pending, result.Err = b.event.createEvent(ctx, *operation.Create, userID, strict)
+		result.Conflicts = resp.Conflicts
+		if result.Err == nil {
+			result.EventID = &resp.ID
+			result.Version = 1 // new events start at the column default
+		}
+	case dto.BatchUpdate:
+		var resp dto.UpdateEventResponse
+		resp, pending, result.Err = b.event.updateEvent(ctx, *operation.Update, operation.EventID, userID, operation.Version, strict)
+		result.EventID = &operation.EventID
+		result.Version, result.Conflicts = resp.Version, resp.Conflicts
+	case dto.BatchDelete:
+		result.EventID = &operation.EventID
+		result.Err = b.event.DeleteEvent(ctx, operation.EventID, userID, operation.Version)
+	default:
+		result.Err = errUnknownMethod
+	}
+
+	return result, pending
+}
@@ -0,0 +1,146 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ilam072/event-calendar/internal/event/mocks"
+	"github.com/ilam072/event-calendar/internal/event/repo"
+	"github.com/ilam072/event-calendar/internal/event/service"
+	"github.com/ilam072/event-calendar/internal/event/worker/reminder"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+)
+
+// runTx runs the batch without a database, returning what fn returns as a
+// real transaction would.
+func runTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestBatch_AtomicCommit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	reminderChan := make(chan reminder.Task, 1)
+	svc := service.NewBatch(service.NewEvent(mockRepo, reminderChan), mockTx)
+
+	userID := uuid.New()
+	createdID := uuid.New()
+	deletedID := uuid.New()
+	remindAt := time.Now().Add(time.Hour)
+
+	mockTx.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runTx)
+	mockRepo.EXPECT().CreateEvent(gomock.Any(), gomock.Any()).Return(createdID, nil)
+	mockRepo.EXPECT().DeleteEvent(gomock.Any(), deletedID, userID, 2).Return(nil)
+
+	req := dto.BatchRequest{Operations: []dto.BatchOperation{
+		{Method: dto.BatchCreate, Create: &dto.CreateEventRequest{Date: time.Now(), Description: "New", RemindAt: &remindAt}},
+		{Method: dto.BatchDelete, EventID: deletedID, Version: 2},
+	}}
+
+	resp, err := svc.Batch(context.Background(), req, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Mode != dto.BatchAtomic {
+		t.Fatalf("expected atomic mode by default, got %q", resp.Mode)
+	}
+	for _, result := range resp.Results {
+		if result.Err != nil {
+			t.Fatalf("unexpected error in result %d: %v", result.Index, result.Err)
+		}
+	}
+	if *resp.Results[0].EventID != createdID {
+		t.Fatalf("expected created event id %s, got %s", createdID, resp.Results[0].EventID)
+	}
+
+	select {
+	case task := <-reminderChan:
+		if task.EventID != createdID {
+			t.Fatalf("expected reminder for %s, got %s", createdID, task.EventID)
+		}
+	default:
+		t.Fatalf("expected reminder to be scheduled after commit")
+	}
+}
+
+func TestBatch_AtomicRollback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	reminderChan := make(chan reminder.Task)
+	svc := service.NewBatch(service.NewEvent(mockRepo, reminderChan), mockTx)
+
+	remindAt := time.Now().Add(time.Hour)
+
+	mockTx.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runTx)
+	mockRepo.EXPECT().CreateEvent(gomock.Any(), gomock.Any()).Return(uuid.New(), nil)
+	mockRepo.EXPECT().DeleteEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(repo.ErrEventNotFound)
+
+	req := dto.BatchRequest{Operations: []dto.BatchOperation{
+		{Method: dto.BatchCreate, Create: &dto.CreateEventRequest{Date: time.Now(), Description: "New", RemindAt: &remindAt}},
+		{Method: dto.BatchDelete, EventID: uuid.New()},
+		{Method: dto.BatchDelete, EventID: uuid.New()},
+	}}
+
+	resp, err := svc.Batch(context.Background(), req, uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !errors.Is(resp.Results[0].Err, domain.ErrBatchRolledBack) {
+		t.Fatalf("expected created event to be rolled back, got %v", resp.Results[0].Err)
+	}
+	if !errors.Is(resp.Results[1].Err, domain.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", resp.Results[1].Err)
+	}
+	if !errors.Is(resp.Results[2].Err, domain.ErrBatchRolledBack) {
+		t.Fatalf("expected skipped operation to be rolled back, got %v", resp.Results[2].Err)
+	}
+}
+
+func TestBatch_BestEffort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	reminderChan := make(chan reminder.Task)
+	svc := service.NewBatch(service.NewEvent(mockRepo, reminderChan), mockTx)
+
+	eventID := uuid.New()
+
+	mockRepo.EXPECT().GetEvent(gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.Event{Version: 2}, nil).Times(2)
+	gomock.InOrder(
+		mockRepo.EXPECT().UpdateEvent(gomock.Any(), gomock.Any()).Return(0, repo.ErrVersionMismatch),
+		mockRepo.EXPECT().UpdateEvent(gomock.Any(), gomock.Any()).Return(3, nil),
+	)
+
+	update := &dto.UpdateEventRequest{Date: time.Now(), Description: "Updated"}
+	req := dto.BatchRequest{Mode: dto.BatchBestEffort, Operations: []dto.BatchOperation{
+		{Method: dto.BatchUpdate, EventID: uuid.New(), Version: 1, Update: update},
+		{Method: dto.BatchUpdate, EventID: eventID, Update: update},
+	}}
+
+	resp, err := svc.Batch(context.Background(), req, uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !errors.Is(resp.Results[0].Err, domain.ErrVersionMismatch) {
+		t.Fatalf("expected ErrVersionMismatch, got %v", resp.Results[0].Err)
+	}
+	if resp.Results[1].Err != nil || resp.Results[1].Version != 3 {
+		t.Fatalf("expected second update to succeed at version 3, got %+v", resp.Results[1])
+	}
+}
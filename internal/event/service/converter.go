@@ -1,22 +1,100 @@
 package service
 
 import (
+	"github.com/google/uuid"
 	"github.com/ilam072/event-calendar/internal/types/domain"
 	"github.com/ilam072/event-calendar/internal/types/dto"
 )
 
-func domainToGetEventsResponse(domainEvents []domain.Event) dto.GetEventsResponse {
+func domainToGetEventsResponse(domainEvents []domain.Event, attendees map[uuid.UUID][]domain.Attendee) dto.GetEventsResponse {
 	events := make([]dto.Event, 0, len(domainEvents))
 	for _, e := range domainEvents {
-		events = append(events, dto.Event{
-			ID:          e.ID,
-			UserID:      e.UserID,
-			Date:        e.Date,
-			Description: e.Description,
-		})
+		event := domainToEvent(e)
+		event.Attendees = domainToAttendees(attendees[e.ID])
+		events = append(events, event)
 	}
 
 	return dto.GetEventsResponse{
 		Events: events,
 	}
 }
+
+func domainToEvents(domainEvents []domain.Event) []dto.Event {
+	events := make([]dto.Event, 0, len(domainEvents))
+	for _, e := range domainEvents {
+		events = append(events, domainToEvent(e))
+	}
+	return events
+}
+
+func domainToEvent(e domain.Event) dto.Event {
+	return dto.Event{
+		ID:          e.ID,
+		UserID:      e.UserID,
+		CalendarID:  e.CalendarID,
+		Date:        e.Date,
+		StartsAt:    e.StartsAt,
+		EndsAt:      e.EndsAt,
+		Description: e.Description,
+		Version:     e.Version,
+		DeletedAt:   e.DeletedAt,
+	}
+}
+
+func domainToAttendees(domainAttendees []domain.Attendee) []dto.Attendee {
+	if len(domainAttendees) == 0 {
+		return nil
+	}
+
+	attendees := make([]dto.Attendee, 0, len(domainAttendees))
+	for _, a := range domainAttendees {
+		attendees = append(attendees, dto.Attendee{
+			ID:          a.ID,
+			UserID:      a.UserID,
+			Email:       a.Email,
+			Status:      a.Status,
+			RespondedAt: a.RespondedAt,
+		})
+	}
+
+	return attendees
+}
+
+func domainToRevisions(domainRevisions []domain.EventRevision) []dto.EventRevision {
+	revisions := make([]dto.EventRevision, 0, len(domainRevisions))
+	for _, r := range domainRevisions {
+		changes := make(map[string]dto.FieldChange, len(r.Changes))
+		for name, c := range r.Changes {
+			changes[name] = dto.FieldChange{From: c.From, To: c.To}
+		}
+
+		revisions = append(revisions, dto.EventRevision{
+			ID:        r.ID,
+			Action:    r.Action,
+			ActorID:   r.ActorID,
+			Version:   r.Version,
+			Before:    domainToSnapshot(r.Before),
+			After:     domainToSnapshot(r.After),
+			Changes:   changes,
+			RequestID: r.RequestID,
+			CreatedAt: r.CreatedAt,
+		})
+	}
+	return revisions
+}
+
+func domainToSnapshot(s *domain.EventSnapshot) *dto.EventSnapshot {
+	if s == nil {
+		return nil
+	}
+
+	return &dto.EventSnapshot{
+		CalendarID:  s.CalendarID,
+		Date:        s.Date,
+		StartsAt:    s.StartsAt,
+		EndsAt:      s.EndsAt,
+		Description: s.Description,
+		RemindAt:    s.RemindAt,
+		DeletedAt:   s.DeletedAt,
+	}
+}
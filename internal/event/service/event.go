@@ -9,19 +9,30 @@ import (
 	"github.com/ilam072/event-calendar/internal/types/domain"
 	"github.com/ilam072/event-calendar/internal/types/dto"
 	"github.com/ilam072/event-calendar/pkg/errutils"
+	"github.com/ilam072/event-calendar/pkg/requestid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 	"time"
 )
 
 //go:generate mockgen -source=event.go -destination=../mocks/service_mocks.go -package=mocks
 type EventRepo interface {
 	CreateEvent(ctx context.Context, event domain.Event) (uuid.UUID, error)
-	UpdateEvent(ctx context.Context, event domain.Event) error
-	DeleteEvent(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) error
-	GetEventsForDay(ctx context.Context, userID uuid.UUID, date time.Time) ([]domain.Event, error)
-	GetEventsForWeek(ctx context.Context, userID uuid.UUID, start time.Time) ([]domain.Event, error)
-	GetEventsForMonth(ctx context.Context, userID uuid.UUID, start time.Time) ([]domain.Event, error)
+	GetEvent(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) (domain.Event, error)
+	UpdateEvent(ctx context.Context, event domain.Event) (int, error)
+	DeleteEvent(ctx context.Context, eventID uuid.UUID, userID uuid.UUID, version int) error
+	GetEventsForDay(ctx context.Context, userID uuid.UUID, date time.Time, calendarIDs []uuid.UUID) ([]domain.Event, error)
+	GetEventsForWeek(ctx context.Context, userID uuid.UUID, start time.Time, calendarIDs []uuid.UUID) ([]domain.Event, error)
+	GetEventsForMonth(ctx context.Context, userID uuid.UUID, start time.Time, calendarIDs []uuid.UUID) ([]domain.Event, error)
+	GetAttendees(ctx context.Context, eventIDs []uuid.UUID) ([]domain.Attendee, error)
+	GetOverlappingEvents(ctx context.Context, userID uuid.UUID, startsAt time.Time, endsAt time.Time, excludeID uuid.UUID) ([]domain.Event, error)
+	GetHistory(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) ([]domain.EventRevision, error)
+	GetRevision(ctx context.Context, eventID uuid.UUID, revisionID int64, userID uuid.UUID) (domain.EventRevision, error)
+	RevertEvent(ctx context.Context, event domain.Event) (int, error)
 }
 
+var tracer = otel.Tracer("github.com/ilam072/event-calendar/internal/event/service")
+
 type Event struct {
 	eventRepo EventRepo
 	reminders chan<- reminder.Task
@@ -34,95 +45,419 @@ func NewEvent(repo EventRepo, reminderChan chan<- reminder.Task) *Event {
 	}
 }
 
-func (e *Event) CreateEvent(ctx context.Context, event dto.CreateEventRequest, userID uuid.UUID) (uuid.UUID, error) {
+// CreateEvent creates the event and reports the events it overlaps. In strict
+// mode an overlapping event is not created and ErrEventConflict is returned
+// together with the conflicts.
+func (e *Event) CreateEvent(ctx context.Context, event dto.CreateEventRequest, userID uuid.UUID, strict bool) (dto.CreateEventResponse, error) {
 	const op = "service.event.Create"
 
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	resp, pending, err := e.createEvent(ctx, event, userID, strict)
+	if err != nil {
+		return resp, errutils.Wrap(op, err)
+	}
+
+	scheduleReminder(ctx, e.reminders, pending)
+
+	return resp, nil
+}
+
+// createEvent is CreateEvent without scheduling the reminder, so that batches
+// can schedule reminders once their transaction is committed. The reminder to
+// schedule, if any, is returned.
+func (e *Event) createEvent(ctx context.Context, event dto.CreateEventRequest, userID uuid.UUID, strict bool) (dto.CreateEventResponse, *reminder.Task, error) {
 	domainEvent := domain.Event{
 		UserID:      userID,
-		Date:        event.Date,
+		CalendarID:  calendarIDOrNil(event.CalendarID),
+		Date:        eventDate(event.Date, event.StartsAt),
+		StartsAt:    event.StartsAt,
+		EndsAt:      event.EndsAt,
 		Description: event.Description,
-		RemindAt:    event.RemindAt,
+		RemindAt:    normalizeTime(event.RemindAt),
 	}
 
-	id, err := e.eventRepo.CreateEvent(ctx, domainEvent)
+	conflicts, err := e.findConflicts(ctx, domainEvent)
 	if err != nil {
-		return uuid.Nil, errutils.Wrap(op, err)
+		return dto.CreateEventResponse{}, nil, err
+	}
+	if strict && len(conflicts) > 0 {
+		return dto.CreateEventResponse{Conflicts: conflicts}, nil, domain.ErrEventConflict
 	}
 
-	if event.RemindAt != nil && !event.RemindAt.IsZero() {
-		e.reminders <- reminder.Task{
-			EventID:  id,
-			UserID:   userID,
-			RemindAt: *event.RemindAt,
+	id, err := e.eventRepo.CreateEvent(ctx, domainEvent)
+	if err != nil {
+		if errors.Is(err, repo.ErrCalendarNotFound) {
+			return dto.CreateEventResponse{}, nil, domain.ErrCalendarNotFound
 		}
+		if errors.Is(err, repo.ErrReadOnly) {
+			return dto.CreateEventResponse{}, nil, domain.ErrForbidden
+		}
+		return dto.CreateEventResponse{}, nil, err
 	}
 
-	return id, nil
+	// new events start at the column default
+	domainEvent.ID, domainEvent.Version = id, 1
+
+	return dto.CreateEventResponse{ID: id, Conflicts: conflicts}, reminderAfterWrite(domainEvent, domainEvent), nil
 }
 
-func (e *Event) UpdateEvent(ctx context.Context, event dto.UpdateEventRequest, eventID uuid.UUID, userID uuid.UUID) error {
+// UpdateEvent behaves like CreateEvent with regard to overlapping events. A
+// non-zero version makes the update fail with ErrVersionMismatch if the
+// event has changed since.
+func (e *Event) UpdateEvent(ctx context.Context, event dto.UpdateEventRequest, eventID uuid.UUID, userID uuid.UUID, version int, strict bool) (dto.UpdateEventResponse, error) {
 	const op = "service.event.Update"
 
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	resp, pending, err := e.updateEvent(ctx, event, eventID, userID, version, strict)
+	if err != nil {
+		return resp, errutils.Wrap(op, err)
+	}
+
+	scheduleReminder(ctx, e.reminders, pending)
+
+	return resp, nil
+}
+
+// updateEvent is UpdateEvent without scheduling the reminder, see
+// createEvent.
+func (e *Event) updateEvent(ctx context.Context, event dto.UpdateEventRequest, eventID uuid.UUID, userID uuid.UUID, version int, strict bool) (dto.UpdateEventResponse, *reminder.Task, error) {
+	current, err := e.eventRepo.GetEvent(ctx, eventID, userID)
+	if err != nil {
+		return dto.UpdateEventResponse{}, nil, updateErr(err)
+	}
+
 	domainEvent := domain.Event{
 		ID:          eventID,
 		UserID:      userID,
-		Date:        event.Date,
+		CalendarID:  calendarIDOrNil(event.CalendarID),
+		Date:        eventDate(event.Date, event.StartsAt),
+		StartsAt:    event.StartsAt,
+		EndsAt:      event.EndsAt,
 		Description: event.Description,
-		RemindAt:    event.RemindAt,
+		RemindAt:    normalizeTime(event.RemindAt),
+		Version:     version,
+	}
+
+	conflicts, err := e.findConflicts(ctx, domainEvent)
+	if err != nil {
+		return dto.UpdateEventResponse{}, nil, err
 	}
+	if strict && len(conflicts) > 0 {
+		return dto.UpdateEventResponse{Conflicts: conflicts}, nil, domain.ErrEventConflict
+	}
+
+	newVersion, err := e.eventRepo.UpdateEvent(ctx, domainEvent)
+	if err != nil {
+		return dto.UpdateEventResponse{}, nil, updateErr(err)
+	}
+	domainEvent.Version = newVersion
+
+	return dto.UpdateEventResponse{Version: newVersion, Conflicts: conflicts}, reminderAfterWrite(current, domainEvent), nil
+}
+
+// PatchEvent applies a merge patch to the event. Without a version the patch
+// is applied to the version it was read at, so concurrent writes are not
+// overwritten.
+func (e *Event) PatchEvent(ctx context.Context, patch dto.PatchEventRequest, eventID uuid.UUID, userID uuid.UUID, version int, strict bool) (dto.UpdateEventResponse, error) {
+	const op = "service.event.Patch"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
 
-	if err := e.eventRepo.UpdateEvent(ctx, domainEvent); err != nil {
+	current, err := e.eventRepo.GetEvent(ctx, eventID, userID)
+	if err != nil {
 		if errors.Is(err, repo.ErrEventNotFound) {
-			return errutils.Wrap(op, domain.ErrEventNotFound)
+			return dto.UpdateEventResponse{}, errutils.Wrap(op, domain.ErrEventNotFound)
 		}
-		return errutils.Wrap(op, err)
+		return dto.UpdateEventResponse{}, errutils.Wrap(op, err)
 	}
 
-	return nil
+	if version != 0 && version != current.Version {
+		return dto.UpdateEventResponse{}, errutils.Wrap(op, domain.ErrVersionMismatch)
+	}
+
+	domainEvent, err := applyPatch(current, patch)
+	if err != nil {
+		return dto.UpdateEventResponse{}, errutils.Wrap(op, err)
+	}
+	domainEvent.UserID = userID
+
+	conflicts, err := e.findConflicts(ctx, domainEvent)
+	if err != nil {
+		return dto.UpdateEventResponse{}, errutils.Wrap(op, err)
+	}
+	if strict && len(conflicts) > 0 {
+		return dto.UpdateEventResponse{Conflicts: conflicts}, errutils.Wrap(op, domain.ErrEventConflict)
+	}
+
+	newVersion, err := e.eventRepo.UpdateEvent(ctx, domainEvent)
+	if err != nil {
+		return dto.UpdateEventResponse{}, errutils.Wrap(op, updateErr(err))
+	}
+	domainEvent.Version = newVersion
+
+	scheduleReminder(ctx, e.reminders, reminderAfterWrite(current, domainEvent))
+
+	return dto.UpdateEventResponse{Version: newVersion, Conflicts: conflicts}, nil
 }
 
-func (e *Event) DeleteEvent(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) error {
+func (e *Event) GetEvent(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) (dto.Event, error) {
+	const op = "service.event.Get"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	event, err := e.eventRepo.GetEvent(ctx, eventID, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrEventNotFound) {
+			return dto.Event{}, errutils.Wrap(op, domain.ErrEventNotFound)
+		}
+		return dto.Event{}, errutils.Wrap(op, err)
+	}
+
+	attendees, err := e.getAttendees(ctx, []domain.Event{event})
+	if err != nil {
+		return dto.Event{}, errutils.Wrap(op, err)
+	}
+
+	resp := domainToEvent(event)
+	resp.Attendees = domainToAttendees(attendees[event.ID])
+
+	return resp, nil
+}
+
+// DeleteEvent deletes the event. A non-zero version makes the delete fail
+// with ErrVersionMismatch if the event has changed since.
+func (e *Event) DeleteEvent(ctx context.Context, eventID uuid.UUID, userID uuid.UUID, version int) error {
 	const op = "service.event.Delete"
 
-	if err := e.eventRepo.DeleteEvent(ctx, eventID, userID); err != nil {
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	if err := e.eventRepo.DeleteEvent(ctx, eventID, userID, version); err != nil {
 		if errors.Is(err, repo.ErrEventNotFound) {
 			return errutils.Wrap(op, domain.ErrEventNotFound)
 		}
+		if errors.Is(err, repo.ErrVersionMismatch) {
+			return errutils.Wrap(op, domain.ErrVersionMismatch)
+		}
+		if errors.Is(err, repo.ErrReadOnly) {
+			return errutils.Wrap(op, domain.ErrForbidden)
+		}
 		return errutils.Wrap(op, err)
 	}
 
 	return nil
 }
 
-func (e *Event) GetEventsForDay(ctx context.Context, userID uuid.UUID, date time.Time) (dto.GetEventsResponse, error) {
+func (e *Event) GetEventsForDay(ctx context.Context, userID uuid.UUID, date time.Time, calendarIDs []uuid.UUID) (dto.GetEventsResponse, error) {
 	const op = "service.event.GetForDay"
 
-	domainEvents, err := e.eventRepo.GetEventsForDay(ctx, userID, date)
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	domainEvents, err := e.eventRepo.GetEventsForDay(ctx, userID, date, calendarIDs)
 	if err != nil {
 		return dto.GetEventsResponse{}, errutils.Wrap(op, err)
 	}
 
-	return domainToGetEventsResponse(domainEvents), nil
+	attendees, err := e.getAttendees(ctx, domainEvents)
+	if err != nil {
+		return dto.GetEventsResponse{}, errutils.Wrap(op, err)
+	}
+
+	return domainToGetEventsResponse(domainEvents, attendees), nil
 }
 
-func (e *Event) GetEventsForWeek(ctx context.Context, userID uuid.UUID, date time.Time) (dto.GetEventsResponse, error) {
+func (e *Event) GetEventsForWeek(ctx context.Context, userID uuid.UUID, date time.Time, calendarIDs []uuid.UUID) (dto.GetEventsResponse, error) {
 	const op = "service.event.GetForWeek"
 
-	domainEvents, err := e.eventRepo.GetEventsForWeek(ctx, userID, date)
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	domainEvents, err := e.eventRepo.GetEventsForWeek(ctx, userID, date, calendarIDs)
+	if err != nil {
+		return dto.GetEventsResponse{}, errutils.Wrap(op, err)
+	}
+
+	attendees, err := e.getAttendees(ctx, domainEvents)
 	if err != nil {
 		return dto.GetEventsResponse{}, errutils.Wrap(op, err)
 	}
 
-	return domainToGetEventsResponse(domainEvents), nil
+	return domainToGetEventsResponse(domainEvents, attendees), nil
 }
 
-func (e *Event) GetEventsForMonth(ctx context.Context, userID uuid.UUID, date time.Time) (dto.GetEventsResponse, error) {
+func (e *Event) GetEventsForMonth(ctx context.Context, userID uuid.UUID, date time.Time, calendarIDs []uuid.UUID) (dto.GetEventsResponse, error) {
 	const op = "service.event.GetForMonth"
 
-	domainEvents, err := e.eventRepo.GetEventsForMonth(ctx, userID, date)
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	domainEvents, err := e.eventRepo.GetEventsForMonth(ctx, userID, date, calendarIDs)
+	if err != nil {
+		return dto.GetEventsResponse{}, errutils.Wrap(op, err)
+	}
+
+	attendees, err := e.getAttendees(ctx, domainEvents)
 	if err != nil {
 		return dto.GetEventsResponse{}, errutils.Wrap(op, err)
 	}
 
-	return domainToGetEventsResponse(domainEvents), nil
+	return domainToGetEventsResponse(domainEvents, attendees), nil
+}
+
+// scheduleReminder hands the reminder, if any, to the reminder worker.
+func scheduleReminder(ctx context.Context, reminders chan<- reminder.Task, task *reminder.Task) {
+	if task == nil {
+		return
+	}
+
+	task.RequestID = requestid.FromContext(ctx)
+	task.Link = trace.SpanContextFromContext(ctx)
+	reminders <- *task
+}
+
+// reminderAfterWrite returns the reminder to schedule for the event once it
+// is written as updated. Every write bumps the version, which makes the
+// reminder worker drop the tasks scheduled before, so a reminder that has not
+// been sent is scheduled again. It is sent to the owner of the event, not to
+// whoever changed it.
+func reminderAfterWrite(current domain.Event, updated domain.Event) *reminder.Task {
+	remindAt := updated.RemindAt
+	if remindAt == nil || remindAt.IsZero() {
+		return nil
+	}
+	// the update keeps the reminder marked as sent
+	if current.Sent && current.RemindAt != nil && current.RemindAt.Equal(*remindAt) {
+		return nil
+	}
+
+	return &reminder.Task{
+		EventID:  current.ID,
+		UserID:   current.UserID,
+		RemindAt: *remindAt,
+		Version:  updated.Version,
+	}
+}
+
+// findConflicts returns the events of the user that overlap a timed event.
+func (e *Event) findConflicts(ctx context.Context, event domain.Event) ([]dto.Event, error) {
+	if event.StartsAt == nil || event.EndsAt == nil {
+		return nil, nil
+	}
+
+	overlapping, err := e.eventRepo.GetOverlappingEvents(ctx, event.UserID, *event.StartsAt, *event.EndsAt, event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domainToEvents(overlapping), nil
+}
+
+// getAttendees loads the attendees of the events grouped by event ID.
+func (e *Event) getAttendees(ctx context.Context, events []domain.Event) (map[uuid.UUID][]domain.Attendee, error) {
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	eventIDs := make([]uuid.UUID, 0, len(events))
+	for _, event := range events {
+		eventIDs = append(eventIDs, event.ID)
+	}
+
+	attendees, err := e.eventRepo.GetAttendees(ctx, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byEvent := make(map[uuid.UUID][]domain.Attendee, len(events))
+	for _, attendee := range attendees {
+		byEvent[attendee.EventID] = append(byEvent[attendee.EventID], attendee)
+	}
+
+	return byEvent, nil
+}
+
+// applyPatch returns the event with the members of the patch applied. The
+// calendar is only set when the patch moves the event.
+func applyPatch(event domain.Event, patch dto.PatchEventRequest) (domain.Event, error) {
+	event.CalendarID = calendarIDOrNil(patch.CalendarID)
+
+	if patch.Date != nil {
+		event.Date = *patch.Date
+	}
+	if patch.Description != nil {
+		event.Description = *patch.Description
+	}
+	if patch.RemindAt.Set {
+		event.RemindAt = normalizeTime(patch.RemindAt.Ptr())
+	}
+	if patch.StartsAt.Set {
+		event.StartsAt = patch.StartsAt.Ptr()
+	}
+	if patch.EndsAt.Set {
+		event.EndsAt = patch.EndsAt.Ptr()
+	}
+
+	if (event.StartsAt == nil) != (event.EndsAt == nil) {
+		return domain.Event{}, domain.ErrInvalidTimeRange
+	}
+	if event.StartsAt != nil && !event.EndsAt.After(*event.StartsAt) {
+		return domain.Event{}, domain.ErrInvalidTimeRange
+	}
+
+	event.Date = eventDate(event.Date, event.StartsAt)
+
+	return event, nil
+}
+
+// updateErr maps the errors of EventRepo.UpdateEvent to domain errors.
+func updateErr(err error) error {
+	switch {
+	case errors.Is(err, repo.ErrEventNotFound):
+		return domain.ErrEventNotFound
+	case errors.Is(err, repo.ErrVersionMismatch):
+		return domain.ErrVersionMismatch
+	case errors.Is(err, repo.ErrCalendarNotFound):
+		return domain.ErrCalendarNotFound
+	case errors.Is(err, repo.ErrReadOnly):
+		return domain.ErrForbidden
+	default:
+		return err
+	}
+}
+
+// eventDate returns the day a timed event starts on, as seen by the client
+// that sent it, so that it is listed under that day.
+func eventDate(date time.Time, startsAt *time.Time) time.Time {
+	if startsAt == nil {
+		return date
+	}
+
+	year, month, day := startsAt.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// normalizeTime returns t in UTC at the microsecond precision of Postgres.
+// Timestamp columns drop the offset rather than convert the time, so times
+// have to be stored in UTC.
+func normalizeTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	normalized := t.UTC().Truncate(time.Microsecond)
+	return &normalized
+}
+
+func calendarIDOrNil(calendarID *uuid.UUID) uuid.UUID {
+	if calendarID == nil {
+		return uuid.Nil
+	}
+	return *calendarID
 }
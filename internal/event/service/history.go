@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/event/repo"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+)
+
+func (e *Event) GetHistory(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) (dto.GetHistoryResponse, error) {
+	const op = "service.event.GetHistory"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	revisions, err := e.eventRepo.GetHistory(ctx, eventID, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrEventNotFound) {
+			return dto.GetHistoryResponse{}, errutils.Wrap(op, domain.ErrEventNotFound)
+		}
+		return dto.GetHistoryResponse{}, errutils.Wrap(op, err)
+	}
+
+	return dto.GetHistoryResponse{Revisions: domainToRevisions(revisions)}, nil
+}
+
+// RevertEvent restores the event to the state it was in after the revision.
+// Like PatchEvent, it applies to the current version unless a version is
+// given.
+func (e *Event) RevertEvent(ctx context.Context, eventID uuid.UUID, revisionID int64, userID uuid.UUID, version int) (dto.UpdateEventResponse, error) {
+	const op = "service.event.Revert"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	current, err := e.eventRepo.GetEvent(ctx, eventID, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrEventNotFound) {
+			return dto.UpdateEventResponse{}, errutils.Wrap(op, domain.ErrEventNotFound)
+		}
+		return dto.UpdateEventResponse{}, errutils.Wrap(op, err)
+	}
+
+	if version != 0 && version != current.Version {
+		return dto.UpdateEventResponse{}, errutils.Wrap(op, domain.ErrVersionMismatch)
+	}
+
+	revision, err := e.eventRepo.GetRevision(ctx, eventID, revisionID, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrRevisionNotFound) {
+			return dto.UpdateEventResponse{}, errutils.Wrap(op, domain.ErrRevisionNotFound)
+		}
+		return dto.UpdateEventResponse{}, errutils.Wrap(op, updateErr(err))
+	}
+	if revision.After == nil {
+		return dto.UpdateEventResponse{}, errutils.Wrap(op, domain.ErrRevisionNotFound)
+	}
+
+	domainEvent := domain.Event{
+		ID:          eventID,
+		UserID:      userID,
+		CalendarID:  revision.After.CalendarID,
+		Date:        revision.After.Date,
+		StartsAt:    revision.After.StartsAt,
+		EndsAt:      revision.After.EndsAt,
+		Description: revision.After.Description,
+		RemindAt:    revision.After.RemindAt,
+		Version:     current.Version,
+	}
+
+	newVersion, err := e.eventRepo.RevertEvent(ctx, domainEvent)
+	if err != nil {
+		return dto.UpdateEventResponse{}, errutils.Wrap(op, updateErr(err))
+	}
+	domainEvent.Version = newVersion
+
+	scheduleReminder(ctx, e.reminders, reminderAfterWrite(current, domainEvent))
+
+	return dto.UpdateEventResponse{Version: newVersion}, nil
+}
@@ -0,0 +1,153 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ilam072/event-calendar/internal/event/mocks"
+	"github.com/ilam072/event-calendar/internal/event/repo"
+	"github.com/ilam072/event-calendar/internal/event/service"
+	"github.com/ilam072/event-calendar/internal/event/worker/reminder"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+)
+
+func TestGetHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	svc := service.NewEvent(mockRepo, make(chan reminder.Task, 1))
+
+	eventID := uuid.New()
+	userID := uuid.New()
+	before := &domain.EventSnapshot{Description: "Old"}
+	after := &domain.EventSnapshot{Description: "New"}
+
+	mockRepo.EXPECT().
+		GetHistory(gomock.Any(), eventID, userID).
+		Return([]domain.EventRevision{
+			{ID: 1, Action: domain.HistoryCreate, Version: 1, After: before, Changes: domain.Diff(nil, before)},
+			{ID: 2, Action: domain.HistoryUpdate, Version: 2, Before: before, After: after, Changes: domain.Diff(before, after), RequestID: "req-1"},
+		}, nil)
+
+	resp, err := svc.GetHistory(context.Background(), eventID, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Revisions) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(resp.Revisions))
+	}
+
+	update := resp.Revisions[1]
+	if update.Before == nil || update.After == nil || update.RequestID != "req-1" {
+		t.Fatalf("unexpected revision %+v", update)
+	}
+	if len(update.Changes) != 1 || update.Changes["description"].To != "New" {
+		t.Fatalf("expected only description to change, got %+v", update.Changes)
+	}
+}
+
+func TestGetHistory_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	svc := service.NewEvent(mockRepo, make(chan reminder.Task, 1))
+
+	mockRepo.EXPECT().
+		GetHistory(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, repo.ErrEventNotFound)
+
+	_, err := svc.GetHistory(context.Background(), uuid.New(), uuid.New())
+	if !errors.Is(err, domain.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+}
+
+func TestRevertEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	reminderChan := make(chan reminder.Task, 1)
+	svc := service.NewEvent(mockRepo, reminderChan)
+
+	eventID := uuid.New()
+	userID := uuid.New()
+	calendarID := uuid.New()
+	remindAt := time.Now().Add(time.Hour)
+
+	mockRepo.EXPECT().
+		GetEvent(gomock.Any(), eventID, userID).
+		Return(domain.Event{ID: eventID, CalendarID: calendarID, Description: "New", Version: 4}, nil)
+	mockRepo.EXPECT().
+		GetRevision(gomock.Any(), eventID, int64(7), userID).
+		Return(domain.EventRevision{ID: 7, After: &domain.EventSnapshot{CalendarID: calendarID, Description: "Old", RemindAt: &remindAt}}, nil)
+	mockRepo.EXPECT().
+		RevertEvent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, e domain.Event) (int, error) {
+			if e.Description != "Old" || e.Version != 4 || e.UserID != userID {
+				t.Fatalf("unexpected event %+v", e)
+			}
+			return 5, nil
+		})
+
+	resp, err := svc.RevertEvent(context.Background(), eventID, 7, userID, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Version != 5 {
+		t.Fatalf("expected version 5, got %d", resp.Version)
+	}
+
+	select {
+	case task := <-reminderChan:
+		if !task.RemindAt.Equal(remindAt) {
+			t.Fatalf("unexpected reminder %+v", task)
+		}
+	default:
+		t.Fatalf("expected the restored reminder to be scheduled")
+	}
+}
+
+func TestRevertEvent_VersionMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	svc := service.NewEvent(mockRepo, make(chan reminder.Task, 1))
+
+	mockRepo.EXPECT().
+		GetEvent(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.Event{Version: 4}, nil)
+
+	_, err := svc.RevertEvent(context.Background(), uuid.New(), 7, uuid.New(), 3)
+	if !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Fatalf("expected ErrVersionMismatch, got %v", err)
+	}
+}
+
+func TestRevertEvent_PurgedRevision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	svc := service.NewEvent(mockRepo, make(chan reminder.Task, 1))
+
+	mockRepo.EXPECT().
+		GetEvent(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.Event{Version: 4}, nil)
+	mockRepo.EXPECT().
+		GetRevision(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.EventRevision{ID: 7, Action: domain.HistoryPurge}, nil)
+
+	_, err := svc.RevertEvent(context.Background(), uuid.New(), 7, uuid.New(), 0)
+	if !errors.Is(err, domain.ErrRevisionNotFound) {
+		t.Fatalf("expected ErrRevisionNotFound, got %v", err)
+	}
+}
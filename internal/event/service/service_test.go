@@ -2,7 +2,11 @@ package service_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/mock/gomock"
 	"testing"
 	"time"
@@ -15,6 +19,7 @@ import (
 	"github.com/ilam072/event-calendar/internal/event/worker/reminder"
 	"github.com/ilam072/event-calendar/internal/types/domain"
 	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/requestid"
 )
 
 func TestCreateEvent(t *testing.T) {
@@ -38,27 +43,29 @@ func TestCreateEvent(t *testing.T) {
 		RemindAt:    &remindAt,
 	}
 
+	stored := remindAt.UTC().Truncate(time.Microsecond)
 	mockRepo.
 		EXPECT().
 		CreateEvent(gomock.Any(), domain.Event{
 			UserID:      userID,
 			Date:        date,
 			Description: "Test",
-			RemindAt:    &remindAt,
+			RemindAt:    &stored,
 		}).
 		Return(eventID, nil)
 
-	id, err := svc.CreateEvent(context.Background(), req, userID)
+	ctx := requestid.NewContext(context.Background(), "req-1")
+	resp, err := svc.CreateEvent(ctx, req, userID, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if id != eventID {
-		t.Fatalf("expected %s, got %s", eventID, id)
+	if resp.ID != eventID {
+		t.Fatalf("expected %s, got %s", eventID, resp.ID)
 	}
 
 	select {
 	case task := <-reminderChan:
-		if task.EventID != eventID || task.UserID != userID {
+		if task.EventID != eventID || task.UserID != userID || task.Version != 1 || task.RequestID != "req-1" {
 			t.Fatalf("wrong reminder task sent")
 		}
 	default:
@@ -66,6 +73,111 @@ func TestCreateEvent(t *testing.T) {
 	}
 }
 
+func TestCreateEvent_LinksReminderToTrace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	reminderChan := make(chan reminder.Task, 1)
+	svc := service.NewEvent(mockRepo, reminderChan)
+
+	remindAt := time.Now().Add(10 * time.Minute)
+	mockRepo.EXPECT().CreateEvent(gomock.Any(), gomock.Any()).Return(uuid.New(), nil)
+
+	ctx, request := provider.Tracer("test").Start(context.Background(), "POST /api/v1/events")
+	_, err := svc.CreateEvent(ctx, dto.CreateEventRequest{Date: time.Now(), Description: "Test", RemindAt: &remindAt}, uuid.New(), false)
+	request.End()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 || spans[0].Name() != "service.event.Create" {
+		t.Fatalf("expected the service span, got %d spans", len(spans))
+	}
+	if spans[0].Parent().SpanID() != request.SpanContext().SpanID() {
+		t.Fatalf("service span is not a child of the request span")
+	}
+
+	task := <-reminderChan
+	if task.Link.TraceID() != request.SpanContext().TraceID() || task.Link.SpanID() != spans[0].SpanContext().SpanID() {
+		t.Fatalf("reminder task is not linked to the service span")
+	}
+}
+
+func TestCreateEvent_Timed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	svc := service.NewEvent(mockRepo, make(chan reminder.Task, 1))
+
+	userID, conflictID := uuid.New(), uuid.New()
+	startsAt := time.Date(2025, time.September, 1, 23, 30, 0, 0, time.FixedZone("UTC+3", 3*60*60))
+	endsAt := startsAt.Add(time.Hour)
+
+	mockRepo.
+		EXPECT().
+		CreateEvent(gomock.Any(), domain.Event{
+			UserID:      userID,
+			Date:        time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC),
+			StartsAt:    &startsAt,
+			EndsAt:      &endsAt,
+			Description: "Late call",
+		}).
+		Return(uuid.New(), nil)
+	mockRepo.
+		EXPECT().
+		GetOverlappingEvents(gomock.Any(), userID, startsAt, endsAt, uuid.Nil).
+		Return([]domain.Event{{ID: conflictID, StartsAt: &startsAt, EndsAt: &endsAt}}, nil)
+
+	resp, err := svc.CreateEvent(context.Background(), dto.CreateEventRequest{
+		StartsAt:    &startsAt,
+		EndsAt:      &endsAt,
+		Description: "Late call",
+	}, userID, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Conflicts) != 1 || resp.Conflicts[0].ID != conflictID {
+		t.Fatalf("expected conflict warning, got %+v", resp.Conflicts)
+	}
+}
+
+func TestCreateEvent_StrictConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	svc := service.NewEvent(mockRepo, make(chan reminder.Task, 1))
+
+	startsAt := time.Now()
+	endsAt := startsAt.Add(time.Hour)
+
+	mockRepo.
+		EXPECT().
+		GetOverlappingEvents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]domain.Event{{ID: uuid.New()}}, nil)
+
+	resp, err := svc.CreateEvent(context.Background(), dto.CreateEventRequest{
+		StartsAt:    &startsAt,
+		EndsAt:      &endsAt,
+		Description: "Standup",
+	}, uuid.New(), true)
+	if !errors.Is(err, domain.ErrEventConflict) {
+		t.Fatalf("expected ErrEventConflict, got %v", err)
+	}
+
+	if len(resp.Conflicts) != 1 {
+		t.Fatalf("expected conflicts in response, got %+v", resp.Conflicts)
+	}
+}
+
 func TestCreateEvent_RepoError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -85,7 +197,7 @@ func TestCreateEvent_RepoError(t *testing.T) {
 		CreateEvent(gomock.Any(), gomock.Any()).
 		Return(uuid.Nil, errors.New("db failure"))
 
-	_, err := svc.CreateEvent(context.Background(), req, userID)
+	_, err := svc.CreateEvent(context.Background(), req, userID, false)
 	if err == nil {
 		t.Fatalf("expected error, got nil")
 	}
@@ -115,15 +227,59 @@ func TestUpdateEvent(t *testing.T) {
 		RemindAt:    req.RemindAt,
 	}
 
+	mockRepo.EXPECT().GetEvent(gomock.Any(), eventID, userID).Return(domain.Event{ID: eventID, UserID: userID, Version: 1}, nil)
 	mockRepo.
 		EXPECT().
 		UpdateEvent(gomock.Any(), expected).
-		Return(nil)
+		Return(2, nil)
+
+	resp, err := svc.UpdateEvent(context.Background(), req, eventID, userID, 0, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Version != 2 {
+		t.Fatalf("expected version 2, got %d", resp.Version)
+	}
+}
 
-	err := svc.UpdateEvent(context.Background(), req, eventID, userID)
+func TestUpdateEvent_ReschedulesReminder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	reminderChan := make(chan reminder.Task, 1)
+	svc := service.NewEvent(mockRepo, reminderChan)
+
+	eventID := uuid.New()
+	ownerID := uuid.New()
+	editorID := uuid.New()
+	sentAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	// 10:00 at +03:00 is stored and scheduled as 07:00 UTC
+	remindAt := time.Date(2026, 10, 20, 10, 0, 0, 0, time.FixedZone("", 3*60*60))
+	want := time.Date(2026, 10, 20, 7, 0, 0, 0, time.UTC)
+
+	mockRepo.EXPECT().GetEvent(gomock.Any(), eventID, editorID).
+		Return(domain.Event{ID: eventID, UserID: ownerID, RemindAt: &sentAt, Sent: true, Version: 5}, nil)
+	mockRepo.EXPECT().
+		UpdateEvent(gomock.Any(), gomock.Cond(func(e domain.Event) bool {
+			return e.RemindAt != nil && e.RemindAt.Location() == time.UTC && e.RemindAt.Equal(want)
+		})).
+		Return(6, nil)
+
+	_, err := svc.UpdateEvent(context.Background(), dto.UpdateEventRequest{Date: remindAt, Description: "Moved", RemindAt: &remindAt}, eventID, editorID, 0, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+
+	select {
+	case task := <-reminderChan:
+		if task.UserID != ownerID || task.Version != 6 || !task.RemindAt.Equal(want) {
+			t.Fatalf("wrong reminder task sent: %+v", task)
+		}
+	default:
+		t.Fatalf("reminder task was not sent")
+	}
 }
 
 func TestUpdateEvent_NotFound(t *testing.T) {
@@ -134,17 +290,155 @@ func TestUpdateEvent_NotFound(t *testing.T) {
 	reminderChan := make(chan reminder.Task)
 	svc := service.NewEvent(mockRepo, reminderChan)
 
+	// deleted between the read and the update
+	mockRepo.EXPECT().GetEvent(gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.Event{Version: 1}, nil)
 	mockRepo.
 		EXPECT().
 		UpdateEvent(gomock.Any(), gomock.Any()).
-		Return(repo.ErrEventNotFound)
+		Return(0, repo.ErrEventNotFound)
 
-	err := svc.UpdateEvent(context.Background(), dto.UpdateEventRequest{}, uuid.New(), uuid.New())
+	_, err := svc.UpdateEvent(context.Background(), dto.UpdateEventRequest{}, uuid.New(), uuid.New(), 0, false)
 	if !errors.Is(err, domain.ErrEventNotFound) {
 		t.Fatalf("expected domain.ErrEventNotFound, got %v", err)
 	}
 }
 
+func TestUpdateEvent_VersionMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	reminderChan := make(chan reminder.Task)
+	svc := service.NewEvent(mockRepo, reminderChan)
+
+	mockRepo.EXPECT().GetEvent(gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.Event{Version: 4}, nil)
+	mockRepo.
+		EXPECT().
+		UpdateEvent(gomock.Any(), gomock.Cond(func(e domain.Event) bool { return e.Version == 3 })).
+		Return(0, repo.ErrVersionMismatch)
+
+	_, err := svc.UpdateEvent(context.Background(), dto.UpdateEventRequest{Date: time.Now()}, uuid.New(), uuid.New(), 3, false)
+	if !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Fatalf("expected domain.ErrVersionMismatch, got %v", err)
+	}
+}
+
+func TestPatchEvent_ClearReminder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	reminderChan := make(chan reminder.Task)
+	svc := service.NewEvent(mockRepo, reminderChan)
+
+	eventID := uuid.New()
+	userID := uuid.New()
+	remindAt := time.Now().Add(time.Hour)
+
+	current := domain.Event{
+		ID:          eventID,
+		UserID:      uuid.New(),
+		CalendarID:  uuid.New(),
+		Date:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Description: "Old",
+		RemindAt:    &remindAt,
+		Version:     4,
+	}
+
+	var patch dto.PatchEventRequest
+	if err := json.Unmarshal([]byte(`{"description":"New","remind_at":null}`), &patch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := domain.Event{
+		ID:          eventID,
+		UserID:      userID,
+		Date:        current.Date,
+		Description: "New",
+		Version:     4,
+	}
+
+	mockRepo.EXPECT().GetEvent(gomock.Any(), eventID, userID).Return(current, nil)
+	mockRepo.EXPECT().UpdateEvent(gomock.Any(), expected).Return(5, nil)
+
+	resp, err := svc.PatchEvent(context.Background(), patch, eventID, userID, 0, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Version != 5 {
+		t.Fatalf("expected version 5, got %d", resp.Version)
+	}
+}
+
+func TestPatchEvent_SchedulesReminder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	reminderChan := make(chan reminder.Task, 1)
+	svc := service.NewEvent(mockRepo, reminderChan)
+
+	eventID := uuid.New()
+	remindAt := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	mockRepo.EXPECT().GetEvent(gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.Event{ID: eventID, Version: 1}, nil)
+	mockRepo.EXPECT().UpdateEvent(gomock.Any(), gomock.Any()).Return(2, nil)
+
+	patch := dto.PatchEventRequest{RemindAt: dto.Nullable[time.Time]{Value: remindAt, Set: true}}
+	if _, err := svc.PatchEvent(context.Background(), patch, eventID, uuid.New(), 0, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case task := <-reminderChan:
+		if task.EventID != eventID || !task.RemindAt.Equal(remindAt) {
+			t.Fatalf("unexpected reminder task: %+v", task)
+		}
+	default:
+		t.Fatalf("expected reminder to be scheduled")
+	}
+}
+
+func TestPatchEvent_InvalidTimeRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	reminderChan := make(chan reminder.Task)
+	svc := service.NewEvent(mockRepo, reminderChan)
+
+	startsAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	endsAt := startsAt.Add(time.Hour)
+
+	mockRepo.EXPECT().
+		GetEvent(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.Event{StartsAt: &startsAt, EndsAt: &endsAt, Version: 1}, nil)
+
+	patch := dto.PatchEventRequest{StartsAt: dto.Nullable[time.Time]{Value: endsAt.Add(time.Hour), Set: true}}
+	_, err := svc.PatchEvent(context.Background(), patch, uuid.New(), uuid.New(), 0, false)
+	if !errors.Is(err, domain.ErrInvalidTimeRange) {
+		t.Fatalf("expected ErrInvalidTimeRange, got %v", err)
+	}
+}
+
+func TestPatchEvent_VersionMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	reminderChan := make(chan reminder.Task)
+	svc := service.NewEvent(mockRepo, reminderChan)
+
+	mockRepo.EXPECT().
+		GetEvent(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.Event{Version: 3}, nil)
+
+	_, err := svc.PatchEvent(context.Background(), dto.PatchEventRequest{}, uuid.New(), uuid.New(), 2, false)
+	if !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Fatalf("expected ErrVersionMismatch, got %v", err)
+	}
+}
+
 func TestDeleteEvent(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -158,10 +452,10 @@ func TestDeleteEvent(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		DeleteEvent(gomock.Any(), eventID, userID).
+		DeleteEvent(gomock.Any(), eventID, userID, 0).
 		Return(nil)
 
-	err := svc.DeleteEvent(context.Background(), eventID, userID)
+	err := svc.DeleteEvent(context.Background(), eventID, userID, 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -177,15 +471,53 @@ func TestDeleteEvent_NotFound(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		DeleteEvent(gomock.Any(), gomock.Any(), gomock.Any()).
+		DeleteEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(repo.ErrEventNotFound)
 
-	err := svc.DeleteEvent(context.Background(), uuid.New(), uuid.New())
+	err := svc.DeleteEvent(context.Background(), uuid.New(), uuid.New(), 0)
 	if !errors.Is(err, domain.ErrEventNotFound) {
 		t.Fatalf("expected ErrEventNotFound, got %v", err)
 	}
 }
 
+func TestDeleteEvent_ReadOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	reminderChan := make(chan reminder.Task)
+	svc := service.NewEvent(mockRepo, reminderChan)
+
+	mockRepo.
+		EXPECT().
+		DeleteEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(repo.ErrReadOnly)
+
+	err := svc.DeleteEvent(context.Background(), uuid.New(), uuid.New(), 0)
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestDeleteEvent_VersionMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepo(ctrl)
+	reminderChan := make(chan reminder.Task)
+	svc := service.NewEvent(mockRepo, reminderChan)
+
+	mockRepo.
+		EXPECT().
+		DeleteEvent(gomock.Any(), gomock.Any(), gomock.Any(), 4).
+		Return(repo.ErrVersionMismatch)
+
+	err := svc.DeleteEvent(context.Background(), uuid.New(), uuid.New(), 4)
+	if !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Fatalf("expected ErrVersionMismatch, got %v", err)
+	}
+}
+
 func TestGetEventsForDay(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -203,10 +535,16 @@ func TestGetEventsForDay(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		GetEventsForDay(gomock.Any(), userID, date).
+		GetEventsForDay(gomock.Any(), userID, date, nil).
 		Return(events, nil)
+	mockRepo.
+		EXPECT().
+		GetAttendees(gomock.Any(), []uuid.UUID{events[0].ID}).
+		Return([]domain.Attendee{
+			{ID: uuid.New(), EventID: events[0].ID, Email: "bob@example.com", Status: domain.AttendeeStatusAccepted},
+		}, nil)
 
-	resp, err := svc.GetEventsForDay(context.Background(), userID, date)
+	resp, err := svc.GetEventsForDay(context.Background(), userID, date, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -214,6 +552,10 @@ func TestGetEventsForDay(t *testing.T) {
 	if len(resp.Events) != 1 {
 		t.Fatalf("expected 1 event, got %d", len(resp.Events))
 	}
+
+	if len(resp.Events[0].Attendees) != 1 || resp.Events[0].Attendees[0].Status != domain.AttendeeStatusAccepted {
+		t.Fatalf("unexpected attendees: %+v", resp.Events[0].Attendees)
+	}
 }
 
 func TestGetEventsForWeek(t *testing.T) {
@@ -228,10 +570,10 @@ func TestGetEventsForWeek(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		GetEventsForWeek(gomock.Any(), userID, date).
+		GetEventsForWeek(gomock.Any(), userID, date, nil).
 		Return([]domain.Event{}, nil)
 
-	_, err := svc.GetEventsForWeek(context.Background(), userID, date)
+	_, err := svc.GetEventsForWeek(context.Background(), userID, date, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -249,10 +591,10 @@ func TestGetEventsForMonth(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		GetEventsForMonth(gomock.Any(), userID, date).
+		GetEventsForMonth(gomock.Any(), userID, date, nil).
 		Return([]domain.Event{}, nil)
 
-	_, err := svc.GetEventsForMonth(context.Background(), userID, date)
+	_, err := svc.GetEventsForMonth(context.Background(), userID, date, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/event/repo"
+	"github.com/ilam072/event-calendar/internal/event/worker/reminder"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"time"
+)
+
+//go:generate mockgen -source=trash.go -destination=../mocks/trash_service_mocks.go -package=mocks
+type TrashRepo interface {
+	GetTrash(ctx context.Context, userID uuid.UUID) ([]domain.Event, error)
+	RestoreEvent(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) (domain.Event, error)
+	EmptyTrash(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+type Trash struct {
+	repo      TrashRepo
+	reminders chan<- reminder.Task
+}
+
+func NewTrash(repo TrashRepo, reminderChan chan<- reminder.Task) *Trash {
+	return &Trash{
+		repo:      repo,
+		reminders: reminderChan,
+	}
+}
+
+func (t *Trash) GetTrash(ctx context.Context, userID uuid.UUID) (dto.GetEventsResponse, error) {
+	const op = "service.trash.Get"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	events, err := t.repo.GetTrash(ctx, userID)
+	if err != nil {
+		return dto.GetEventsResponse{}, errutils.Wrap(op, err)
+	}
+
+	return dto.GetEventsResponse{Events: domainToEvents(events)}, nil
+}
+
+// RestoreEvent takes the event out of the trash. Restoring bumps the version,
+// which makes the reminder worker drop the tasks scheduled before, so a
+// reminder that is still ahead and not sent is scheduled again.
+func (t *Trash) RestoreEvent(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) (dto.Event, error) {
+	const op = "service.trash.Restore"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	event, err := t.repo.RestoreEvent(ctx, eventID, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrEventNotFound) {
+			return dto.Event{}, errutils.Wrap(op, domain.ErrEventNotFound)
+		}
+		return dto.Event{}, errutils.Wrap(op, err)
+	}
+
+	if !event.Sent && event.RemindAt != nil && event.RemindAt.After(time.Now()) {
+		scheduleReminder(ctx, t.reminders, &reminder.Task{
+			EventID:  event.ID,
+			UserID:   event.UserID,
+			RemindAt: *event.RemindAt,
+			Version:  event.Version,
+		})
+	}
+
+	return domainToEvent(event), nil
+}
+
+func (t *Trash) EmptyTrash(ctx context.Context, userID uuid.UUID) (dto.EmptyTrashResponse, error) {
+	const op = "service.trash.Empty"
+
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	deleted, err := t.repo.EmptyTrash(ctx, userID)
+	if err != nil {
+		return dto.EmptyTrashResponse{}, errutils.Wrap(op, err)
+	}
+
+	return dto.EmptyTrashResponse{Deleted: deleted}, nil
+}
@@ -0,0 +1,169 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ilam072/event-calendar/internal/event/mocks"
+	"github.com/ilam072/event-calendar/internal/event/repo"
+	"github.com/ilam072/event-calendar/internal/event/service"
+	"github.com/ilam072/event-calendar/internal/event/worker/reminder"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+)
+
+func TestGetTrash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTrashRepo(ctrl)
+	svc := service.NewTrash(mockRepo, make(chan reminder.Task, 1))
+
+	userID := uuid.New()
+	deletedAt := time.Now()
+
+	mockRepo.EXPECT().
+		GetTrash(gomock.Any(), userID).
+		Return([]domain.Event{{ID: uuid.New(), Description: "Trashed", DeletedAt: &deletedAt}}, nil)
+
+	resp, err := svc.GetTrash(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Events) != 1 || resp.Events[0].DeletedAt == nil {
+		t.Fatalf("expected one trashed event, got %+v", resp.Events)
+	}
+}
+
+func TestGetTrash_Empty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTrashRepo(ctrl)
+	svc := service.NewTrash(mockRepo, make(chan reminder.Task, 1))
+
+	mockRepo.EXPECT().GetTrash(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	resp, err := svc.GetTrash(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Events == nil {
+		t.Fatalf("expected empty list, got nil")
+	}
+}
+
+func TestRestoreEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTrashRepo(ctrl)
+	svc := service.NewTrash(mockRepo, make(chan reminder.Task, 1))
+
+	eventID := uuid.New()
+	userID := uuid.New()
+
+	mockRepo.EXPECT().
+		RestoreEvent(gomock.Any(), eventID, userID).
+		Return(domain.Event{ID: eventID, Version: 3}, nil)
+
+	event, err := svc.RestoreEvent(context.Background(), eventID, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.ID != eventID || event.Version != 3 {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+}
+
+func TestRestoreEvent_ReschedulesReminder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTrashRepo(ctrl)
+	reminderChan := make(chan reminder.Task, 1)
+	svc := service.NewTrash(mockRepo, reminderChan)
+
+	eventID := uuid.New()
+	ownerID := uuid.New()
+	remindAt := time.Now().Add(time.Hour).UTC()
+
+	mockRepo.EXPECT().
+		RestoreEvent(gomock.Any(), eventID, gomock.Any()).
+		Return(domain.Event{ID: eventID, UserID: ownerID, RemindAt: &remindAt, Version: 4}, nil)
+
+	if _, err := svc.RestoreEvent(context.Background(), eventID, uuid.New()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case task := <-reminderChan:
+		if task.EventID != eventID || task.UserID != ownerID || task.Version != 4 || !task.RemindAt.Equal(remindAt) {
+			t.Fatalf("wrong reminder task sent: %+v", task)
+		}
+	default:
+		t.Fatalf("reminder task was not sent")
+	}
+}
+
+func TestRestoreEvent_PastReminder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTrashRepo(ctrl)
+	reminderChan := make(chan reminder.Task, 1)
+	svc := service.NewTrash(mockRepo, reminderChan)
+
+	remindAt := time.Now().Add(-time.Hour).UTC()
+
+	mockRepo.EXPECT().
+		RestoreEvent(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.Event{ID: uuid.New(), RemindAt: &remindAt, Version: 2}, nil)
+
+	if _, err := svc.RestoreEvent(context.Background(), uuid.New(), uuid.New()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(reminderChan) != 0 {
+		t.Fatalf("expected no reminder for a past remind_at")
+	}
+}
+
+func TestRestoreEvent_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTrashRepo(ctrl)
+	svc := service.NewTrash(mockRepo, make(chan reminder.Task, 1))
+
+	mockRepo.EXPECT().
+		RestoreEvent(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.Event{}, repo.ErrEventNotFound)
+
+	_, err := svc.RestoreEvent(context.Background(), uuid.New(), uuid.New())
+	if !errors.Is(err, domain.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+}
+
+func TestEmptyTrash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTrashRepo(ctrl)
+	svc := service.NewTrash(mockRepo, make(chan reminder.Task, 1))
+
+	mockRepo.EXPECT().EmptyTrash(gomock.Any(), gomock.Any()).Return(int64(4), nil)
+
+	resp, err := svc.EmptyTrash(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Deleted != 4 {
+		t.Fatalf("expected 4 deleted events, got %d", resp.Deleted)
+	}
+}
@@ -2,52 +2,168 @@ package janitor
 
 import (
 	"context"
+	"github.com/ilam072/event-calendar/internal/metrics"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"github.com/ilam072/event-calendar/pkg/tracing"
 	"github.com/robfig/cron/v3"
-	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"sync/atomic"
 	"time"
 )
 
+var tracer = otel.Tracer("github.com/ilam072/event-calendar/internal/event/worker/janitor")
+
+// loginThrottleRetention is how long login throttles are kept after their
+// last failure. Sign-in forgets failures after 15 minutes anyway.
+const loginThrottleRetention = time.Hour
+
 type EventRepo interface {
-	ArchiveOldEvents(ctx context.Context) error
+	ArchiveOldEvents(ctx context.Context) (int64, error)
+	PurgeTrash(ctx context.Context, retention time.Duration) error
+}
+
+type IdempotencyRepo interface {
+	DeleteExpired(ctx context.Context) error
+}
+
+type LoginThrottleRepo interface {
+	DeleteStale(ctx context.Context, olderThan time.Duration) error
+}
+
+type MFATokenRepo interface {
+	DeleteExpiredMFATokens(ctx context.Context) error
 }
 
 type Worker struct {
-	cron      *cron.Cron
-	eventRepo EventRepo
+	cron              *cron.Cron
+	eventRepo         EventRepo
+	idempotencyRepo   IdempotencyRepo
+	loginThrottleRepo LoginThrottleRepo
+	mfaTokenRepo      MFATokenRepo
+	trashRetention    time.Duration
+	logger            logger.Logger
+	running           atomic.Bool
 }
 
-func NewWorker(eventRepo EventRepo) *Worker {
+func NewWorker(eventRepo EventRepo, idempotencyRepo IdempotencyRepo, loginThrottleRepo LoginThrottleRepo, mfaTokenRepo MFATokenRepo, trashRetention time.Duration, logger logger.Logger) *Worker {
 	c := cron.New(cron.WithSeconds())
-	return &Worker{cron: c, eventRepo: eventRepo}
+	return &Worker{
+		cron:              c,
+		eventRepo:         eventRepo,
+		idempotencyRepo:   idempotencyRepo,
+		loginThrottleRepo: loginThrottleRepo,
+		mfaTokenRepo:      mfaTokenRepo,
+		trashRetention:    trashRetention,
+		logger:            logger,
+	}
 }
 
 func (w *Worker) RegisterJobs() {
 	schedule := "0 */5 * * * *"
 
 	if _, err := w.cron.AddFunc(schedule, func() {
-		log.Logger.Print("[JOB] Archiving old events...\n")
+		w.logger.Info().Msg("[JOB] Archiving old events...")
+
+		err := w.runJob("archive_old_events", func(ctx context.Context) error {
+			archived, err := w.eventRepo.ArchiveOldEvents(ctx)
+			metrics.JanitorArchivedEvents.Add(float64(archived))
+			return err
+		})
+		if err != nil {
+			w.logger.Error().Err(err).Msg("[JOB] ArchiveOldEvents failed")
+		}
+	}); err != nil {
+		w.logger.Error().Err(err).Msg("[CRON] Failed to register ArchiveOldEvents job")
+	} else {
+		w.logger.Info().Msg("[CRON] ArchiveOldEvents job registered successfully")
+	}
+
+	if _, err := w.cron.AddFunc("0 30 * * * *", func() {
+		w.logger.Info().Msg("[JOB] Purging trash...")
+
+		err := w.runJob("purge_trash", func(ctx context.Context) error {
+			return w.eventRepo.PurgeTrash(ctx, w.trashRetention)
+		})
+		if err != nil {
+			w.logger.Error().Err(err).Msg("[JOB] PurgeTrash failed")
+		}
+	}); err != nil {
+		w.logger.Error().Err(err).Msg("[CRON] Failed to register PurgeTrash job")
+	} else {
+		w.logger.Info().Msg("[CRON] PurgeTrash job registered successfully")
+	}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-		defer cancel()
+	if _, err := w.cron.AddFunc("0 0 * * * *", func() {
+		w.logger.Info().Msg("[JOB] Deleting expired idempotency keys...")
 
-		if err := w.eventRepo.ArchiveOldEvents(ctx); err != nil {
-			log.Logger.Error().Err(err).Msg("[JOB] ArchiveOldEvents failed")
+		if err := w.runJob("delete_expired_idempotency_keys", w.idempotencyRepo.DeleteExpired); err != nil {
+			w.logger.Error().Err(err).Msg("[JOB] DeleteExpired idempotency keys failed")
 		}
 	}); err != nil {
-		log.Logger.Error().Err(err).Msg("[CRON] Failed to register ArchiveOldEvents job")
+		w.logger.Error().Err(err).Msg("[CRON] Failed to register DeleteExpired idempotency keys job")
 	} else {
-		log.Logger.Print("[CRON] ArchiveOldEvents job registered successfully")
+		w.logger.Info().Msg("[CRON] DeleteExpired idempotency keys job registered successfully")
+	}
+
+	if _, err := w.cron.AddFunc("0 10 * * * *", func() {
+		w.logger.Info().Msg("[JOB] Deleting stale login throttles...")
+
+		err := w.runJob("delete_stale_login_throttles", func(ctx context.Context) error {
+			return w.loginThrottleRepo.DeleteStale(ctx, loginThrottleRetention)
+		})
+		if err != nil {
+			w.logger.Error().Err(err).Msg("[JOB] DeleteStale login throttles failed")
+		}
+	}); err != nil {
+		w.logger.Error().Err(err).Msg("[CRON] Failed to register DeleteStale login throttles job")
+	} else {
+		w.logger.Info().Msg("[CRON] DeleteStale login throttles job registered successfully")
+	}
+
+	if _, err := w.cron.AddFunc("0 20 * * * *", func() {
+		w.logger.Info().Msg("[JOB] Deleting expired mfa tokens...")
+
+		if err := w.runJob("delete_expired_mfa_tokens", w.mfaTokenRepo.DeleteExpiredMFATokens); err != nil {
+			w.logger.Error().Err(err).Msg("[JOB] DeleteExpiredMFATokens failed")
+		}
+	}); err != nil {
+		w.logger.Error().Err(err).Msg("[CRON] Failed to register DeleteExpiredMFATokens job")
+	} else {
+		w.logger.Info().Msg("[CRON] DeleteExpiredMFATokens job registered successfully")
 	}
 }
 
+// runJob runs a job in its own trace with a timeout and records how long it
+// took.
+func (w *Worker) runJob(job string, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "janitor."+job)
+
+	start := time.Now()
+	err := fn(ctx)
+	metrics.JanitorDuration.WithLabelValues(job, metrics.Result(err)).Observe(time.Since(start).Seconds())
+	tracing.End(span, err)
+
+	return err
+}
+
 func (w *Worker) Start() {
 	w.RegisterJobs()
 	w.cron.Start()
-	log.Logger.Println("[CRON] Worker started")
+	w.running.Store(true)
+	w.logger.Info().Msg("[CRON] Worker started")
 }
 
 func (w *Worker) Stop() {
-	log.Logger.Println("[CRON] Stopping scheduler...")
+	w.logger.Info().Msg("[CRON] Stopping scheduler...")
+	w.running.Store(false)
 	ctx := w.cron.Stop()
 	<-ctx.Done()
 }
+
+// Running reports whether the scheduler has been started and not stopped.
+func (w *Worker) Running() bool {
+	return w.running.Load()
+}
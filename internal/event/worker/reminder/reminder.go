@@ -2,17 +2,27 @@ package reminder
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
-	"github.com/rs/zerolog/log"
+	"github.com/ilam072/event-calendar/internal/metrics"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"github.com/ilam072/event-calendar/pkg/requestid"
+	"github.com/ilam072/event-calendar/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"sync/atomic"
 	"time"
 
 	"github.com/ilam072/event-calendar/internal/types/domain"
 )
 
 type EventRepo interface {
+	GetPendingReminders(ctx context.Context, now time.Time) ([]domain.Event, error)
 	GetEventByID(ctx context.Context, eventID uuid.UUID) (domain.Event, error)
-	MarkReminderSent(ctx context.Context, eventID uuid.UUID) error
+	ClaimReminder(ctx context.Context, eventID uuid.UUID, version int) (bool, error)
+	ReleaseReminder(ctx context.Context, eventID uuid.UUID, version int) error
 }
 
 type UserRepo interface {
@@ -23,10 +33,21 @@ type Sender interface {
 	Send(subject string, message string, to string) error
 }
 
+var tracer = otel.Tracer("github.com/ilam072/event-calendar/internal/event/worker/reminder")
+
 type Task struct {
 	EventID  uuid.UUID
 	UserID   uuid.UUID
 	RemindAt time.Time
+	// Version is the version of the event the reminder was scheduled for.
+	// The task is dropped once the event has changed since, as the change
+	// schedules the reminder again if it is still due.
+	Version int
+	// RequestID is the request that scheduled the reminder, if any.
+	RequestID string
+	// Link is the span that scheduled the reminder. The delivery is traced
+	// separately and linked to it, since it happens long after the request.
+	Link trace.SpanContext
 }
 
 type Worker struct {
@@ -34,15 +55,18 @@ type Worker struct {
 	eventRepo EventRepo
 	userRepo  UserRepo
 	sender    Sender
+	logger    logger.Logger
 	done      chan struct{}
+	running   atomic.Bool
 }
 
-func NewWorker(eventRepo EventRepo, userRepo UserRepo, sender Sender, buffer int) *Worker {
+func NewWorker(eventRepo EventRepo, userRepo UserRepo, sender Sender, buffer int, logger logger.Logger) *Worker {
 	return &Worker{
 		tasks:     make(chan Task, buffer),
 		eventRepo: eventRepo,
 		userRepo:  userRepo,
 		sender:    sender,
+		logger:    logger,
 		done:      make(chan struct{}),
 	}
 }
@@ -51,33 +75,76 @@ func (w *Worker) TasksChan() chan<- Task {
 	return w.tasks
 }
 
+// Running reports whether Run is processing tasks.
+func (w *Worker) Running() bool {
+	return w.running.Load()
+}
+
 func (w *Worker) Run(ctx context.Context) {
+	w.running.Store(true)
+	defer w.running.Store(false)
+
+	w.schedulePending(ctx)
+
 	for {
 		select {
 		case task, ok := <-w.tasks:
 			if !ok {
-				log.Info().Msg("Reminder worker stopped, channel closed")
+				w.logger.Info().Msg("Reminder worker stopped, channel closed")
 				close(w.done)
 				return
 			}
+			metrics.RemindersScheduled.Inc()
+			metrics.RemindersQueued.Inc()
 			go w.handleTask(ctx, task)
 
 		case <-ctx.Done():
-			log.Info().Msg("Reminder worker stopped by context")
+			w.logger.Info().Msg("Reminder worker stopped by context")
 			close(w.done)
 			return
 		}
 	}
 }
 
+// schedulePending schedules the reminders that are still due, which were
+// scheduled by an earlier run or imported while no server was running.
+func (w *Worker) schedulePending(ctx context.Context) {
+	events, err := w.eventRepo.GetPendingReminders(ctx, time.Now())
+	if err != nil {
+		w.logger.Error().Ctx(ctx).Err(err).Str("op", "schedulePending").Msg("failed to get pending reminders")
+		return
+	}
+
+	for _, event := range events {
+		metrics.RemindersScheduled.Inc()
+		metrics.RemindersQueued.Inc()
+		go w.handleTask(ctx, Task{
+			EventID:  event.ID,
+			UserID:   event.UserID,
+			RemindAt: *event.RemindAt,
+			Version:  event.Version,
+		})
+	}
+
+	w.logger.Info().Ctx(ctx).Any("reminders", len(events)).Msg("Pending reminders scheduled")
+}
+
 func (w *Worker) handleTask(ctx context.Context, task Task) {
+	defer metrics.RemindersQueued.Dec()
+
+	// Deliveries are logged with the request that scheduled them.
+	ctx = logger.NewContext(ctx, "event_id", task.EventID.String())
+	ctx = logger.NewContext(ctx, "user_id", task.UserID.String())
+	if task.RequestID != "" {
+		ctx = requestid.NewContext(ctx, task.RequestID)
+		ctx = logger.NewContext(ctx, "request_id", task.RequestID)
+	}
+
 	delay := time.Until(task.RemindAt)
 
-	log.Logger.Info().
-		Str("event_id", task.EventID.String()).
-		Str("user_id", task.UserID.String()).
+	w.logger.Info().Ctx(ctx).
 		Str("remind_at", task.RemindAt.String()).
-		Str("delay", time.Until(task.RemindAt).String()).
+		Str("delay", delay.String()).
 		Msg("Sending reminder...")
 
 	if delay > 0 {
@@ -88,36 +155,114 @@ func (w *Worker) handleTask(ctx context.Context, task Task) {
 		}
 	}
 
-	log.Logger.Info().
-		Str("event_id", task.EventID.String()).
-		Str("user_id", task.UserID.String()).
-		Msg("Sending reminder...")
+	ctx, span := tracer.Start(ctx, "reminder.Deliver",
+		trace.WithNewRoot(),
+		trace.WithLinks(trace.Link{SpanContext: task.Link}),
+		trace.WithAttributes(
+			attribute.String("event.id", task.EventID.String()),
+			attribute.String("user.id", task.UserID.String()),
+		),
+	)
+	var err error
+	defer func() { tracing.End(span, err) }()
+
+	w.logger.Info().Ctx(ctx).Msg("Sending reminder...")
 
 	user, err := w.userRepo.GetUserByID(ctx, task.UserID)
 	if err != nil {
-		log.Error().Err(err).Str("op", "handleTask").Msg("failed to get user by id")
+		metrics.RemindersFailed.Inc()
+		w.logger.Error().Ctx(ctx).Err(err).Str("op", "handleTask").Msg("failed to get user by id")
 		return
 	}
 
 	event, err := w.eventRepo.GetEventByID(ctx, task.EventID)
 	if err != nil {
-		log.Error().Err(err).Str("op", "handleTask").Msg("failed to get event by id")
+		metrics.RemindersFailed.Inc()
+		w.logger.Error().Ctx(ctx).Err(err).Str("op", "handleTask").Msg("failed to get event by id")
 		return
 	}
 
-	message := fmt.Sprintf(`Event "%s" is coming up soon. 🔔`, event.Description)
-	if err := w.sender.Send("Event reminder", message, user.Email); err != nil {
-		log.Error().Err(err).Str("op", "handleTask").Msg("failed to send reminder")
+	// The event may have been changed or trashed since the reminder was
+	// scheduled.
+	if event.Sent || event.DeletedAt != nil || event.RemindAt == nil || event.Version != task.Version {
+		w.logger.Info().Ctx(ctx).Msg("Reminder is no longer due, skipping")
+		return
+	}
+
+	// Every replica schedules the pending reminders, so only the one that
+	// claims the reminder sends it.
+	claimed, err := w.eventRepo.ClaimReminder(ctx, event.ID, task.Version)
+	if err != nil {
+		metrics.RemindersFailed.Inc()
+		w.logger.Error().Ctx(ctx).Err(err).Str("op", "handleTask").Msg("failed to claim reminder")
+		return
+	}
+	if !claimed {
+		w.logger.Info().Ctx(ctx).Msg("Reminder has been claimed elsewhere, skipping")
 		return
 	}
 
-	if err := w.eventRepo.MarkReminderSent(ctx, task.EventID); err != nil {
-		log.Error().Err(err).Msg("failed to mark reminder sent")
+	if err = w.send(ctx, user, event, true); err != nil {
+		w.logger.Error().Ctx(ctx).Err(err).Str("op", "handleTask").Msg("failed to send reminder")
 	}
 }
 
+// Resend sends the reminder of the event to its owner right away. A reminder
+// that has been sent, or is being sent by a server, is only sent again when
+// force is set.
+func (w *Worker) Resend(ctx context.Context, eventID uuid.UUID, force bool) (err error) {
+	ctx, span := tracer.Start(ctx, "reminder.Resend", trace.WithAttributes(attribute.String("event.id", eventID.String())))
+	defer func() { tracing.End(span, err) }()
+
+	event, err := w.eventRepo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	if event.DeletedAt != nil {
+		return fmt.Errorf("event %s is in the trash", eventID)
+	}
+	if event.RemindAt == nil {
+		return fmt.Errorf("event %s has no reminder", eventID)
+	}
+
+	user, err := w.userRepo.GetUserByID(ctx, event.UserID)
+	if err != nil {
+		return err
+	}
+
+	claimed, err := w.eventRepo.ClaimReminder(ctx, event.ID, event.Version)
+	if err != nil {
+		return err
+	}
+	if !claimed && !force {
+		return fmt.Errorf("reminder of event %s has already been sent or is being sent", eventID)
+	}
+
+	return w.send(ctx, user, event, claimed)
+}
+
+// send emails the reminder. A claimed reminder is released when that fails,
+// so that it can be sent again.
+func (w *Worker) send(ctx context.Context, user domain.User, event domain.Event, claimed bool) error {
+	message := fmt.Sprintf(`Event "%s" is coming up soon. 🔔`, event.Description)
+	if err := w.sender.Send("Event reminder", message, user.Email); err != nil {
+		metrics.RemindersFailed.Inc()
+		err = fmt.Errorf("failed to send reminder: %w", err)
+		if claimed {
+			if releaseErr := w.eventRepo.ReleaseReminder(ctx, event.ID, event.Version); releaseErr != nil {
+				err = errors.Join(err, releaseErr)
+			}
+		}
+		return err
+	}
+	metrics.RemindersSent.Inc()
+
+	return nil
+}
+
 func (w *Worker) Stop() {
 	close(w.tasks)
 	<-w.done
-	log.Info().Msg("Reminder worker fully stopped")
+	w.logger.Info().Msg("Reminder worker fully stopped")
 }
@@ -0,0 +1,289 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: export.go
+//
+// Generated by this command:
+//
+//	mockgen -source=export.go -destination=../mocks/service_mocks.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	domain "github.com/ilam072/event-calendar/internal/types/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserRepo is a mock of UserRepo interface.
+type MockUserRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockUserRepoMockRecorder is the mock recorder for MockUserRepo.
+type MockUserRepoMockRecorder struct {
+	mock *MockUserRepo
+}
+
+// NewMockUserRepo creates a new mock instance.
+func NewMockUserRepo(ctrl *gomock.Controller) *MockUserRepo {
+	mock := &MockUserRepo{ctrl: ctrl}
+	mock.recorder = &MockUserRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepo) EXPECT() *MockUserRepoMockRecorder {
+	return m.recorder
+}
+
+// GetIdentities mocks base method.
+func (m *MockUserRepo) GetIdentities(ctx context.Context, userID uuid.UUID) ([]domain.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdentities", ctx, userID)
+	ret0, _ := ret[0].([]domain.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdentities indicates an expected call of GetIdentities.
+func (mr *MockUserRepoMockRecorder) GetIdentities(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdentities", reflect.TypeOf((*MockUserRepo)(nil).GetIdentities), ctx, userID)
+}
+
+// GetUserByID mocks base method.
+func (m *MockUserRepo) GetUserByID(ctx context.Context, userID uuid.UUID) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, userID)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockUserRepoMockRecorder) GetUserByID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserRepo)(nil).GetUserByID), ctx, userID)
+}
+
+// MockEventRepo is a mock of EventRepo interface.
+type MockEventRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockEventRepoMockRecorder is the mock recorder for MockEventRepo.
+type MockEventRepoMockRecorder struct {
+	mock *MockEventRepo
+}
+
+// NewMockEventRepo creates a new mock instance.
+func NewMockEventRepo(ctrl *gomock.Controller) *MockEventRepo {
+	mock := &MockEventRepo{ctrl: ctrl}
+	mock.recorder = &MockEventRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventRepo) EXPECT() *MockEventRepoMockRecorder {
+	return m.recorder
+}
+
+// GetArchivedUserEvents mocks base method.
+func (m *MockEventRepo) GetArchivedUserEvents(ctx context.Context, userID uuid.UUID) ([]domain.ArchivedEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArchivedUserEvents", ctx, userID)
+	ret0, _ := ret[0].([]domain.ArchivedEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArchivedUserEvents indicates an expected call of GetArchivedUserEvents.
+func (mr *MockEventRepoMockRecorder) GetArchivedUserEvents(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArchivedUserEvents", reflect.TypeOf((*MockEventRepo)(nil).GetArchivedUserEvents), ctx, userID)
+}
+
+// GetUserEvents mocks base method.
+func (m *MockEventRepo) GetUserEvents(ctx context.Context, userID uuid.UUID) ([]domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserEvents", ctx, userID)
+	ret0, _ := ret[0].([]domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserEvents indicates an expected call of GetUserEvents.
+func (mr *MockEventRepoMockRecorder) GetUserEvents(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserEvents", reflect.TypeOf((*MockEventRepo)(nil).GetUserEvents), ctx, userID)
+}
+
+// GetUserHistory mocks base method.
+func (m *MockEventRepo) GetUserHistory(ctx context.Context, userID uuid.UUID) ([]domain.EventRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserHistory", ctx, userID)
+	ret0, _ := ret[0].([]domain.EventRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserHistory indicates an expected call of GetUserHistory.
+func (mr *MockEventRepoMockRecorder) GetUserHistory(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserHistory", reflect.TypeOf((*MockEventRepo)(nil).GetUserHistory), ctx, userID)
+}
+
+// GetUserInvitations mocks base method.
+func (m *MockEventRepo) GetUserInvitations(ctx context.Context, userID uuid.UUID) ([]domain.Attendee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserInvitations", ctx, userID)
+	ret0, _ := ret[0].([]domain.Attendee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserInvitations indicates an expected call of GetUserInvitations.
+func (mr *MockEventRepoMockRecorder) GetUserInvitations(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserInvitations", reflect.TypeOf((*MockEventRepo)(nil).GetUserInvitations), ctx, userID)
+}
+
+// MockCalendarRepo is a mock of CalendarRepo interface.
+type MockCalendarRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockCalendarRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockCalendarRepoMockRecorder is the mock recorder for MockCalendarRepo.
+type MockCalendarRepoMockRecorder struct {
+	mock *MockCalendarRepo
+}
+
+// NewMockCalendarRepo creates a new mock instance.
+func NewMockCalendarRepo(ctrl *gomock.Controller) *MockCalendarRepo {
+	mock := &MockCalendarRepo{ctrl: ctrl}
+	mock.recorder = &MockCalendarRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCalendarRepo) EXPECT() *MockCalendarRepoMockRecorder {
+	return m.recorder
+}
+
+// GetCalendars mocks base method.
+func (m *MockCalendarRepo) GetCalendars(ctx context.Context, userID uuid.UUID) ([]domain.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendars", ctx, userID)
+	ret0, _ := ret[0].([]domain.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendars indicates an expected call of GetCalendars.
+func (mr *MockCalendarRepoMockRecorder) GetCalendars(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendars", reflect.TypeOf((*MockCalendarRepo)(nil).GetCalendars), ctx, userID)
+}
+
+// GetUserShares mocks base method.
+func (m *MockCalendarRepo) GetUserShares(ctx context.Context, userID uuid.UUID) ([]domain.CalendarShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserShares", ctx, userID)
+	ret0, _ := ret[0].([]domain.CalendarShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserShares indicates an expected call of GetUserShares.
+func (mr *MockCalendarRepoMockRecorder) GetUserShares(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserShares", reflect.TypeOf((*MockCalendarRepo)(nil).GetUserShares), ctx, userID)
+}
+
+// MockAPIKeyRepo is a mock of APIKeyRepo interface.
+type MockAPIKeyRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockAPIKeyRepoMockRecorder is the mock recorder for MockAPIKeyRepo.
+type MockAPIKeyRepoMockRecorder struct {
+	mock *MockAPIKeyRepo
+}
+
+// NewMockAPIKeyRepo creates a new mock instance.
+func NewMockAPIKeyRepo(ctrl *gomock.Controller) *MockAPIKeyRepo {
+	mock := &MockAPIKeyRepo{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyRepo) EXPECT() *MockAPIKeyRepoMockRecorder {
+	return m.recorder
+}
+
+// GetAPIKeysByUser mocks base method.
+func (m *MockAPIKeyRepo) GetAPIKeysByUser(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeysByUser", ctx, userID)
+	ret0, _ := ret[0].([]domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeysByUser indicates an expected call of GetAPIKeysByUser.
+func (mr *MockAPIKeyRepoMockRecorder) GetAPIKeysByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeysByUser", reflect.TypeOf((*MockAPIKeyRepo)(nil).GetAPIKeysByUser), ctx, userID)
+}
+
+// MockLoginThrottleRepo is a mock of LoginThrottleRepo interface.
+type MockLoginThrottleRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginThrottleRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockLoginThrottleRepoMockRecorder is the mock recorder for MockLoginThrottleRepo.
+type MockLoginThrottleRepoMockRecorder struct {
+	mock *MockLoginThrottleRepo
+}
+
+// NewMockLoginThrottleRepo creates a new mock instance.
+func NewMockLoginThrottleRepo(ctrl *gomock.Controller) *MockLoginThrottleRepo {
+	mock := &MockLoginThrottleRepo{ctrl: ctrl}
+	mock.recorder = &MockLoginThrottleRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginThrottleRepo) EXPECT() *MockLoginThrottleRepoMockRecorder {
+	return m.recorder
+}
+
+// GetLoginThrottles mocks base method.
+func (m *MockLoginThrottleRepo) GetLoginThrottles(ctx context.Context, keys []string) ([]domain.LoginThrottle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginThrottles", ctx, keys)
+	ret0, _ := ret[0].([]domain.LoginThrottle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginThrottles indicates an expected call of GetLoginThrottles.
+func (mr *MockLoginThrottleRepoMockRecorder) GetLoginThrottles(ctx, keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginThrottles", reflect.TypeOf((*MockLoginThrottleRepo)(nil).GetLoginThrottles), ctx, keys)
+}
@@ -0,0 +1,69 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/response"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"net/http"
+	"time"
+)
+
+//go:generate mockgen -source=rest.go -destination=../mocks/rest_mocks.go -package=mocks
+type Export interface {
+	ExportUserData(ctx context.Context, userID uuid.UUID) ([]byte, error)
+}
+
+type ExportHandler struct {
+	export Export
+	logger logger.Logger
+}
+
+func NewExportHandler(export Export, logger logger.Logger) *ExportHandler {
+	return &ExportHandler{export: export, logger: logger}
+}
+
+func (h *ExportHandler) ExportUserData(c *gin.Context) {
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	archive, err := h.export.ExportUserData(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			response.NotFound(c)
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("user_id", userID.String()).Msg("failed to export user data")
+		response.InternalServerError(c)
+		return
+	}
+
+	fileName := fmt.Sprintf("event-calendar-export-%s.zip", time.Now().Format(time.DateOnly))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+func (h *ExportHandler) getUserData(c *gin.Context) (uuid.UUID, bool) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		h.logger.Error().Ctx(c.Request.Context()).Msg("failed to get user_id from ctx")
+		response.Unauthorized(c, "missing user_id in token")
+		return uuid.Nil, false
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Any("user_id", userID).Msg("failed to parse user id into uuid")
+		response.Unauthorized(c, "user_id must be uuid format")
+		return uuid.Nil, false
+	}
+
+	return userUUID, true
+}
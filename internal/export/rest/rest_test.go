@@ -0,0 +1,109 @@
+package rest_test
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/export/mocks"
+	"github.com/ilam072/event-calendar/internal/export/rest"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var log = &logger.DummyLogger{}
+
+func TestExportUserData_NoUserID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := rest.NewExportHandler(mocks.NewMockExport(ctrl), log)
+	r := routerWithHandler(h, "")
+
+	req := httptest.NewRequest("GET", "/me/export", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestExportUserData_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+
+	mockExport := mocks.NewMockExport(ctrl)
+	mockExport.EXPECT().
+		ExportUserData(gomock.Any(), userID).
+		Return([]byte("PK"), nil)
+
+	h := rest.NewExportHandler(mockExport, log)
+	r := routerWithHandler(h, userID.String())
+
+	req := httptest.NewRequest("GET", "/me/export", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/zip", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), "attachment")
+	assert.Equal(t, "PK", rec.Body.String())
+}
+
+func TestExportUserData_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExport := mocks.NewMockExport(ctrl)
+	mockExport.EXPECT().
+		ExportUserData(gomock.Any(), gomock.Any()).
+		Return(nil, domain.ErrUserNotFound)
+
+	h := rest.NewExportHandler(mockExport, log)
+	r := routerWithHandler(h, uuid.New().String())
+
+	req := httptest.NewRequest("GET", "/me/export", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestExportUserData_ServiceError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExport := mocks.NewMockExport(ctrl)
+	mockExport.EXPECT().
+		ExportUserData(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("boom"))
+
+	h := rest.NewExportHandler(mockExport, log)
+	r := routerWithHandler(h, uuid.New().String())
+
+	req := httptest.NewRequest("GET", "/me/export", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func routerWithHandler(h *rest.ExportHandler, userID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.GET("/me/export", func(c *gin.Context) {
+		if userID != "" {
+			c.Set("user_id", userID)
+		}
+		h.ExportUserData(c)
+	})
+
+	return r
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/ical"
+	"strings"
+	"time"
+)
+
+func buildUserExport(d userData, now time.Time) dto.UserExport {
+	export := dto.UserExport{
+		ExportedAt: now,
+		User: dto.ExportUser{
+			ID:        d.user.ID,
+			Email:     d.user.Email,
+			CreatedAt: d.user.CreatedAt,
+			UpdatedAt: d.user.UpdatedAt,
+		},
+		Sessions:       make([]dto.ExportSession, 0, len(d.throttles)),
+		APIKeys:        make([]dto.ExportAPIKey, 0, len(d.apiKeys)),
+		Identities:     make([]dto.ExportIdentity, 0, len(d.identities)),
+		Calendars:      make([]dto.ExportCalendar, 0, len(d.calendars)),
+		Shares:         make([]dto.ExportShare, 0, len(d.shares)),
+		Events:         make([]dto.ExportEvent, 0, len(d.events)),
+		ArchivedEvents: make([]dto.ExportArchivedEvent, 0, len(d.archived)),
+		Reminders:      make([]dto.ExportReminder, 0),
+		Invitations:    make([]dto.ExportInvitation, 0, len(d.invitations)),
+		History:        make([]dto.ExportRevision, 0, len(d.history)),
+	}
+
+	for _, t := range d.throttles {
+		kind := "password"
+		if strings.HasPrefix(t.Key, mfaThrottlePrefix) {
+			kind = "mfa"
+		}
+		export.Sessions = append(export.Sessions, dto.ExportSession{
+			Kind:          kind,
+			Failures:      t.Failures,
+			LastFailureAt: t.LastFailureAt,
+			LockedUntil:   t.LockedUntil,
+		})
+	}
+
+	for _, k := range d.apiKeys {
+		export.APIKeys = append(export.APIKeys, dto.ExportAPIKey{
+			ID:         k.ID,
+			Name:       k.Name,
+			Prefix:     k.Prefix,
+			Scope:      k.Scope,
+			ExpiresAt:  k.ExpiresAt,
+			LastUsedAt: k.LastUsedAt,
+			CreatedAt:  k.CreatedAt,
+		})
+	}
+
+	for _, i := range d.identities {
+		export.Identities = append(export.Identities, dto.ExportIdentity{
+			Issuer:    i.Issuer,
+			Subject:   i.Subject,
+			CreatedAt: i.CreatedAt,
+		})
+	}
+
+	for _, c := range d.calendars {
+		export.Calendars = append(export.Calendars, dto.ExportCalendar{
+			ID:          c.ID,
+			OwnerID:     c.UserID,
+			Name:        c.Name,
+			Color:       c.Color,
+			Description: c.Description,
+			IsDefault:   c.IsDefault,
+			Visibility:  c.Visibility,
+			Role:        c.Role,
+			CreatedAt:   c.CreatedAt,
+			UpdatedAt:   c.UpdatedAt,
+		})
+	}
+
+	for _, s := range d.shares {
+		export.Shares = append(export.Shares, dto.ExportShare{
+			ID:         s.ID,
+			CalendarID: s.CalendarID,
+			UserID:     s.UserID,
+			Email:      s.Email,
+			Role:       s.Role,
+			CreatedAt:  s.CreatedAt,
+		})
+	}
+
+	for _, e := range d.events {
+		export.Events = append(export.Events, dto.ExportEvent{
+			ID:          e.ID,
+			CalendarID:  e.CalendarID,
+			Date:        e.Date,
+			StartsAt:    e.StartsAt,
+			EndsAt:      e.EndsAt,
+			Description: e.Description,
+			RemindAt:    e.RemindAt,
+			CreatedAt:   e.CreatedAt,
+			UpdatedAt:   e.UpdatedAt,
+			DeletedAt:   e.DeletedAt,
+		})
+
+		if e.RemindAt != nil {
+			export.Reminders = append(export.Reminders, dto.ExportReminder{
+				EventID:  e.ID,
+				RemindAt: *e.RemindAt,
+				Sent:     e.Sent,
+			})
+		}
+	}
+
+	for _, e := range d.archived {
+		export.ArchivedEvents = append(export.ArchivedEvents, dto.ExportArchivedEvent{
+			ID:                e.ID,
+			CalendarID:        e.CalendarID,
+			Date:              e.Date,
+			StartsAt:          e.StartsAt,
+			EndsAt:            e.EndsAt,
+			Description:       e.Description,
+			ArchivedAt:        e.ArchivedAt,
+			OriginalCreatedAt: e.OriginalCreatedAt,
+			OriginalUpdatedAt: e.OriginalUpdatedAt,
+		})
+	}
+
+	for _, a := range d.invitations {
+		export.Invitations = append(export.Invitations, dto.ExportInvitation{
+			ID:          a.ID,
+			EventID:     a.EventID,
+			Email:       a.Email,
+			Status:      a.Status,
+			RespondedAt: a.RespondedAt,
+			CreatedAt:   a.CreatedAt,
+		})
+	}
+
+	for _, r := range d.history {
+		export.History = append(export.History, dto.ExportRevision{
+			EventID:       r.EventID,
+			EventRevision: domainToRevision(r),
+		})
+	}
+
+	return export
+}
+
+func domainToRevision(r domain.EventRevision) dto.EventRevision {
+	changes := make(map[string]dto.FieldChange, len(r.Changes))
+	for name, c := range r.Changes {
+		changes[name] = dto.FieldChange{From: c.From, To: c.To}
+	}
+
+	return dto.EventRevision{
+		ID:        r.ID,
+		Action:    r.Action,
+		ActorID:   r.ActorID,
+		Version:   r.Version,
+		Before:    domainToSnapshot(r.Before),
+		After:     domainToSnapshot(r.After),
+		Changes:   changes,
+		RequestID: r.RequestID,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func domainToSnapshot(s *domain.EventSnapshot) *dto.EventSnapshot {
+	if s == nil {
+		return nil
+	}
+
+	return &dto.EventSnapshot{
+		CalendarID:  s.CalendarID,
+		Date:        s.Date,
+		StartsAt:    s.StartsAt,
+		EndsAt:      s.EndsAt,
+		Description: s.Description,
+		RemindAt:    s.RemindAt,
+		DeletedAt:   s.DeletedAt,
+	}
+}
+
+func buildCalendar(events []domain.Event, archived []domain.ArchivedEvent) []ical.Event {
+	calendar := make([]ical.Event, 0, len(events)+len(archived))
+	for _, e := range events {
+		// The trash is kept in the JSON dump only.
+		if e.DeletedAt != nil {
+			continue
+		}
+		calendar = append(calendar, ical.Event{
+			UID:       e.ID.String(),
+			Date:      e.Date,
+			StartsAt:  e.StartsAt,
+			EndsAt:    e.EndsAt,
+			Summary:   e.Description,
+			RemindAt:  e.RemindAt,
+			CreatedAt: e.CreatedAt,
+			UpdatedAt: e.UpdatedAt,
+		})
+	}
+
+	for _, e := range archived {
+		event := ical.Event{
+			UID:      e.ID.String(),
+			Date:     e.Date,
+			StartsAt: e.StartsAt,
+			EndsAt:   e.EndsAt,
+			Summary:  e.Description,
+		}
+		if e.OriginalCreatedAt != nil {
+			event.CreatedAt = *e.OriginalCreatedAt
+		}
+		if e.OriginalUpdatedAt != nil {
+			event.UpdatedAt = *e.OriginalUpdatedAt
+		}
+		calendar = append(calendar, event)
+	}
+
+	return calendar
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/user/repo"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"github.com/ilam072/event-calendar/pkg/ical"
+	"strings"
+	"time"
+)
+
+const (
+	dataFileName     = "export.json"
+	calendarFileName = "events.ics"
+)
+
+//go:generate mockgen -source=export.go -destination=../mocks/service_mocks.go -package=mocks
+type UserRepo interface {
+	GetUserByID(ctx context.Context, userID uuid.UUID) (domain.User, error)
+	GetIdentities(ctx context.Context, userID uuid.UUID) ([]domain.UserIdentity, error)
+}
+
+type EventRepo interface {
+	GetUserEvents(ctx context.Context, userID uuid.UUID) ([]domain.Event, error)
+	GetArchivedUserEvents(ctx context.Context, userID uuid.UUID) ([]domain.ArchivedEvent, error)
+	GetUserInvitations(ctx context.Context, userID uuid.UUID) ([]domain.Attendee, error)
+	GetUserHistory(ctx context.Context, userID uuid.UUID) ([]domain.EventRevision, error)
+}
+
+type CalendarRepo interface {
+	GetCalendars(ctx context.Context, userID uuid.UUID) ([]domain.Calendar, error)
+	GetUserShares(ctx context.Context, userID uuid.UUID) ([]domain.CalendarShare, error)
+}
+
+type APIKeyRepo interface {
+	GetAPIKeysByUser(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error)
+}
+
+type LoginThrottleRepo interface {
+	GetLoginThrottles(ctx context.Context, keys []string) ([]domain.LoginThrottle, error)
+}
+
+type Export struct {
+	userRepo          UserRepo
+	eventRepo         EventRepo
+	calendarRepo      CalendarRepo
+	apiKeyRepo        APIKeyRepo
+	loginThrottleRepo LoginThrottleRepo
+}
+
+func NewExport(userRepo UserRepo, eventRepo EventRepo, calendarRepo CalendarRepo, apiKeyRepo APIKeyRepo, loginThrottleRepo LoginThrottleRepo) *Export {
+	return &Export{
+		userRepo:          userRepo,
+		eventRepo:         eventRepo,
+		calendarRepo:      calendarRepo,
+		apiKeyRepo:        apiKeyRepo,
+		loginThrottleRepo: loginThrottleRepo,
+	}
+}
+
+// userData is everything read for an export.
+type userData struct {
+	user        domain.User
+	throttles   []domain.LoginThrottle
+	apiKeys     []domain.APIKey
+	identities  []domain.UserIdentity
+	calendars   []domain.Calendar
+	shares      []domain.CalendarShare
+	events      []domain.Event
+	archived    []domain.ArchivedEvent
+	invitations []domain.Attendee
+	history     []domain.EventRevision
+}
+
+// ExportUserData builds a zip archive with a JSON dump of the user's data and
+// an iCalendar file with their active and archived events.
+func (e *Export) ExportUserData(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	const op = "service.export.ExportUserData"
+
+	user, err := e.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrUserNotFound) {
+			return nil, errutils.Wrap(op, domain.ErrUserNotFound)
+		}
+		return nil, errutils.Wrap(op, err)
+	}
+
+	d, err := e.load(ctx, user)
+	if err != nil {
+		return nil, errutils.Wrap(op, err)
+	}
+
+	now := time.Now()
+	export := buildUserExport(d, now)
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, errutils.Wrap(op, err)
+	}
+
+	calendar := ical.Encode(buildCalendar(d.events, d.archived), now)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{
+		{name: dataFileName, data: data},
+		{name: calendarFileName, data: calendar},
+	} {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return nil, errutils.Wrap(op, err)
+		}
+		if _, err = w.Write(file.data); err != nil {
+			return nil, errutils.Wrap(op, err)
+		}
+	}
+	if err = zw.Close(); err != nil {
+		return nil, errutils.Wrap(op, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (e *Export) load(ctx context.Context, user domain.User) (userData, error) {
+	d := userData{user: user}
+
+	var err error
+	if d.throttles, err = e.loginThrottleRepo.GetLoginThrottles(ctx, sessionThrottleKeys(user)); err != nil {
+		return userData{}, err
+	}
+	if d.apiKeys, err = e.apiKeyRepo.GetAPIKeysByUser(ctx, user.ID); err != nil {
+		return userData{}, err
+	}
+	if d.identities, err = e.userRepo.GetIdentities(ctx, user.ID); err != nil {
+		return userData{}, err
+	}
+	if d.calendars, err = e.calendarRepo.GetCalendars(ctx, user.ID); err != nil {
+		return userData{}, err
+	}
+	if d.shares, err = e.calendarRepo.GetUserShares(ctx, user.ID); err != nil {
+		return userData{}, err
+	}
+	if d.events, err = e.eventRepo.GetUserEvents(ctx, user.ID); err != nil {
+		return userData{}, err
+	}
+	if d.archived, err = e.eventRepo.GetArchivedUserEvents(ctx, user.ID); err != nil {
+		return userData{}, err
+	}
+	if d.invitations, err = e.eventRepo.GetUserInvitations(ctx, user.ID); err != nil {
+		return userData{}, err
+	}
+	if d.history, err = e.eventRepo.GetUserHistory(ctx, user.ID); err != nil {
+		return userData{}, err
+	}
+
+	return d, nil
+}
+
+// Sign-in throttles are keyed like the user service does: the lowercased
+// email for the password and the user id for the second factor. Throttles of
+// client IPs are shared by everyone behind them and are not exported.
+const (
+	passwordThrottlePrefix = "account:"
+	mfaThrottlePrefix      = "mfa:"
+)
+
+func sessionThrottleKeys(user domain.User) []string {
+	return []string{
+		passwordThrottlePrefix + strings.ToLower(user.Email),
+		mfaThrottlePrefix + user.ID.String(),
+	}
+}
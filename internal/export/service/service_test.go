@@ -0,0 +1,180 @@
+package service_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ilam072/event-calendar/internal/export/mocks"
+	"github.com/ilam072/event-calendar/internal/export/service"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/internal/user/repo"
+)
+
+func TestExportUserData(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepo(ctrl)
+	eventRepo := mocks.NewMockEventRepo(ctrl)
+	calendarRepo := mocks.NewMockCalendarRepo(ctrl)
+	apiKeyRepo := mocks.NewMockAPIKeyRepo(ctrl)
+	throttleRepo := mocks.NewMockLoginThrottleRepo(ctrl)
+	svc := service.NewExport(userRepo, eventRepo, calendarRepo, apiKeyRepo, throttleRepo)
+
+	userID := uuid.New()
+	remindAt := time.Now().Add(time.Hour)
+	lockedUntil := time.Now().Add(10 * time.Minute)
+
+	user := domain.User{ID: userID, Email: "test@mail.com", PasswordHash: "SECRET_HASH"}
+	events := []domain.Event{
+		{ID: uuid.New(), UserID: userID, Date: time.Now(), Description: "Meeting", RemindAt: &remindAt},
+		{ID: uuid.New(), UserID: userID, Date: time.Now(), Description: "Lunch"},
+		{ID: uuid.New(), UserID: userID, Date: time.Now(), Description: "Cancelled", DeletedAt: &lockedUntil},
+	}
+	archived := []domain.ArchivedEvent{
+		{ID: uuid.New(), UserID: userID, Date: time.Now().AddDate(0, 0, -10), Description: "Old"},
+	}
+
+	userRepo.EXPECT().GetUserByID(gomock.Any(), userID).Return(user, nil)
+	throttleRepo.EXPECT().GetLoginThrottles(gomock.Any(), []string{"account:test@mail.com", "mfa:" + userID.String()}).
+		Return([]domain.LoginThrottle{{Key: "account:test@mail.com", Failures: 0, LastFailureAt: time.Now(), LockedUntil: &lockedUntil}}, nil)
+	apiKeyRepo.EXPECT().GetAPIKeysByUser(gomock.Any(), userID).
+		Return([]domain.APIKey{{ID: uuid.New(), UserID: userID, Name: "ci", Prefix: "ek_abc", SecretHash: "KEY_HASH"}}, nil)
+	userRepo.EXPECT().GetIdentities(gomock.Any(), userID).
+		Return([]domain.UserIdentity{{Issuer: "https://idp.example.com", Subject: "42", UserID: userID}}, nil)
+	calendarRepo.EXPECT().GetCalendars(gomock.Any(), userID).
+		Return([]domain.Calendar{{ID: uuid.New(), UserID: userID, Name: "Personal", Role: domain.CalendarRoleOwner}}, nil)
+	calendarRepo.EXPECT().GetUserShares(gomock.Any(), userID).
+		Return([]domain.CalendarShare{{ID: uuid.New(), Email: "bob@mail.com", Role: domain.CalendarRoleViewer}}, nil)
+	eventRepo.EXPECT().GetUserEvents(gomock.Any(), userID).Return(events, nil)
+	eventRepo.EXPECT().GetArchivedUserEvents(gomock.Any(), userID).Return(archived, nil)
+	eventRepo.EXPECT().GetUserInvitations(gomock.Any(), userID).
+		Return([]domain.Attendee{{ID: uuid.New(), EventID: uuid.New(), UserID: &userID, Email: user.Email, Status: domain.AttendeeStatusAccepted}}, nil)
+	eventRepo.EXPECT().GetUserHistory(gomock.Any(), userID).
+		Return([]domain.EventRevision{{ID: 1, EventID: events[0].ID, Action: domain.HistoryCreate, After: domain.NewEventSnapshot(events[0])}}, nil)
+
+	data, err := svc.ExportUserData(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	files := readArchive(t, data)
+
+	raw, ok := files["export.json"]
+	if !ok {
+		t.Fatalf("export.json is missing from archive")
+	}
+	if strings.Contains(string(raw), user.PasswordHash) {
+		t.Fatalf("export must not contain password hash")
+	}
+	if strings.Contains(string(raw), "KEY_HASH") {
+		t.Fatalf("export must not contain api key hash")
+	}
+
+	var export dto.UserExport
+	if err = json.Unmarshal(raw, &export); err != nil {
+		t.Fatalf("failed to decode export.json: %v", err)
+	}
+	if export.User.Email != user.Email {
+		t.Fatalf("expected email %s, got %s", user.Email, export.User.Email)
+	}
+	if len(export.Events) != 3 || len(export.ArchivedEvents) != 1 || len(export.Reminders) != 1 {
+		t.Fatalf("unexpected export contents: %+v", export)
+	}
+	if export.Events[2].DeletedAt == nil {
+		t.Fatalf("expected trashed event to carry deleted_at, got %+v", export.Events[2])
+	}
+	if len(export.Sessions) != 1 || export.Sessions[0].Kind != "password" || export.Sessions[0].LockedUntil == nil {
+		t.Fatalf("unexpected sessions: %+v", export.Sessions)
+	}
+	if len(export.APIKeys) != 1 || len(export.Identities) != 1 || len(export.Calendars) != 1 || len(export.Shares) != 1 {
+		t.Fatalf("unexpected account contents: %+v", export)
+	}
+	if len(export.Invitations) != 1 || len(export.History) != 1 || export.History[0].EventID != events[0].ID {
+		t.Fatalf("unexpected invitations or history: %+v", export)
+	}
+
+	calendar, ok := files["events.ics"]
+	if !ok {
+		t.Fatalf("events.ics is missing from archive")
+	}
+	if got := strings.Count(string(calendar), "BEGIN:VEVENT"); got != 3 {
+		t.Fatalf("expected 3 calendar events, got %d", got)
+	}
+}
+
+func TestExportUserData_UserNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepo(ctrl)
+	svc := service.NewExport(userRepo, mocks.NewMockEventRepo(ctrl), mocks.NewMockCalendarRepo(ctrl), mocks.NewMockAPIKeyRepo(ctrl), mocks.NewMockLoginThrottleRepo(ctrl))
+
+	userRepo.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Return(domain.User{}, repo.ErrUserNotFound)
+
+	_, err := svc.ExportUserData(context.Background(), uuid.New())
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestExportUserData_RepoError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepo(ctrl)
+	eventRepo := mocks.NewMockEventRepo(ctrl)
+	calendarRepo := mocks.NewMockCalendarRepo(ctrl)
+	apiKeyRepo := mocks.NewMockAPIKeyRepo(ctrl)
+	throttleRepo := mocks.NewMockLoginThrottleRepo(ctrl)
+	svc := service.NewExport(userRepo, eventRepo, calendarRepo, apiKeyRepo, throttleRepo)
+
+	userRepo.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Return(domain.User{}, nil)
+	throttleRepo.EXPECT().GetLoginThrottles(gomock.Any(), gomock.Any()).Return(nil, nil)
+	apiKeyRepo.EXPECT().GetAPIKeysByUser(gomock.Any(), gomock.Any()).Return(nil, nil)
+	userRepo.EXPECT().GetIdentities(gomock.Any(), gomock.Any()).Return(nil, nil)
+	calendarRepo.EXPECT().GetCalendars(gomock.Any(), gomock.Any()).Return(nil, nil)
+	calendarRepo.EXPECT().GetUserShares(gomock.Any(), gomock.Any()).Return(nil, nil)
+	eventRepo.EXPECT().GetUserEvents(gomock.Any(), gomock.Any()).Return(nil, errors.New("db failure"))
+
+	_, err := svc.ExportUserData(context.Background(), uuid.New())
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+func readArchive(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("failed to open archive: %v", err)
+	}
+
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", f.Name, err)
+		}
+		content, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", f.Name, err)
+		}
+		files[f.Name] = content
+	}
+
+	return files
+}
@@ -0,0 +1,117 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const checkTimeout = 2 * time.Second
+
+var errNotRunning = errors.New("not running")
+
+// Check tells whether a component works. Only critical checks make the app
+// not ready when they fail, the others are just reported.
+type Check struct {
+	Name     string
+	Critical bool
+	Check    func(ctx context.Context) error
+}
+
+type HealthHandler struct {
+	checks       []Check
+	shuttingDown atomic.Bool
+}
+
+func NewHealthHandler(checks ...Check) *HealthHandler {
+	return &HealthHandler{checks: checks}
+}
+
+// Shutdown makes the app report not ready, so that no new traffic is routed
+// to it while it shuts down.
+func (h *HealthHandler) Shutdown() {
+	h.shuttingDown.Store(true)
+}
+
+// Healthz reports that the process is alive. It checks nothing else, so that
+// a failing dependency does not get the app restarted.
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.HealthResponse{Status: dto.HealthUp})
+}
+
+// Readyz runs the checks concurrently and reports each component.
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	if h.shuttingDown.Load() {
+		c.JSON(http.StatusServiceUnavailable, dto.HealthResponse{Status: dto.HealthShuttingDown})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), checkTimeout)
+	defer cancel()
+
+	results := make([]dto.ComponentHealth, len(h.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			results[i] = dto.ComponentHealth{Status: dto.HealthUp, Critical: check.Critical}
+			if err := check.Check(ctx); err != nil {
+				results[i].Status = dto.HealthDown
+				results[i].Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	resp := dto.HealthResponse{Status: dto.HealthReady, Components: make(map[string]dto.ComponentHealth, len(h.checks))}
+	status := http.StatusOK
+	for i, check := range h.checks {
+		resp.Components[check.Name] = results[i]
+		if check.Critical && results[i].Status == dto.HealthDown {
+			resp.Status = dto.HealthNotReady
+			status = http.StatusServiceUnavailable
+		}
+	}
+
+	c.JSON(status, resp)
+}
+
+// Running checks a background worker.
+func Running(running func() bool) func(ctx context.Context) error {
+	return func(context.Context) error {
+		if !running() {
+			return errNotRunning
+		}
+		return nil
+	}
+}
+
+// Cached runs check at most once per ttl and otherwise returns the last
+// result, for checks that are slow or hit external services.
+func Cached(ttl time.Duration, check func(ctx context.Context) error) func(ctx context.Context) error {
+	var mu sync.Mutex
+	var checkedAt time.Time
+	var last error
+
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !checkedAt.IsZero() && time.Since(checkedAt) < ttl {
+			return last
+		}
+
+		last = check(ctx)
+		checkedAt = time.Now()
+
+		return last
+	}
+}
@@ -0,0 +1,100 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/ilam072/event-calendar/internal/health/rest"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func healthRouter(h *rest.HealthHandler) *gin.Engine {
+	r := gin.New()
+	r.GET("/healthz", h.Healthz)
+	r.GET("/readyz", h.Readyz)
+	return r
+}
+
+func up(context.Context) error { return nil }
+
+func down(context.Context) error { return errors.New("connection refused") }
+
+func readyz(t *testing.T, h *rest.HealthHandler) (int, dto.HealthResponse) {
+	req := httptest.NewRequest("GET", "/readyz", nil)
+	rec := httptest.NewRecorder()
+	healthRouter(h).ServeHTTP(rec, req)
+
+	var resp dto.HealthResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	return rec.Code, resp
+}
+
+func TestHealthz(t *testing.T) {
+	h := rest.NewHealthHandler(rest.Check{Name: "postgres", Critical: true, Check: down})
+
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	rec := httptest.NewRecorder()
+	healthRouter(h).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestReadyz_Ready(t *testing.T) {
+	h := rest.NewHealthHandler(
+		rest.Check{Name: "postgres", Critical: true, Check: up},
+		rest.Check{Name: "smtp", Check: down},
+	)
+
+	code, resp := readyz(t, h)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, dto.HealthReady, resp.Status)
+	assert.Equal(t, dto.HealthUp, resp.Components["postgres"].Status)
+	assert.Equal(t, dto.HealthDown, resp.Components["smtp"].Status)
+	assert.Equal(t, "connection refused", resp.Components["smtp"].Error)
+}
+
+func TestReadyz_CriticalDown(t *testing.T) {
+	running := false
+	h := rest.NewHealthHandler(
+		rest.Check{Name: "postgres", Critical: true, Check: up},
+		rest.Check{Name: "reminder_worker", Critical: true, Check: rest.Running(func() bool { return running })},
+	)
+
+	code, resp := readyz(t, h)
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, dto.HealthNotReady, resp.Status)
+	assert.Equal(t, dto.HealthDown, resp.Components["reminder_worker"].Status)
+}
+
+func TestReadyz_ShuttingDown(t *testing.T) {
+	h := rest.NewHealthHandler(rest.Check{Name: "postgres", Critical: true, Check: up})
+	h.Shutdown()
+
+	code, resp := readyz(t, h)
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, dto.HealthShuttingDown, resp.Status)
+}
+
+func TestCached(t *testing.T) {
+	calls := 0
+	check := rest.Cached(time.Hour, func(context.Context) error {
+		calls++
+		return nil
+	})
+
+	_ = check(context.Background())
+	_ = check(context.Background())
+
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,95 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type IdempotencyRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewIdempotencyRepo(db *pgxpool.Pool) *IdempotencyRepo {
+	return &IdempotencyRepo{db: db}
+}
+
+// Reserve claims the key for a request until ttl has passed. If the key is
+// already claimed, the stored key is returned with reserved set to false.
+func (r *IdempotencyRepo) Reserve(ctx context.Context, userID uuid.UUID, key string, requestHash string, ttl time.Duration) (domain.IdempotencyKey, bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (user_id, key, request_hash, expires_at)
+		VALUES ($1, $2, $3, now() + make_interval(secs => $4))
+		ON CONFLICT (user_id, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+		    status_code = NULL,
+		    response_body = NULL,
+		    created_at = now(),
+		    expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < now()
+		RETURNING created_at;
+	`
+
+	stored := domain.IdempotencyKey{UserID: userID, Key: key, RequestHash: requestHash}
+	err := r.db.QueryRow(ctx, query, userID, key, requestHash, ttl.Seconds()).Scan(&stored.CreatedAt)
+	if err == nil {
+		return stored, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return domain.IdempotencyKey{}, false, errutils.Wrap("failed to reserve idempotency key", err)
+	}
+
+	query = `
+		SELECT request_hash, COALESCE(status_code, 0), response_body, created_at
+		FROM idempotency_keys
+		WHERE user_id = $1 AND key = $2;
+	`
+
+	err = r.db.QueryRow(ctx, query, userID, key).
+		Scan(&stored.RequestHash, &stored.StatusCode, &stored.ResponseBody, &stored.CreatedAt)
+	if err != nil {
+		return domain.IdempotencyKey{}, false, errutils.Wrap("failed to get idempotency key", err)
+	}
+
+	return stored, false, nil
+}
+
+func (r *IdempotencyRepo) Complete(ctx context.Context, userID uuid.UUID, key string, statusCode int, body []byte) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $3, response_body = $4
+		WHERE user_id = $1 AND key = $2;
+	`
+
+	if _, err := r.db.Exec(ctx, query, userID, key, statusCode, body); err != nil {
+		return errutils.Wrap("failed to complete idempotency key", err)
+	}
+
+	return nil
+}
+
+// Release frees the key so that the request can be retried.
+func (r *IdempotencyRepo) Release(ctx context.Context, userID uuid.UUID, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2;`
+
+	if _, err := r.db.Exec(ctx, query, userID, key); err != nil {
+		return errutils.Wrap("failed to release idempotency key", err)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepo) DeleteExpired(ctx context.Context) error {
+	query := `DELETE FROM idempotency_keys WHERE expires_at < now();`
+
+	if _, err := r.db.Exec(ctx, query); err != nil {
+		return errutils.Wrap("failed to delete expired idempotency keys", err)
+	}
+
+	return nil
+}
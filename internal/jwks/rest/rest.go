@@ -0,0 +1,25 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ilam072/event-calendar/pkg/jwt"
+	"net/http"
+)
+
+//go:generate mockgen -source=rest.go -destination=../mocks/rest_mocks.go -package=mocks
+type KeySet interface {
+	JWKS() jwt.JWKS
+}
+
+type JWKSHandler struct {
+	keySet KeySet
+}
+
+func NewJWKSHandler(keySet KeySet) *JWKSHandler {
+	return &JWKSHandler{keySet: keySet}
+}
+
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keySet.JWKS())
+}
@@ -0,0 +1,40 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/ilam072/event-calendar/internal/jwks/mocks"
+	"github.com/ilam072/event-calendar/internal/jwks/rest"
+	"github.com/ilam072/event-calendar/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetJWKS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	keySet := mocks.NewMockKeySet(ctrl)
+	keySet.EXPECT().JWKS().Return(jwt.JWKS{Keys: []jwt.JWK{{Kty: "OKP", Kid: "2025-01", Alg: "EdDSA", Crv: "Ed25519", X: "x"}}})
+
+	h := rest.NewJWKSHandler(keySet)
+	r := gin.New()
+	r.GET("/.well-known/jwks.json", h.GetJWKS)
+
+	req := httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var set jwt.JWKS
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &set))
+	assert.Len(t, set.Keys, 1)
+	assert.Equal(t, "2025-01", set.Keys[0].Kid)
+}
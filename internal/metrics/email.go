@@ -0,0 +1,23 @@
+package metrics
+
+import "time"
+
+type Sender interface {
+	Send(subject string, message string, to string) error
+}
+
+type instrumentedSender struct {
+	sender Sender
+}
+
+// InstrumentSender records how long sending emails through sender takes.
+func InstrumentSender(sender Sender) Sender {
+	return instrumentedSender{sender: sender}
+}
+
+func (s instrumentedSender) Send(subject string, message string, to string) error {
+	start := time.Now()
+	err := s.sender.Send(subject, message, to)
+	emailDuration.WithLabelValues(Result(err)).Observe(time.Since(start).Seconds())
+	return err
+}
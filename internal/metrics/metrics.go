@@ -0,0 +1,89 @@
+// Package metrics defines the Prometheus metrics of the app. They are
+// registered with the default registry and served on /metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "calendar"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	emailDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "email_send_duration_seconds",
+		Help:      "Time spent sending emails, by result.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"result"})
+
+	RemindersScheduled = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reminders_scheduled_total",
+		Help:      "Reminders handed to the reminder worker.",
+	})
+
+	RemindersSent = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reminders_sent_total",
+		Help:      "Reminders sent.",
+	})
+
+	RemindersFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reminders_failed_total",
+		Help:      "Reminders that could not be sent.",
+	})
+
+	RemindersQueued = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "reminders_queue_depth",
+		Help:      "Reminders waiting to be sent by the reminder worker.",
+	})
+
+	JanitorDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "janitor_job_duration_seconds",
+		Help:      "Duration of janitor jobs, by job and result.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 20},
+	}, []string{"job", "result"})
+
+	JanitorArchivedEvents = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "janitor_archived_events_total",
+		Help:      "Past events moved to the archive.",
+	})
+)
+
+// Result is the label value for the outcome of an operation.
+func Result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// RegisterLogDrops exposes the number of log entries a logger dropped
+// because its buffer was full.
+func RegisterLogDrops(dropped func() uint64) {
+	promauto.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "log_entries_dropped_total",
+		Help:      "Log entries dropped because the async logger buffer was full.",
+	}, func() float64 {
+		return float64(dropped())
+	})
+}
@@ -0,0 +1,49 @@
+package metrics_test
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/ilam072/event-calendar/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type failingSender struct{}
+
+func (failingSender) Send(string, string, string) error {
+	return errors.New("smtp down")
+}
+
+func scrape(t *testing.T) string {
+	rec := httptest.NewRecorder()
+	promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{}).
+		ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	return rec.Body.String()
+}
+
+func TestHTTP_LabelsByRoute(t *testing.T) {
+	r := gin.New()
+	r.Use(metrics.HTTP())
+	r.GET("/events/:id", func(c *gin.Context) {
+		c.Status(http.StatusNotFound)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/events/42", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/nope", nil))
+
+	body := scrape(t)
+	assert.Contains(t, body, `calendar_http_requests_total{method="GET",route="/events/:id",status="404"} 1`)
+	assert.Contains(t, body, `route="unmatched"`)
+	assert.NotContains(t, body, `route="/events/42"`)
+}
+
+func TestInstrumentSender(t *testing.T) {
+	err := metrics.InstrumentSender(failingSender{}).Send("subject", "message", "to@mail.com")
+
+	assert.Error(t, err)
+	assert.Contains(t, scrape(t), `calendar_email_send_duration_seconds_count{result="error"} 1`)
+}
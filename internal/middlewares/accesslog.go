@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"net/http"
+	"time"
+)
+
+// AccessLog logs every request once it is served, with the request and user
+// IDs of its context. Server errors are logged as errors and client errors
+// as warnings. Must run after RequestID.
+func AccessLog(log logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+
+		c.Next()
+
+		status := c.Writer.Status()
+
+		var event logger.Event
+		switch {
+		case status >= http.StatusInternalServerError:
+			event = log.Error()
+		case status >= http.StatusBadRequest:
+			event = log.Warn()
+		default:
+			event = log.Info()
+		}
+
+		event = event.Ctx(c.Request.Context()).
+			Str("method", c.Request.Method).
+			Str("path", path).
+			Str("route", c.FullPath()).
+			Any("status", status).
+			Any("latency_ms", time.Since(start).Milliseconds()).
+			Any("bytes", c.Writer.Size()).
+			Str("client_ip", c.ClientIP()).
+			Str("user_agent", c.Request.UserAgent())
+		if len(c.Errors) > 0 {
+			event = event.Str("errors", c.Errors.String())
+		}
+
+		event.Msg("request served")
+	}
+}
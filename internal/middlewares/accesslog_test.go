@@ -0,0 +1,48 @@
+package middlewares_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/ilam072/event-calendar/internal/middlewares"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAccessLog(t *testing.T) {
+	tests := []struct {
+		status int
+		level  string
+	}{
+		{status: http.StatusOK, level: "info"},
+		{status: http.StatusNotFound, level: "warn"},
+		{status: http.StatusInternalServerError, level: "error"},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		r := gin.New()
+		r.Use(middlewares.RequestID())
+		r.Use(middlewares.AccessLog(logger.NewZerologLogger(zerolog.New(&out))))
+		r.GET("/events/:id", func(c *gin.Context) {
+			c.Status(tt.status)
+		})
+
+		req := httptest.NewRequest("GET", "/events/42", nil)
+		req.Header.Set(middlewares.RequestIDHeader, "abc-123")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+		assert.Equal(t, tt.level, entry["level"])
+		assert.Equal(t, "abc-123", entry["request_id"])
+		assert.Equal(t, "/events/42", entry["path"])
+		assert.Equal(t, "/events/:id", entry["route"])
+		assert.Equal(t, float64(tt.status), entry["status"])
+	}
+}
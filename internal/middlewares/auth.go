@@ -1,9 +1,14 @@
 package middlewares
 
 import (
+	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/apikey"
 	"github.com/ilam072/event-calendar/pkg/jwt"
-	"github.com/rs/zerolog/log"
+	"github.com/ilam072/event-calendar/pkg/logger"
 	"net/http"
 	"strings"
 	"time"
@@ -11,20 +16,41 @@ import (
 
 const bearerPrefix = "Bearer "
 
-func Auth(manager *jwt.Manager) gin.HandlerFunc {
+const (
+	AuthMethodKey    = "auth_method"
+	AuthMethodJWT    = "jwt"
+	AuthMethodAPIKey = "api_key"
+)
+
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, rawKey string) (domain.APIKey, error)
+}
+
+type UserChecker interface {
+	IsActive(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// Auth authenticates the request by a JWT or an API key. Tokens of users that
+// were disabled or removed since they were issued are rejected.
+func Auth(manager *jwt.Manager, apiKeys APIKeyAuthenticator, users UserChecker, log logger.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" || !strings.HasPrefix(authHeader, bearerPrefix) {
-			log.Logger.Warn().Msg("invalid header")
+			log.Warn().Ctx(c.Request.Context()).Msg("invalid header")
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 			return
 		}
 
 		token := strings.TrimPrefix(authHeader, bearerPrefix)
 
+		if apikey.IsAPIKey(token) {
+			authAPIKey(c, apiKeys, token, log)
+			return
+		}
+
 		claims, err := manager.ParseToken(token)
 		if err != nil {
-			log.Logger.Err(err).Msg("failed to parse token")
+			log.Error().Ctx(c.Request.Context()).Err(err).Msg("failed to parse token")
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 			return
 		}
@@ -34,7 +60,72 @@ func Auth(manager *jwt.Manager) gin.HandlerFunc {
 			return
 		}
 
+		userID, err := uuid.Parse(claims.UserID)
+		if err != nil {
+			log.Warn().Ctx(c.Request.Context()).Str("user_id", claims.UserID).Msg("failed to parse user id into uuid")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		active, err := users.IsActive(c.Request.Context(), userID)
+		if err != nil {
+			log.Error().Ctx(c.Request.Context()).Err(err).Msg("failed to check user")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		if !active {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
+		c.Set(AuthMethodKey, AuthMethodJWT)
+		c.Request = c.Request.WithContext(logger.NewContext(c.Request.Context(), "user_id", claims.UserID))
+		c.Next()
+	}
+}
+
+func authAPIKey(c *gin.Context, apiKeys APIKeyAuthenticator, token string, log logger.Logger) {
+	key, err := apiKeys.Authenticate(c.Request.Context(), token)
+	if err != nil {
+		log.Error().Ctx(c.Request.Context()).Err(err).Msg("failed to authenticate api key")
+		if errors.Is(err, domain.ErrInvalidAPIKey) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	if key.Scope == domain.APIKeyScopeRead && !isReadOnlyMethod(c.Request.Method) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "api key is read-only"})
+		return
+	}
+
+	c.Set("user_id", key.UserID.String())
+	c.Set(AuthMethodKey, AuthMethodAPIKey)
+	c.Request = c.Request.WithContext(logger.NewContext(c.Request.Context(), "user_id", key.UserID.String()))
+	c.Next()
+}
+
+// RequireSession rejects requests authenticated with an API key. It guards
+// the account's credentials, so that a leaked key cannot be used to mint or
+// revoke keys or to change two-factor authentication. It must run after Auth.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString(AuthMethodKey) != AuthMethodJWT {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "this endpoint can not be used with an api key"})
+			return
+		}
 		c.Next()
 	}
 }
+
+func isReadOnlyMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,86 @@
+package middlewares_test
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/middlewares"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/jwt"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type activeUsers map[uuid.UUID]bool
+
+func (u activeUsers) IsActive(_ context.Context, userID uuid.UUID) (bool, error) {
+	return u[userID], nil
+}
+
+type noAPIKeys struct{}
+
+func (noAPIKeys) Authenticate(context.Context, string) (domain.APIKey, error) {
+	return domain.APIKey{}, domain.ErrInvalidAPIKey
+}
+
+func TestAuth_DisabledUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	manager := jwt.NewManager([]byte("secret"))
+	active, disabled := uuid.New(), uuid.New()
+	users := activeUsers{active: true}
+
+	r := gin.New()
+	r.GET("/events", middlewares.Auth(manager, noAPIKeys{}, users, &logger.DummyLogger{}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	cases := map[uuid.UUID]int{
+		active:   http.StatusOK,
+		disabled: http.StatusUnauthorized,
+	}
+	for userID, want := range cases {
+		token, err := manager.NewToken(userID.String(), time.Minute)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest("GET", "/events", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		assert.Equal(t, want, rec.Code, "user %s", userID)
+	}
+}
+
+func sessionRouter(authMethod string) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if authMethod != "" {
+			c.Set(middlewares.AuthMethodKey, authMethod)
+		}
+	})
+	r.POST("/me/totp", middlewares.RequireSession(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestRequireSession(t *testing.T) {
+	cases := map[string]int{
+		middlewares.AuthMethodJWT:    http.StatusOK,
+		middlewares.AuthMethodAPIKey: http.StatusForbidden,
+		"":                           http.StatusForbidden,
+	}
+
+	for authMethod, want := range cases {
+		rec := httptest.NewRecorder()
+		sessionRouter(authMethod).ServeHTTP(rec, httptest.NewRequest("POST", "/me/totp", nil))
+
+		assert.Equal(t, want, rec.Code, "auth method %q", authMethod)
+	}
+}
@@ -0,0 +1,148 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/response"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	maxIdempotencyKeyLen = 255
+)
+
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, userID uuid.UUID, key string, requestHash string, ttl time.Duration) (domain.IdempotencyKey, bool, error)
+	Complete(ctx context.Context, userID uuid.UUID, key string, statusCode int, body []byte) error
+	Release(ctx context.Context, userID uuid.UUID, key string) error
+}
+
+// Idempotency replays the stored response when a request is retried with the
+// same Idempotency-Key header, for ttl after the first one. Reusing a key for
+// a different request is rejected. Server errors are not stored, so that the
+// request can be retried. Must run after Auth.
+func Idempotency(store IdempotencyStore, ttl time.Duration, log logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLen {
+			response.BadRequest(c, "Idempotency-Key must be at most 255 characters")
+			c.Abort()
+			return
+		}
+
+		userID, err := uuid.Parse(c.GetString("user_id"))
+		if err != nil {
+			response.Unauthorized(c, "user_id must be uuid format")
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			response.BadRequest(c, "invalid request body")
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		hash := requestHash(c.Request, body)
+		stored, reserved, err := store.Reserve(ctx, userID, key, hash, ttl)
+		if err != nil {
+			log.Error().Ctx(c.Request.Context()).Err(err).Msg("failed to reserve idempotency key")
+			response.InternalServerError(c)
+			c.Abort()
+			return
+		}
+
+		if !reserved {
+			replay(c, stored, hash)
+			return
+		}
+
+		// The response has been sent, so the outcome is stored even if the
+		// client has gone away in the meantime.
+		ctx = context.WithoutCancel(ctx)
+
+		// A panicking handler stores no response, so the key is released for
+		// the retry. The panic keeps unwinding to the recovery middleware.
+		handled := false
+		defer func() {
+			if handled {
+				return
+			}
+			if err := store.Release(ctx, userID, key); err != nil {
+				log.Error().Ctx(ctx).Err(err).Msg("failed to release idempotency key")
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+		handled = true
+
+		if status := recorder.Status(); status >= http.StatusInternalServerError {
+			err = store.Release(ctx, userID, key)
+		} else {
+			err = store.Complete(ctx, userID, key, status, recorder.body.Bytes())
+		}
+		if err != nil {
+			log.Error().Ctx(c.Request.Context()).Err(err).Msg("failed to store idempotent response")
+		}
+	}
+}
+
+func replay(c *gin.Context, stored domain.IdempotencyKey, hash string) {
+	defer c.Abort()
+
+	if stored.RequestHash != hash {
+		response.Error(c, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_REUSED", "Idempotency-Key was already used for a different request")
+		return
+	}
+
+	if !stored.Completed() {
+		response.Conflict(c, "IDEMPOTENCY_KEY_IN_USE", "a request with this Idempotency-Key is still in progress")
+		return
+	}
+
+	c.Header("Idempotent-Replayed", "true")
+	c.Data(stored.StatusCode, "application/json; charset=utf-8", stored.ResponseBody)
+}
+
+// requestHash identifies a request by its method, path and body.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,156 @@
+package middlewares_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/middlewares"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type memoryStore struct {
+	keys map[string]domain.IdempotencyKey
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{keys: make(map[string]domain.IdempotencyKey)}
+}
+
+func (s *memoryStore) Reserve(_ context.Context, userID uuid.UUID, key string, requestHash string, _ time.Duration) (domain.IdempotencyKey, bool, error) {
+	id := userID.String() + "/" + key
+	if stored, ok := s.keys[id]; ok {
+		return stored, false, nil
+	}
+	s.keys[id] = domain.IdempotencyKey{UserID: userID, Key: key, RequestHash: requestHash}
+	return s.keys[id], true, nil
+}
+
+func (s *memoryStore) Complete(_ context.Context, userID uuid.UUID, key string, statusCode int, body []byte) error {
+	id := userID.String() + "/" + key
+	stored := s.keys[id]
+	stored.StatusCode, stored.ResponseBody = statusCode, body
+	s.keys[id] = stored
+	return nil
+}
+
+func (s *memoryStore) Release(_ context.Context, userID uuid.UUID, key string) error {
+	delete(s.keys, userID.String()+"/"+key)
+	return nil
+}
+
+func idempotentRouter(store middlewares.IdempotencyStore, userID uuid.UUID, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.POST("/events", func(c *gin.Context) {
+		c.Set("user_id", userID.String())
+	}, middlewares.Idempotency(store, time.Hour, &logger.DummyLogger{}), handler)
+	return r
+}
+
+func post(r *gin.Engine, key string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/events", bytes.NewBufferString(body))
+	req.Header.Set("Idempotency-Key", key)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotency_ReplaysResponse(t *testing.T) {
+	calls := 0
+	r := idempotentRouter(newMemoryStore(), uuid.New(), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{"event_id": calls})
+	})
+
+	first := post(r, "key-1", `{"description":"x"}`)
+	second := post(r, "key-1", `{"description":"x"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "true", second.Header().Get("Idempotent-Replayed"))
+}
+
+func TestIdempotency_DifferentBody(t *testing.T) {
+	r := idempotentRouter(newMemoryStore(), uuid.New(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	post(r, "key-1", `{"description":"x"}`)
+	rec := post(r, "key-1", `{"description":"y"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestIdempotency_InProgress(t *testing.T) {
+	var r *gin.Engine
+	var retry *httptest.ResponseRecorder
+	r = idempotentRouter(newMemoryStore(), uuid.New(), func(c *gin.Context) {
+		if retry == nil {
+			// the client retries before the first request has finished
+			retry = post(r, "key-1", `{}`)
+		}
+		c.Status(http.StatusOK)
+	})
+
+	post(r, "key-1", `{}`)
+
+	assert.Equal(t, http.StatusConflict, retry.Code)
+}
+
+func TestIdempotency_ServerErrorIsNotStored(t *testing.T) {
+	calls := 0
+	r := idempotentRouter(newMemoryStore(), uuid.New(), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			c.JSON(http.StatusInternalServerError, gin.H{})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	post(r, "key-1", `{}`)
+	rec := post(r, "key-1", `{}`)
+
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestIdempotency_PanicReleasesKey(t *testing.T) {
+	calls := 0
+	r := idempotentRouter(newMemoryStore(), uuid.New(), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	first := post(r, "key-1", `{}`)
+	rec := post(r, "key-1", `{}`)
+
+	assert.Equal(t, http.StatusInternalServerError, first.Code)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestIdempotency_NoKey(t *testing.T) {
+	calls := 0
+	r := idempotentRouter(newMemoryStore(), uuid.New(), func(c *gin.Context) {
+		calls++
+		c.Status(http.StatusOK)
+	})
+
+	post(r, "", `{}`)
+	post(r, "", `{}`)
+
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,48 @@
+package middlewares_test
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ilam072/event-calendar/internal/middlewares"
+	"github.com/ilam072/event-calendar/pkg/requestid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func requestIDRouter(seen *string) *gin.Engine {
+	r := gin.New()
+	r.Use(middlewares.RequestID())
+	r.GET("/", func(c *gin.Context) {
+		*seen = requestid.FromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestRequestID_KeepsIncoming(t *testing.T) {
+	var seen string
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(middlewares.RequestIDHeader, "abc-123")
+	rec := httptest.NewRecorder()
+	requestIDRouter(&seen).ServeHTTP(rec, req)
+
+	assert.Equal(t, "abc-123", rec.Header().Get(middlewares.RequestIDHeader))
+	assert.Equal(t, "abc-123", seen)
+}
+
+func TestRequestID_ReplacesInvalid(t *testing.T) {
+	for _, id := range []string{"", "bad id", strings.Repeat("a", 65)} {
+		var seen string
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(middlewares.RequestIDHeader, id)
+		rec := httptest.NewRecorder()
+		requestIDRouter(&seen).ServeHTTP(rec, req)
+
+		got := rec.Header().Get(middlewares.RequestIDHeader)
+		assert.NotEmpty(t, got)
+		assert.NotEqual(t, id, got)
+		assert.Equal(t, got, seen)
+	}
+}
@@ -0,0 +1,54 @@
+package response
+
+import (
+	"github.com/gin-gonic/gin"
+	"html/template"
+	"net/http"
+)
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><meta name="robots" content="noindex"><title>{{.Title}}</title></head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Message}}</p>
+{{- if .Action}}
+<form method="post" action="{{.Action}}">
+{{- range $name, $value := .Fields}}
+<input type="hidden" name="{{$name}}" value="{{$value}}">
+{{- end}}
+<button type="submit">{{.Button}}</button>
+</form>
+{{- end}}
+</body>
+</html>
+`))
+
+type page struct {
+	Title   string
+	Message string
+	Action  string
+	Button  string
+	Fields  map[string]string
+}
+
+// ConfirmPage renders a form that posts fields to action. Links in emails are
+// opened by mail scanners and previews, so they lead to this page and the
+// change is only made once the user submits it.
+func ConfirmPage(c *gin.Context, title, message, button, action string, fields map[string]string) {
+	renderPage(c, http.StatusOK, page{Title: title, Message: message, Action: action, Button: button, Fields: fields})
+}
+
+// Page renders a plain message, such as the outcome of a ConfirmPage form.
+func Page(c *gin.Context, status int, title, message string) {
+	renderPage(c, status, page{Title: title, Message: message})
+}
+
+func renderPage(c *gin.Context, status int, p page) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("Cache-Control", "no-store")
+	c.Status(status)
+	if err := pageTemplate.Execute(c.Writer, p); err != nil {
+		_ = c.Error(err)
+	}
+}
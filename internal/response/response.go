@@ -2,12 +2,16 @@ package response
 
 import (
 	"github.com/gin-gonic/gin"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type Err struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
+	Details any    `json:"details,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -31,14 +35,48 @@ func Conflict(c *gin.Context, code string, message string) {
 	Error(c, http.StatusConflict, code, message)
 }
 
+// ConflictWithDetails is Conflict with extra data that helps the client
+// resolve the conflict, such as the conflicting resources.
+func ConflictWithDetails(c *gin.Context, code string, message string, details any) {
+	c.JSON(http.StatusConflict, ErrorResponse{Error: Err{
+		Code:    code,
+		Message: message,
+		Details: details,
+	}})
+}
+
 func NotFound(c *gin.Context) {
 	Error(c, http.StatusNotFound, "NOT_FOUND", "resource not found")
 }
 
+func PreconditionFailed(c *gin.Context, message string) {
+	Error(c, http.StatusPreconditionFailed, "PRECONDITION_FAILED", message)
+}
+
 func Unauthorized(c *gin.Context, message string) {
 	Error(c, http.StatusUnauthorized, "UNAUTHORIZED", message)
 }
 
+func Forbidden(c *gin.Context, message string) {
+	Error(c, http.StatusForbidden, "FORBIDDEN", message)
+}
+
+func TooManyRequests(c *gin.Context, retryAfter time.Duration, message string) {
+	setRetryAfter(c, retryAfter)
+	Error(c, http.StatusTooManyRequests, "TOO_MANY_REQUESTS", message)
+}
+
+func Locked(c *gin.Context, retryAfter time.Duration, message string) {
+	setRetryAfter(c, retryAfter)
+	Error(c, http.StatusLocked, "LOCKED", message)
+}
+
 func InternalServerError(c *gin.Context) {
 	Error(c, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error, try again later")
 }
+
+// setRetryAfter sets the Retry-After header in whole seconds, rounded up.
+func setRetryAfter(c *gin.Context, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(max(seconds, 1)))
+}
@@ -2,28 +2,128 @@ package router
 
 import (
 	"github.com/gin-gonic/gin"
+	apikeyrest "github.com/ilam072/event-calendar/internal/apikey/rest"
+	calendarrest "github.com/ilam072/event-calendar/internal/calendar/rest"
 	eventrest "github.com/ilam072/event-calendar/internal/event/rest"
+	exportrest "github.com/ilam072/event-calendar/internal/export/rest"
+	healthrest "github.com/ilam072/event-calendar/internal/health/rest"
+	jwksrest "github.com/ilam072/event-calendar/internal/jwks/rest"
+	"github.com/ilam072/event-calendar/internal/metrics"
 	"github.com/ilam072/event-calendar/internal/middlewares"
+	schedulingrest "github.com/ilam072/event-calendar/internal/scheduling/rest"
 	userrest "github.com/ilam072/event-calendar/internal/user/rest"
 	"github.com/ilam072/event-calendar/pkg/jwt"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"net/http"
+	"time"
 )
 
-func New(userHandler *userrest.UserHandler, eventHandler *eventrest.EventHandler, manager *jwt.Manager) *gin.Engine {
+func New(
+	userHandler *userrest.UserHandler,
+	oidcHandler *userrest.OIDCHandler,
+	eventHandler *eventrest.EventHandler,
+	attendeeHandler *eventrest.AttendeeHandler,
+	batchHandler *eventrest.BatchHandler,
+	trashHandler *eventrest.TrashHandler,
+	calendarHandler *calendarrest.CalendarHandler,
+	shareHandler *calendarrest.ShareHandler,
+	schedulingHandler *schedulingrest.SchedulingHandler,
+	exportHandler *exportrest.ExportHandler,
+	apiKeyHandler *apikeyrest.APIKeyHandler,
+	jwksHandler *jwksrest.JWKSHandler,
+	healthHandler *healthrest.HealthHandler,
+	manager *jwt.Manager,
+	apiKeys middlewares.APIKeyAuthenticator,
+	users middlewares.UserChecker,
+	idempotencyKeys middlewares.IdempotencyStore,
+	idempotencyTTL time.Duration,
+	log logger.Logger,
+) *gin.Engine {
 	engine := gin.New()
-	engine.Use(gin.Logger())
+	engine.Use(middlewares.RequestID())
+	engine.Use(otelgin.Middleware("event-calendar", otelgin.WithFilter(traced)))
+	engine.Use(metrics.HTTP())
+	engine.Use(middlewares.AccessLog(log))
 	engine.Use(gin.Recovery())
 
+	engine.GET("/healthz", healthHandler.Healthz)
+	engine.GET("/readyz", healthHandler.Readyz)
+	engine.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	engine.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+	// rsvp links from invitation emails, authenticated by the signed token
+	engine.GET("/rsvp", attendeeHandler.RSVPPage)
+	engine.POST("/rsvp", attendeeHandler.RespondByLink)
+
 	auth := engine.Group("/auth")
 	// user
 	auth.POST("sign-up", userHandler.SignUp)
 	auth.POST("sign-in", userHandler.SignIn)
+	auth.POST("sign-in/mfa", userHandler.VerifyMFA)
+	// link from the sign-up email, authenticated by the signed token
+	auth.GET("verify-email", userHandler.VerifyEmailPage)
+	auth.POST("verify-email", userHandler.VerifyEmail)
+	// single sign-on, registered only when an identity provider is configured
+	if oidcHandler != nil {
+		auth.GET("oidc/login", oidcHandler.Login)
+		auth.GET("oidc/callback", oidcHandler.Callback)
+	}
 
-	api := engine.Group("/api/v1", middlewares.Auth(manager))
+	api := engine.Group("/api/v1", middlewares.Auth(manager, apiKeys, users, log))
+	idempotent := middlewares.Idempotency(idempotencyKeys, idempotencyTTL, log)
+	session := middlewares.RequireSession()
+	// calendar
+	api.POST("/calendars", calendarHandler.CreateCalendar)
+	api.GET("/calendars", calendarHandler.GetCalendars)
+	api.GET("/calendars/:id", calendarHandler.GetCalendar)
+	api.PUT("/calendars/:id", calendarHandler.UpdateCalendar)
+	api.DELETE("/calendars/:id", calendarHandler.DeleteCalendar)
+	// calendar sharing
+	api.GET("/calendars/:id/shares", shareHandler.GetShares)
+	api.POST("/calendars/:id/shares", shareHandler.ShareCalendar)
+	api.DELETE("/calendars/:id/shares/:share_id", shareHandler.RevokeShare)
 	// event
-	api.POST("/events", eventHandler.CreateEvent)
-	api.GET("/events", eventHandler.GetEvents) // query ?period=day&date=2025-08-30
+	api.POST("/events", idempotent, eventHandler.CreateEvent)
+	api.GET("/events", eventHandler.GetEvents) // query ?period=day&date=2025-08-30&calendar_id=<uuid>,<uuid>
+	api.GET("/events/:id", eventHandler.GetEvent)
 	api.PUT("/events/:id", eventHandler.UpdateEvent)
+	api.PATCH("/events/:id", eventHandler.PatchEvent) // application/merge-patch+json
 	api.DELETE("/events/:id", eventHandler.DeleteEvent)
+	api.GET("/events/:id/history", eventHandler.GetHistory)
+	api.POST("/events/:id/history/:revision_id/revert", eventHandler.RevertEvent)
+	api.POST("/events:action", idempotent, batchHandler.Batch) // POST /events:batch
+	// trash
+	api.GET("/trash", trashHandler.GetTrash)
+	api.POST("/trash/:id/restore", trashHandler.RestoreEvent)
+	api.DELETE("/trash", trashHandler.EmptyTrash)
+	// attendees
+	api.POST("/events/:id/attendees", attendeeHandler.AddAttendees)
+	api.DELETE("/events/:id/attendees/:attendee_id", attendeeHandler.RemoveAttendee)
+	api.PUT("/events/:id/rsvp", attendeeHandler.Respond)
+	// scheduling
+	api.POST("/freebusy", schedulingHandler.FreeBusy)
+	api.POST("/scheduling/suggest", schedulingHandler.SuggestSlots)
+	// two-factor authentication
+	api.POST("/me/totp", session, userHandler.EnrollTOTP)
+	api.POST("/me/totp/confirm", session, userHandler.ConfirmTOTP)
+	api.DELETE("/me/totp", session, userHandler.DisableTOTP)
+	// export
+	api.GET("/me/export", exportHandler.ExportUserData)
+	// api keys
+	api.GET("/api-keys", session, apiKeyHandler.GetAPIKeys)
+	api.POST("/api-keys", session, apiKeyHandler.CreateAPIKey)
+	api.DELETE("/api-keys/:id", session, apiKeyHandler.DeleteAPIKey)
 
 	return engine
 }
+
+// traced leaves probes and scrapes out of traces.
+func traced(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/healthz", "/readyz", "/metrics":
+		return false
+	default:
+		return true
+	}
+}
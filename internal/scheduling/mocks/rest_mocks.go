@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: rest.go
+//
+// Generated by this command:
+//
+//	mockgen -source=rest.go -destination=../mocks/rest_mocks.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	dto "github.com/ilam072/event-calendar/internal/types/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockScheduling is a mock of Scheduling interface.
+type MockScheduling struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchedulingMockRecorder
+	isgomock struct{}
+}
+
+// MockSchedulingMockRecorder is the mock recorder for MockScheduling.
+type MockSchedulingMockRecorder struct {
+	mock *MockScheduling
+}
+
+// NewMockScheduling creates a new mock instance.
+func NewMockScheduling(ctrl *gomock.Controller) *MockScheduling {
+	mock := &MockScheduling{ctrl: ctrl}
+	mock.recorder = &MockSchedulingMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduling) EXPECT() *MockSchedulingMockRecorder {
+	return m.recorder
+}
+
+// FreeBusy mocks base method.
+func (m *MockScheduling) FreeBusy(ctx context.Context, req dto.FreeBusyRequest, userID uuid.UUID) (dto.FreeBusyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FreeBusy", ctx, req, userID)
+	ret0, _ := ret[0].(dto.FreeBusyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FreeBusy indicates an expected call of FreeBusy.
+func (mr *MockSchedulingMockRecorder) FreeBusy(ctx, req, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreeBusy", reflect.TypeOf((*MockScheduling)(nil).FreeBusy), ctx, req, userID)
+}
+
+// SuggestSlots mocks base method.
+func (m *MockScheduling) SuggestSlots(ctx context.Context, req dto.SuggestSlotsRequest, userID uuid.UUID) (dto.SuggestSlotsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestSlots", ctx, req, userID)
+	ret0, _ := ret[0].(dto.SuggestSlotsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestSlots indicates an expected call of SuggestSlots.
+func (mr *MockSchedulingMockRecorder) SuggestSlots(ctx, req, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestSlots", reflect.TypeOf((*MockScheduling)(nil).SuggestSlots), ctx, req, userID)
+}
+
+// MockValidator is a mock of Validator interface.
+type MockValidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockValidatorMockRecorder
+	isgomock struct{}
+}
+
+// MockValidatorMockRecorder is the mock recorder for MockValidator.
+type MockValidatorMockRecorder struct {
+	mock *MockValidator
+}
+
+// NewMockValidator creates a new mock instance.
+func NewMockValidator(ctrl *gomock.Controller) *MockValidator {
+	mock := &MockValidator{ctrl: ctrl}
+	mock.recorder = &MockValidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockValidator) EXPECT() *MockValidatorMockRecorder {
+	return m.recorder
+}
+
+// Validate mocks base method.
+func (m *MockValidator) Validate(i any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate", i)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Validate indicates an expected call of Validate.
+func (mr *MockValidatorMockRecorder) Validate(i any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockValidator)(nil).Validate), i)
+}
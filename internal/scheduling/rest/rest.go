@@ -0,0 +1,119 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/response"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"net/http"
+)
+
+//go:generate mockgen -source=rest.go -destination=../mocks/rest_mocks.go -package=mocks
+type Scheduling interface {
+	FreeBusy(ctx context.Context, req dto.FreeBusyRequest, userID uuid.UUID) (dto.FreeBusyResponse, error)
+	SuggestSlots(ctx context.Context, req dto.SuggestSlotsRequest, userID uuid.UUID) (dto.SuggestSlotsResponse, error)
+}
+
+type Validator interface {
+	Validate(i interface{}) error
+}
+
+type SchedulingHandler struct {
+	scheduling Scheduling
+	validator  Validator
+	logger     logger.Logger
+}
+
+func NewSchedulingHandler(scheduling Scheduling, validator Validator, logger logger.Logger) *SchedulingHandler {
+	return &SchedulingHandler{scheduling: scheduling, validator: validator, logger: logger}
+}
+
+func (h *SchedulingHandler) FreeBusy(c *gin.Context) {
+	var req dto.FreeBusyRequest
+	if err := c.BindJSON(&req); err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to bind free/busy json")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.scheduling.FreeBusy(c.Request.Context(), req, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrRangeTooLong) {
+			response.BadRequest(c, "time range must not exceed 62 days")
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("user_id", userID.String()).Msg("failed to get free/busy")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *SchedulingHandler) SuggestSlots(c *gin.Context) {
+	var req dto.SuggestSlotsRequest
+	if err := c.BindJSON(&req); err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to bind suggest slots json")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.scheduling.SuggestSlots(c.Request.Context(), req, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRangeTooLong):
+			response.BadRequest(c, "time range must not exceed 62 days")
+		case errors.Is(err, domain.ErrInvalidWorkday):
+			response.BadRequest(c, "workday_start must be before workday_end")
+		default:
+			h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("user_id", userID.String()).Msg("failed to suggest slots")
+			response.InternalServerError(c)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *SchedulingHandler) getUserData(c *gin.Context) (uuid.UUID, bool) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		h.logger.Error().Ctx(c.Request.Context()).Msg("failed to get user_id from ctx")
+		response.Unauthorized(c, "missing user_id in token")
+		return uuid.Nil, false
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Any("user_id", userID).Msg("failed to parse user id into uuid")
+		response.Unauthorized(c, "user_id must be uuid format")
+		return uuid.Nil, false
+	}
+
+	return userUUID, true
+}
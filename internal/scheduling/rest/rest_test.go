@@ -0,0 +1,105 @@
+package rest_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/scheduling/mocks"
+	"github.com/ilam072/event-calendar/internal/scheduling/rest"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var log = &logger.DummyLogger{}
+
+func TestFreeBusy_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	start := time.Date(2025, time.September, 1, 10, 0, 0, 0, time.UTC)
+
+	mockScheduling := mocks.NewMockScheduling(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
+	mockScheduling.EXPECT().
+		FreeBusy(gomock.Any(), gomock.Any(), userID).
+		Return(dto.FreeBusyResponse{Users: []dto.UserFreeBusy{{
+			Email: "bob@example.com",
+			Busy:  []dto.BusyInterval{{Start: start, End: start.Add(time.Hour)}},
+		}}}, nil)
+
+	h := rest.NewSchedulingHandler(mockScheduling, mockValidator, log)
+	r := routerWithHandler(h, userID.String())
+
+	body := `{"emails":["bob@example.com"],"from":"2025-09-01T00:00:00Z","to":"2025-09-02T00:00:00Z"}`
+	req := httptest.NewRequest("POST", "/freebusy", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"start":"2025-09-01T10:00:00Z"`)
+	assert.NotContains(t, rec.Body.String(), "description")
+}
+
+func TestFreeBusy_ValidationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockValidator := mocks.NewMockValidator(ctrl)
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(errors.New("emails is required"))
+
+	h := rest.NewSchedulingHandler(mocks.NewMockScheduling(ctrl), mockValidator, log)
+	r := routerWithHandler(h, uuid.New().String())
+
+	req := httptest.NewRequest("POST", "/freebusy", bytes.NewBufferString(`{}`))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestSuggestSlots_RangeTooLong(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockScheduling := mocks.NewMockScheduling(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	mockValidator.EXPECT().Validate(gomock.Any()).Return(nil)
+	mockScheduling.EXPECT().
+		SuggestSlots(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.SuggestSlotsResponse{}, domain.ErrRangeTooLong)
+
+	h := rest.NewSchedulingHandler(mockScheduling, mockValidator, log)
+	r := routerWithHandler(h, uuid.New().String())
+
+	body := `{"emails":["bob@example.com"],"from":"2025-01-01T00:00:00Z","to":"2025-12-01T00:00:00Z","duration_minutes":30}`
+	req := httptest.NewRequest("POST", "/scheduling/suggest", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func routerWithHandler(h *rest.SchedulingHandler, userID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+	})
+	r.POST("/freebusy", h.FreeBusy)
+	r.POST("/scheduling/suggest", h.SuggestSlots)
+
+	return r
+}
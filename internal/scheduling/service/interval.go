@@ -0,0 +1,171 @@
+package service
+
+import (
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"sort"
+	"time"
+)
+
+// interval is a half-open time range [start, end).
+type interval struct {
+	start time.Time
+	end   time.Time
+}
+
+func (i interval) clip(from time.Time, to time.Time) (interval, bool) {
+	if i.start.Before(from) {
+		i.start = from
+	}
+	if i.end.After(to) {
+		i.end = to
+	}
+	return i, i.start.Before(i.end)
+}
+
+// eventInterval returns the time a busy event takes. All-day events take the
+// whole day in loc.
+func eventInterval(event domain.BusyEvent, loc *time.Location) interval {
+	if event.StartsAt != nil && event.EndsAt != nil {
+		return interval{start: *event.StartsAt, end: *event.EndsAt}
+	}
+
+	year, month, day := event.Date.Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	return interval{start: start, end: start.AddDate(0, 0, 1)}
+}
+
+// mergeIntervals sorts the intervals and joins the ones that overlap or touch.
+func mergeIntervals(intervals []interval) []interval {
+	if len(intervals) == 0 {
+		return nil
+	}
+
+	sorted := make([]interval, len(intervals))
+	copy(sorted, intervals)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].start.Before(sorted[j].start)
+	})
+
+	merged := []interval{sorted[0]}
+	for _, i := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if i.start.After(last.end) {
+			merged = append(merged, i)
+			continue
+		}
+		if i.end.After(last.end) {
+			last.end = i.end
+		}
+	}
+
+	return merged
+}
+
+type workday struct {
+	start time.Duration
+	end   time.Duration
+}
+
+func parseWorkday(start string, end string) (workday, error) {
+	s, err := time.Parse("15:04", start)
+	if err != nil {
+		return workday{}, domain.ErrInvalidWorkday
+	}
+	e, err := time.Parse("15:04", end)
+	if err != nil {
+		return workday{}, domain.ErrInvalidWorkday
+	}
+
+	w := workday{
+		start: time.Duration(s.Hour())*time.Hour + time.Duration(s.Minute())*time.Minute,
+		end:   time.Duration(e.Hour())*time.Hour + time.Duration(e.Minute())*time.Minute,
+	}
+	if w.start >= w.end {
+		return workday{}, domain.ErrInvalidWorkday
+	}
+
+	return w, nil
+}
+
+type slotQuery struct {
+	from            time.Time
+	to              time.Time
+	duration        time.Duration
+	count           int
+	workday         workday
+	includeWeekends bool
+	loc             *time.Location
+}
+
+// findSlots walks the working hours of every day in the query range and
+// returns the earliest non-overlapping free slots, starting on slotStep
+// boundaries. busy must be merged.
+func findSlots(busy []interval, q slotQuery) []interval {
+	var slots []interval
+
+	from := q.from.In(q.loc)
+	year, month, day := from.Date()
+	for d := time.Date(year, month, day, 0, 0, 0, 0, q.loc); d.Before(q.to) && len(slots) < q.count; d = d.AddDate(0, 0, 1) {
+		if !q.includeWeekends && (d.Weekday() == time.Saturday || d.Weekday() == time.Sunday) {
+			continue
+		}
+
+		window, ok := interval{
+			start: atTimeOfDay(d, q.workday.start),
+			end:   atTimeOfDay(d, q.workday.end),
+		}.clip(q.from, q.to)
+		if !ok {
+			continue
+		}
+
+		start := ceilToStep(window.start)
+		for len(slots) < q.count {
+			candidate := interval{start: start, end: start.Add(q.duration)}
+			if candidate.end.After(window.end) {
+				break
+			}
+
+			if conflict, found := firstOverlap(busy, candidate); found {
+				start = ceilToStep(conflict.end)
+				continue
+			}
+
+			slots = append(slots, candidate)
+			start = candidate.end
+		}
+	}
+
+	return slots
+}
+
+// atTimeOfDay returns the wall clock time offset from midnight of day, which
+// stays correct across DST changes.
+func atTimeOfDay(day time.Time, offset time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), int(offset.Hours()), int(offset.Minutes())%60, 0, 0, day.Location())
+}
+
+func ceilToStep(t time.Time) time.Time {
+	ceiled := t.Truncate(slotStep)
+	if ceiled.Before(t) {
+		ceiled = ceiled.Add(slotStep)
+	}
+	return ceiled
+}
+
+func firstOverlap(busy []interval, candidate interval) (interval, bool) {
+	for _, b := range busy {
+		if b.start.Before(candidate.end) && candidate.start.Before(b.end) {
+			return b, true
+		}
+	}
+	return interval{}, false
+}
+
+func intervalsToDTO(intervals []interval, loc *time.Location) []dto.BusyInterval {
+	result := make([]dto.BusyInterval, 0, len(intervals))
+	for _, i := range intervals {
+		result = append(result, dto.BusyInterval{Start: i.start.In(loc), End: i.end.In(loc)})
+	}
+	return result
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	maxRange            = 62 * 24 * time.Hour
+	defaultSlotCount    = 3
+	defaultWorkdayStart = "09:00"
+	defaultWorkdayEnd   = "17:00"
+	slotStep            = 15 * time.Minute
+)
+
+//go:generate mockgen -source=scheduling.go -destination=../mocks/service_mocks.go -package=mocks
+type EventRepo interface {
+	GetBusyEvents(ctx context.Context, requesterID uuid.UUID, emails []string, from time.Time, to time.Time) ([]domain.BusyEvent, error)
+	GetVisibleEmails(ctx context.Context, requesterID uuid.UUID, emails []string) ([]string, error)
+}
+
+type UserRepo interface {
+	GetUserByID(ctx context.Context, userID uuid.UUID) (domain.User, error)
+}
+
+// Scheduling answers when people are busy and when they can meet. It never
+// exposes anything about an event except the time it takes.
+type Scheduling struct {
+	eventRepo EventRepo
+	userRepo  UserRepo
+}
+
+func NewScheduling(eventRepo EventRepo, userRepo UserRepo) *Scheduling {
+	return &Scheduling{eventRepo: eventRepo, userRepo: userRepo}
+}
+
+// FreeBusy returns the merged busy intervals of every requested email
+// between req.From and req.To. Users without visible calendars, including
+// unknown emails, are marked unknown rather than free.
+func (s *Scheduling) FreeBusy(ctx context.Context, req dto.FreeBusyRequest, userID uuid.UUID) (dto.FreeBusyResponse, error) {
+	const op = "service.scheduling.FreeBusy"
+
+	if req.To.Sub(req.From) > maxRange {
+		return dto.FreeBusyResponse{}, errutils.Wrap(op, domain.ErrRangeTooLong)
+	}
+
+	loc, err := location(req.TimeZone)
+	if err != nil {
+		return dto.FreeBusyResponse{}, errutils.Wrap(op, err)
+	}
+
+	emails := normalizeEmails(req.Emails)
+
+	unknown, err := s.unknownEmails(ctx, userID, emails)
+	if err != nil {
+		return dto.FreeBusyResponse{}, errutils.Wrap(op, err)
+	}
+
+	busy, err := s.busyByEmail(ctx, userID, emails, req.From, req.To, loc)
+	if err != nil {
+		return dto.FreeBusyResponse{}, errutils.Wrap(op, err)
+	}
+
+	resp := dto.FreeBusyResponse{Users: make([]dto.UserFreeBusy, 0, len(emails))}
+	for _, email := range emails {
+		resp.Users = append(resp.Users, dto.UserFreeBusy{
+			Email:   email,
+			Busy:    intervalsToDTO(busy[email], loc),
+			Unknown: slices.Contains(unknown, email),
+		})
+	}
+
+	return resp, nil
+}
+
+// SuggestSlots proposes up to req.Count slots of the requested duration
+// within working hours when the requester and every email are free. Nothing
+// is proposed while some emails are unknown, as their time cannot be seen.
+func (s *Scheduling) SuggestSlots(ctx context.Context, req dto.SuggestSlotsRequest, userID uuid.UUID) (dto.SuggestSlotsResponse, error) {
+	const op = "service.scheduling.SuggestSlots"
+
+	if req.To.Sub(req.From) > maxRange {
+		return dto.SuggestSlotsResponse{}, errutils.Wrap(op, domain.ErrRangeTooLong)
+	}
+
+	loc, err := location(req.TimeZone)
+	if err != nil {
+		return dto.SuggestSlotsResponse{}, errutils.Wrap(op, err)
+	}
+
+	hours, err := parseWorkday(valueOr(req.WorkdayStart, defaultWorkdayStart), valueOr(req.WorkdayEnd, defaultWorkdayEnd))
+	if err != nil {
+		return dto.SuggestSlotsResponse{}, errutils.Wrap(op, err)
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return dto.SuggestSlotsResponse{}, errutils.Wrap(op, err)
+	}
+
+	emails := normalizeEmails(append([]string{user.Email}, req.Emails...))
+
+	unknown, err := s.unknownEmails(ctx, userID, emails)
+	if err != nil {
+		return dto.SuggestSlotsResponse{}, errutils.Wrap(op, err)
+	}
+	if len(unknown) > 0 {
+		return dto.SuggestSlotsResponse{Slots: []dto.BusyInterval{}, Unknown: unknown}, nil
+	}
+
+	busy, err := s.busyByEmail(ctx, userID, emails, req.From, req.To, loc)
+	if err != nil {
+		return dto.SuggestSlotsResponse{}, errutils.Wrap(op, err)
+	}
+
+	var all []interval
+	for _, intervals := range busy {
+		all = append(all, intervals...)
+	}
+
+	count := req.Count
+	if count == 0 {
+		count = defaultSlotCount
+	}
+
+	slots := findSlots(mergeIntervals(all), slotQuery{
+		from:            req.From,
+		to:              req.To,
+		duration:        time.Duration(req.DurationMinutes) * time.Minute,
+		count:           count,
+		workday:         hours,
+		includeWeekends: req.IncludeWeekends,
+		loc:             loc,
+	})
+
+	return dto.SuggestSlotsResponse{Slots: intervalsToDTO(slots, loc)}, nil
+}
+
+// unknownEmails returns the emails, in their given order, whose calendars
+// the requester cannot see at all.
+func (s *Scheduling) unknownEmails(ctx context.Context, userID uuid.UUID, emails []string) ([]string, error) {
+	visible, err := s.eventRepo.GetVisibleEmails(ctx, userID, emails)
+	if err != nil {
+		return nil, err
+	}
+
+	var unknown []string
+	for _, email := range emails {
+		if !slices.Contains(visible, email) {
+			unknown = append(unknown, email)
+		}
+	}
+
+	return unknown, nil
+}
+
+// busyByEmail loads the busy events of the emails and turns them into merged
+// intervals clipped to [from, to).
+func (s *Scheduling) busyByEmail(ctx context.Context, userID uuid.UUID, emails []string, from time.Time, to time.Time, loc *time.Location) (map[string][]interval, error) {
+	events, err := s.eventRepo.GetBusyEvents(ctx, userID, emails, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	byEmail := make(map[string][]interval, len(emails))
+	for _, event := range events {
+		i, ok := eventInterval(event, loc).clip(from, to)
+		if !ok {
+			continue
+		}
+		byEmail[event.Email] = append(byEmail[event.Email], i)
+	}
+
+	for email, intervals := range byEmail {
+		byEmail[email] = mergeIntervals(intervals)
+	}
+
+	return byEmail, nil
+}
+
+func normalizeEmails(emails []string) []string {
+	normalized := make([]string, 0, len(emails))
+	seen := make(map[string]struct{}, len(emails))
+	for _, email := range emails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if _, ok := seen[email]; ok {
+			continue
+		}
+		seen[email] = struct{}{}
+		normalized = append(normalized, email)
+	}
+
+	return normalized
+}
+
+func location(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(name)
+}
+
+func valueOr(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,264 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ilam072/event-calendar/internal/scheduling/mocks"
+	"github.com/ilam072/event-calendar/internal/scheduling/service"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+)
+
+func at(hour, minute int) time.Time {
+	return time.Date(2025, time.September, 1, hour, minute, 0, 0, time.UTC) // Monday
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestFreeBusy_MergesAndClips(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvents := mocks.NewMockEventRepo(ctrl)
+	svc := service.NewScheduling(mockEvents, mocks.NewMockUserRepo(ctrl))
+
+	userID := uuid.New()
+	from, to := at(8, 0), at(18, 0)
+
+	mockEvents.
+		EXPECT().
+		GetVisibleEmails(gomock.Any(), userID, []string{"bob@example.com", "eve@example.com"}).
+		Return([]string{"bob@example.com", "eve@example.com"}, nil)
+	mockEvents.
+		EXPECT().
+		GetBusyEvents(gomock.Any(), userID, []string{"bob@example.com", "eve@example.com"}, from, to).
+		Return([]domain.BusyEvent{
+			{Email: "bob@example.com", StartsAt: ptr(at(7, 0)), EndsAt: ptr(at(9, 0))},
+			{Email: "bob@example.com", StartsAt: ptr(at(10, 0)), EndsAt: ptr(at(11, 0))},
+			{Email: "bob@example.com", StartsAt: ptr(at(10, 30)), EndsAt: ptr(at(12, 0))},
+		}, nil)
+
+	resp, err := svc.FreeBusy(context.Background(), dto.FreeBusyRequest{
+		Emails: []string{"Bob@example.com", "eve@example.com"},
+		From:   from,
+		To:     to,
+	}, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Users) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(resp.Users))
+	}
+
+	bob := resp.Users[0].Busy
+	if len(bob) != 2 ||
+		!bob[0].Start.Equal(at(8, 0)) || !bob[0].End.Equal(at(9, 0)) ||
+		!bob[1].Start.Equal(at(10, 0)) || !bob[1].End.Equal(at(12, 0)) {
+		t.Fatalf("unexpected busy intervals: %+v", bob)
+	}
+
+	if len(resp.Users[1].Busy) != 0 {
+		t.Fatalf("expected eve to be free, got %+v", resp.Users[1].Busy)
+	}
+}
+
+func TestFreeBusy_UnknownUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvents := mocks.NewMockEventRepo(ctrl)
+	svc := service.NewScheduling(mockEvents, mocks.NewMockUserRepo(ctrl))
+
+	userID := uuid.New()
+	from, to := at(8, 0), at(18, 0)
+
+	mockEvents.
+		EXPECT().
+		GetVisibleEmails(gomock.Any(), userID, []string{"bob@example.com", "ghost@example.com"}).
+		Return([]string{"bob@example.com"}, nil)
+	mockEvents.
+		EXPECT().
+		GetBusyEvents(gomock.Any(), userID, gomock.Any(), from, to).
+		Return(nil, nil)
+
+	resp, err := svc.FreeBusy(context.Background(), dto.FreeBusyRequest{
+		Emails: []string{"bob@example.com", "ghost@example.com"},
+		From:   from,
+		To:     to,
+	}, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Users) != 2 || resp.Users[0].Unknown || !resp.Users[1].Unknown {
+		t.Fatalf("expected only ghost to be unknown, got %+v", resp.Users)
+	}
+}
+
+func TestFreeBusy_RangeTooLong(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := service.NewScheduling(mocks.NewMockEventRepo(ctrl), mocks.NewMockUserRepo(ctrl))
+
+	_, err := svc.FreeBusy(context.Background(), dto.FreeBusyRequest{
+		Emails: []string{"bob@example.com"},
+		From:   at(0, 0),
+		To:     at(0, 0).AddDate(0, 3, 0),
+	}, uuid.New())
+	if !errors.Is(err, domain.ErrRangeTooLong) {
+		t.Fatalf("expected ErrRangeTooLong, got %v", err)
+	}
+}
+
+func TestSuggestSlots(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvents := mocks.NewMockEventRepo(ctrl)
+	mockUsers := mocks.NewMockUserRepo(ctrl)
+	svc := service.NewScheduling(mockEvents, mockUsers)
+
+	userID := uuid.New()
+	from := at(0, 0)
+	to := from.AddDate(0, 0, 7)
+
+	mockUsers.
+		EXPECT().
+		GetUserByID(gomock.Any(), userID).
+		Return(domain.User{ID: userID, Email: "me@example.com"}, nil)
+	mockEvents.
+		EXPECT().
+		GetVisibleEmails(gomock.Any(), userID, []string{"me@example.com", "bob@example.com"}).
+		Return([]string{"me@example.com", "bob@example.com"}, nil)
+	mockEvents.
+		EXPECT().
+		GetBusyEvents(gomock.Any(), userID, []string{"me@example.com", "bob@example.com"}, from, to).
+		Return([]domain.BusyEvent{
+			{Email: "me@example.com", StartsAt: ptr(at(9, 0)), EndsAt: ptr(at(10, 10))},
+			{Email: "bob@example.com", StartsAt: ptr(at(11, 0)), EndsAt: ptr(at(16, 30))},
+		}, nil)
+
+	resp, err := svc.SuggestSlots(context.Background(), dto.SuggestSlotsRequest{
+		Emails:          []string{"bob@example.com"},
+		From:            from,
+		To:              to,
+		DurationMinutes: 30,
+		Count:           3,
+	}, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []time.Time{at(10, 15), at(16, 30), at(9, 0).AddDate(0, 0, 1)}
+	if len(resp.Slots) != len(want) {
+		t.Fatalf("expected %d slots, got %+v", len(want), resp.Slots)
+	}
+	for i, slot := range resp.Slots {
+		if !slot.Start.Equal(want[i]) || slot.End.Sub(slot.Start) != 30*time.Minute {
+			t.Fatalf("unexpected slot %d: %+v", i, slot)
+		}
+	}
+}
+
+func TestSuggestSlots_SkipsWeekendsAndAllDayEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvents := mocks.NewMockEventRepo(ctrl)
+	mockUsers := mocks.NewMockUserRepo(ctrl)
+	svc := service.NewScheduling(mockEvents, mockUsers)
+
+	saturday := time.Date(2025, time.August, 30, 0, 0, 0, 0, time.UTC)
+
+	mockUsers.
+		EXPECT().
+		GetUserByID(gomock.Any(), gomock.Any()).
+		Return(domain.User{Email: "me@example.com"}, nil)
+	mockEvents.
+		EXPECT().
+		GetVisibleEmails(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]string{"me@example.com", "bob@example.com"}, nil)
+	mockEvents.
+		EXPECT().
+		GetBusyEvents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]domain.BusyEvent{
+			{Email: "bob@example.com", Date: at(0, 0)},
+		}, nil)
+
+	resp, err := svc.SuggestSlots(context.Background(), dto.SuggestSlotsRequest{
+		Emails:          []string{"bob@example.com"},
+		From:            saturday,
+		To:              saturday.AddDate(0, 0, 7),
+		DurationMinutes: 60,
+		Count:           1,
+	}, uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tuesday := time.Date(2025, time.September, 2, 9, 0, 0, 0, time.UTC)
+	if len(resp.Slots) != 1 || !resp.Slots[0].Start.Equal(tuesday) {
+		t.Fatalf("expected slot on tuesday 09:00, got %+v", resp.Slots)
+	}
+}
+
+func TestSuggestSlots_UnknownUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvents := mocks.NewMockEventRepo(ctrl)
+	mockUsers := mocks.NewMockUserRepo(ctrl)
+	svc := service.NewScheduling(mockEvents, mockUsers)
+
+	mockUsers.
+		EXPECT().
+		GetUserByID(gomock.Any(), gomock.Any()).
+		Return(domain.User{Email: "me@example.com"}, nil)
+	mockEvents.
+		EXPECT().
+		GetVisibleEmails(gomock.Any(), gomock.Any(), []string{"me@example.com", "bob@example.com", "ghost@example.com"}).
+		Return([]string{"me@example.com", "bob@example.com"}, nil)
+
+	resp, err := svc.SuggestSlots(context.Background(), dto.SuggestSlotsRequest{
+		Emails:          []string{"bob@example.com", "ghost@example.com"},
+		From:            at(0, 0),
+		To:              at(0, 0).AddDate(0, 0, 7),
+		DurationMinutes: 30,
+	}, uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Slots) != 0 || len(resp.Unknown) != 1 || resp.Unknown[0] != "ghost@example.com" {
+		t.Fatalf("expected no slots and ghost unknown, got %+v", resp)
+	}
+}
+
+func TestSuggestSlots_InvalidWorkday(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := service.NewScheduling(mocks.NewMockEventRepo(ctrl), mocks.NewMockUserRepo(ctrl))
+
+	_, err := svc.SuggestSlots(context.Background(), dto.SuggestSlotsRequest{
+		Emails:          []string{"bob@example.com"},
+		From:            at(0, 0),
+		To:              at(0, 0).AddDate(0, 0, 1),
+		DurationMinutes: 30,
+		WorkdayStart:    "18:00",
+		WorkdayEnd:      "09:00",
+	}, uuid.New())
+	if !errors.Is(err, domain.ErrInvalidWorkday) {
+		t.Fatalf("expected ErrInvalidWorkday, got %v", err)
+	}
+}
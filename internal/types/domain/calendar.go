@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	CalendarVisibilityPrivate = "private"
+	CalendarVisibilityPublic  = "public"
+
+	CalendarRoleOwner   = "owner"
+	CalendarRoleManager = "manager"
+	CalendarRoleEditor  = "editor"
+	CalendarRoleViewer  = "viewer"
+
+	DefaultCalendarName  = "Personal"
+	DefaultCalendarColor = "#1e88e5"
+)
+
+type Calendar struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Name        string
+	Color       string
+	Description string
+	IsDefault   bool
+	Visibility  string
+	Role        string // access of the requesting user
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// CalendarShare grants a user access to someone else's calendar. UserID is
+// nil while the invitation is pending for an email without an account.
+type CalendarShare struct {
+	ID         uuid.UUID
+	CalendarID uuid.UUID
+	UserID     *uuid.UUID
+	Email      string
+	Role       string
+	CreatedBy  *uuid.UUID
+	CreatedAt  time.Time
+}
+
+// CanWriteEvents reports whether the role may create, change and delete
+// events in the calendar.
+func CanWriteEvents(role string) bool {
+	return role == CalendarRoleOwner || role == CalendarRoleManager || role == CalendarRoleEditor
+}
+
+// CanManageShares reports whether the role may grant and revoke access.
+func CanManageShares(role string) bool {
+	return role == CalendarRoleOwner || role == CalendarRoleManager
+}
@@ -1,9 +1,54 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
-	ErrUserExists         = errors.New("user exists")
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrEventNotFound      = errors.New("event not found")
+	ErrUserExists              = errors.New("user exists")
+	ErrUserNotFound            = errors.New("user not found")
+	ErrInvalidCredentials      = errors.New("invalid credentials")
+	ErrInvalidMFACode          = errors.New("invalid mfa code")
+	ErrEmailNotVerified        = errors.New("email not verified")
+	ErrUnverifiedAccountExists = errors.New("unverified account with this email exists")
+	ErrInvalidVerificationLink = errors.New("invalid email verification link")
+	ErrUserDisabled            = errors.New("account disabled")
+	ErrTooManyAttempts         = errors.New("too many sign-in attempts")
+	ErrAccountLocked           = errors.New("account temporarily locked")
+	ErrTOTPEnabled             = errors.New("totp already enabled")
+	ErrTOTPNotEnrolled         = errors.New("totp not enrolled")
+	ErrEventNotFound           = errors.New("event not found")
+	ErrEventConflict           = errors.New("event overlaps existing events")
+	ErrVersionMismatch         = errors.New("event has been modified")
+	ErrInvalidTimeRange        = errors.New("event must end after it starts")
+	ErrBatchRolledBack         = errors.New("batch rolled back after another operation failed")
+	ErrRevisionNotFound        = errors.New("revision not found")
+	ErrAttendeeNotFound        = errors.New("attendee not found")
+	ErrInvalidRSVPLink         = errors.New("invalid rsvp link")
+	ErrCalendarNotFound        = errors.New("calendar not found")
+	ErrDefaultCalendar         = errors.New("default calendar cannot be deleted")
+	ErrShareNotFound           = errors.New("calendar share not found")
+	ErrShareWithOwner          = errors.New("calendar cannot be shared with its owner")
+	ErrForbidden               = errors.New("insufficient permissions")
+	ErrRangeTooLong            = errors.New("time range is too long")
+	ErrInvalidWorkday          = errors.New("workday must start before it ends")
+	ErrAPIKeyNotFound          = errors.New("api key not found")
+	ErrInvalidAPIKey           = errors.New("invalid api key")
+	ErrInvalidExpiration       = errors.New("expiration must be in the future")
 )
+
+// LoginBlockedError reports how long sign-in is blocked. It wraps either
+// ErrTooManyAttempts or ErrAccountLocked.
+type LoginBlockedError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *LoginBlockedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LoginBlockedError) Unwrap() error {
+	return e.Err
+}
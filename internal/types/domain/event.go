@@ -5,13 +5,51 @@ import (
 	"time"
 )
 
+// Event is an all-day event on Date unless StartsAt and EndsAt are set.
 type Event struct {
 	ID          uuid.UUID
 	UserID      uuid.UUID
+	CalendarID  uuid.UUID
 	Date        time.Time
+	StartsAt    *time.Time
+	EndsAt      *time.Time
 	Description string
 	Sent        bool
 	RemindAt    *time.Time
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	Version     int
+	DeletedAt   *time.Time
+}
+
+const (
+	AttendeeStatusNeedsAction = "needs_action"
+	AttendeeStatusAccepted    = "accepted"
+	AttendeeStatusDeclined    = "declined"
+	AttendeeStatusTentative   = "tentative"
+)
+
+// Attendee is a person invited to an event, either a registered user or an
+// external email. UserID is set once the email belongs to an account.
+type Attendee struct {
+	ID          uuid.UUID
+	EventID     uuid.UUID
+	UserID      *uuid.UUID
+	Email       string
+	Status      string
+	RespondedAt *time.Time
+	CreatedAt   time.Time
+}
+
+type ArchivedEvent struct {
+	ID                uuid.UUID
+	UserID            uuid.UUID
+	CalendarID        *uuid.UUID
+	Date              time.Time
+	StartsAt          *time.Time
+	EndsAt            *time.Time
+	Description       string
+	ArchivedAt        time.Time
+	OriginalCreatedAt *time.Time
+	OriginalUpdatedAt *time.Time
 }
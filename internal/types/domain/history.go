@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"reflect"
+	"time"
+)
+
+const (
+	HistoryCreate  = "create"
+	HistoryUpdate  = "update"
+	HistoryDelete  = "delete"
+	HistoryRestore = "restore"
+	HistoryRevert  = "revert"
+	HistoryArchive = "archive"
+	HistoryPurge   = "purge"
+)
+
+// EventSnapshot holds the fields of an event that users can change, and when
+// it was moved to the trash. It is stored as JSON and also built in SQL, so
+// the JSON names must not change.
+type EventSnapshot struct {
+	CalendarID  uuid.UUID  `json:"calendar_id"`
+	Date        time.Time  `json:"date"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	Description string     `json:"description"`
+	RemindAt    *time.Time `json:"remind_at"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+}
+
+func NewEventSnapshot(e Event) *EventSnapshot {
+	return &EventSnapshot{
+		CalendarID:  e.CalendarID,
+		Date:        e.Date,
+		StartsAt:    e.StartsAt,
+		EndsAt:      e.EndsAt,
+		Description: e.Description,
+		RemindAt:    e.RemindAt,
+		DeletedAt:   e.DeletedAt,
+	}
+}
+
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// Diff lists the fields that differ between two snapshots by their JSON
+// name. A nil snapshot counts as all fields being null.
+func Diff(before *EventSnapshot, after *EventSnapshot) map[string]FieldChange {
+	from, to := before.fields(), after.fields()
+
+	changes := make(map[string]FieldChange)
+	for name := range from {
+		if !reflect.DeepEqual(from[name], to[name]) {
+			changes[name] = FieldChange{From: from[name], To: to[name]}
+		}
+	}
+	for name := range to {
+		if _, ok := from[name]; !ok && to[name] != nil {
+			changes[name] = FieldChange{From: nil, To: to[name]}
+		}
+	}
+
+	return changes
+}
+
+// fields returns the snapshot as it is stored, so that values compare the
+// same way they are shown.
+func (s *EventSnapshot) fields() map[string]any {
+	if s == nil {
+		return nil
+	}
+
+	data, _ := json.Marshal(s)
+
+	var fields map[string]any
+	_ = json.Unmarshal(data, &fields)
+
+	return fields
+}
+
+// EventRevision is an entry of the append-only history of an event. Before
+// is unset for creations and After for purges.
+type EventRevision struct {
+	ID        int64
+	EventID   uuid.UUID
+	Action    string
+	ActorID   *uuid.UUID
+	Version   int
+	Before    *EventSnapshot
+	After     *EventSnapshot
+	Changes   map[string]FieldChange
+	RequestID string
+	CreatedAt time.Time
+}
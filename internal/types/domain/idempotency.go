@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// IdempotencyKey is a request made with an Idempotency-Key header. The
+// response is unset while the first request is still being handled.
+type IdempotencyKey struct {
+	UserID       uuid.UUID
+	Key          string
+	RequestHash  string
+	StatusCode   int
+	ResponseBody []byte
+	CreatedAt    time.Time
+}
+
+func (k IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
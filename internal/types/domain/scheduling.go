@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// BusyEvent is an event that makes the user with Email busy. It carries no
+// details beyond the time it takes.
+type BusyEvent struct {
+	Email    string
+	Date     time.Time
+	StartsAt *time.Time
+	EndsAt   *time.Time
+}
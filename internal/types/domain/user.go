@@ -9,6 +9,28 @@ type User struct {
 	ID           uuid.UUID
 	Email        string
 	PasswordHash string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	TOTPSecret   string
+	TOTPEnabled  bool
+	DisabledAt   *time.Time
+	// EmailVerifiedAt is nil until the user proves they own Email.
+	EmailVerifiedAt *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// UserIdentity links an account of an OpenID Connect provider to a user.
+type UserIdentity struct {
+	Issuer    string
+	Subject   string
+	UserID    uuid.UUID
+	CreatedAt time.Time
+}
+
+// LoginThrottle tracks recent failed sign-in attempts for one key: an account
+// email, a client IP or the second factor of a user.
+type LoginThrottle struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
 }
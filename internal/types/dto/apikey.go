@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,min=1,max=100"`
+	Scope     string     `json:"scope" validate:"required,oneof=read read_write"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type APIKey struct {
+	ID         uuid.UUID  `json:"api_key_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scope      string     `json:"scope"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAPIKeyResponse is the only place the plain key is ever returned.
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+type GetAPIKeysResponse struct {
+	APIKeys []APIKey `json:"api_keys"`
+}
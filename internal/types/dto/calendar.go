@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type CreateCalendarRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=100"`
+	Color       string `json:"color" validate:"omitempty,hexcolor,len=7"`
+	Description string `json:"description" validate:"max=500"`
+	Visibility  string `json:"visibility" validate:"omitempty,oneof=private public"`
+}
+
+type UpdateCalendarRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=100"`
+	Color       string `json:"color" validate:"required,hexcolor,len=7"`
+	Description string `json:"description" validate:"max=500"`
+	Visibility  string `json:"visibility" validate:"required,oneof=private public"`
+	IsDefault   bool   `json:"is_default"`
+}
+
+type Calendar struct {
+	ID          uuid.UUID `json:"calendar_id"`
+	Name        string    `json:"name"`
+	Color       string    `json:"color"`
+	Description string    `json:"description"`
+	IsDefault   bool      `json:"is_default"`
+	Visibility  string    `json:"visibility"`
+	Role        string    `json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type GetCalendarsResponse struct {
+	Calendars []Calendar `json:"calendars"`
+}
+
+type ShareCalendarRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=viewer editor manager"`
+}
+
+type CalendarShare struct {
+	ID        uuid.UUID  `json:"share_id"`
+	UserID    *uuid.UUID `json:"user_id"`
+	Email     string     `json:"email"`
+	Role      string     `json:"role"`
+	Pending   bool       `json:"pending"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type GetCalendarSharesResponse struct {
+	Shares []CalendarShare `json:"shares"`
+}
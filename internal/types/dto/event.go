@@ -5,23 +5,169 @@ import (
 	"time"
 )
 
+// CreateEventRequest describes an all-day event on Date, or a timed event
+// from StartsAt to EndsAt, in which case Date may be omitted.
 type CreateEventRequest struct {
-	Date        time.Time  `json:"date" validate:"required"`
+	CalendarID  *uuid.UUID `json:"calendar_id,omitempty"`
+	Date        time.Time  `json:"date" validate:"required_without=StartsAt"`
+	StartsAt    *time.Time `json:"starts_at,omitempty" validate:"required_with=EndsAt"`
+	EndsAt      *time.Time `json:"ends_at,omitempty" validate:"required_with=StartsAt,omitempty,gtfield=StartsAt"`
 	Description string     `json:"description" validate:"required,min=1,max=500"`
 	RemindAt    *time.Time `json:"remind_at,omitempty"`
 }
 
 type UpdateEventRequest struct {
-	Date        time.Time  `json:"date" validate:"required"`
+	CalendarID  *uuid.UUID `json:"calendar_id,omitempty"`
+	Date        time.Time  `json:"date" validate:"required_without=StartsAt"`
+	StartsAt    *time.Time `json:"starts_at,omitempty" validate:"required_with=EndsAt"`
+	EndsAt      *time.Time `json:"ends_at,omitempty" validate:"required_with=StartsAt,omitempty,gtfield=StartsAt"`
 	Description string     `json:"description" validate:"required,min=1,max=500"`
 	RemindAt    *time.Time `json:"remind_at" validate:"required"`
 }
 
+// PatchEventRequest is a JSON Merge Patch (RFC 7396) of an event. Absent
+// members are left unchanged. A null remind_at removes the reminder and null
+// starts_at and ends_at turn the event into an all-day one. The other members
+// cannot be removed, so null is treated as absent.
+type PatchEventRequest struct {
+	CalendarID  *uuid.UUID          `json:"calendar_id"`
+	Date        *time.Time          `json:"date"`
+	StartsAt    Nullable[time.Time] `json:"starts_at"`
+	EndsAt      Nullable[time.Time] `json:"ends_at"`
+	Description *string             `json:"description" validate:"omitempty,min=1,max=500"`
+	RemindAt    Nullable[time.Time] `json:"remind_at"`
+}
+
 type Event struct {
-	ID          uuid.UUID `json:"event_id"`
-	UserID      uuid.UUID `json:"user_id"`
-	Date        time.Time `json:"date"`
-	Description string    `json:"description"`
+	ID          uuid.UUID  `json:"event_id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	CalendarID  uuid.UUID  `json:"calendar_id"`
+	Date        time.Time  `json:"date"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+	Description string     `json:"description"`
+	Version     int        `json:"version"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	Attendees   []Attendee `json:"attendees,omitempty"`
+}
+
+// CreateEventResponse lists the events the new one overlaps, if any.
+type CreateEventResponse struct {
+	ID        uuid.UUID `json:"event_id"`
+	Conflicts []Event   `json:"conflicts,omitempty"`
+}
+
+type UpdateEventResponse struct {
+	Version   int     `json:"version"`
+	Conflicts []Event `json:"conflicts,omitempty"`
+}
+
+const (
+	BatchAtomic     = "atomic"
+	BatchBestEffort = "best_effort"
+
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// BatchRequest runs up to 100 operations. In atomic mode, the default, they
+// are applied in one transaction and stop at the first failure. In
+// best_effort mode each one is applied on its own.
+type BatchRequest struct {
+	Mode       string           `json:"mode" validate:"omitempty,oneof=atomic best_effort"`
+	Strict     bool             `json:"strict"`
+	Operations []BatchOperation `json:"operations" validate:"required,min=1,max=100,dive"`
+}
+
+// BatchOperation carries the body of a create or an update. Version plays
+// the role of If-Match for updates and deletes.
+type BatchOperation struct {
+	Method  string              `json:"method" validate:"required,oneof=create update delete"`
+	EventID uuid.UUID           `json:"event_id" validate:"required_unless=Method create"`
+	Version int                 `json:"version" validate:"min=0"`
+	Create  *CreateEventRequest `json:"create" validate:"required_if=Method create"`
+	Update  *UpdateEventRequest `json:"update" validate:"required_if=Method update"`
+}
+
+// BatchResult is the outcome of the operation at Index. Err is turned into
+// Status and Error by the handler.
+type BatchResult struct {
+	Index     int         `json:"index"`
+	Status    int         `json:"status"`
+	EventID   *uuid.UUID  `json:"event_id,omitempty"`
+	Version   int         `json:"version,omitempty"`
+	Conflicts []Event     `json:"conflicts,omitempty"`
+	Error     *BatchError `json:"error,omitempty"`
+	Err       error       `json:"-"`
+}
+
+type BatchError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type BatchResponse struct {
+	Mode    string        `json:"mode"`
+	Results []BatchResult `json:"results"`
+}
+
+// EventSnapshot is the state of an event at some point of its history.
+type EventSnapshot struct {
+	CalendarID  uuid.UUID  `json:"calendar_id"`
+	Date        time.Time  `json:"date"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	Description string     `json:"description"`
+	RemindAt    *time.Time `json:"remind_at"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+}
+
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// EventRevision is an entry of the history of an event. Before is null for
+// creations and After for purges.
+type EventRevision struct {
+	ID        int64                  `json:"revision_id"`
+	Action    string                 `json:"action"`
+	ActorID   *uuid.UUID             `json:"actor_id"`
+	Version   int                    `json:"version"`
+	Before    *EventSnapshot         `json:"before"`
+	After     *EventSnapshot         `json:"after"`
+	Changes   map[string]FieldChange `json:"changes"`
+	RequestID string                 `json:"request_id,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+type GetHistoryResponse struct {
+	Revisions []EventRevision `json:"revisions"`
+}
+
+type EmptyTrashResponse struct {
+	Deleted int64 `json:"deleted"`
+}
+
+type AddAttendeesRequest struct {
+	Emails []string `json:"emails" validate:"required,min=1,max=100,dive,email"`
+}
+
+type RSVPRequest struct {
+	Status string `json:"status" validate:"required,oneof=accepted declined tentative"`
+}
+
+type Attendee struct {
+	ID          uuid.UUID  `json:"attendee_id"`
+	UserID      *uuid.UUID `json:"user_id"`
+	Email       string     `json:"email"`
+	Status      string     `json:"status"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+}
+
+type GetAttendeesResponse struct {
+	Attendees []Attendee `json:"attendees"`
 }
 
 type GetEventsResponse struct {
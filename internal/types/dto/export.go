@@ -0,0 +1,124 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type ExportUser struct {
+	ID        uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ExportEvent struct {
+	ID          uuid.UUID  `json:"event_id"`
+	CalendarID  uuid.UUID  `json:"calendar_id"`
+	Date        time.Time  `json:"date"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+	Description string     `json:"description"`
+	RemindAt    *time.Time `json:"remind_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+type ExportArchivedEvent struct {
+	ID                uuid.UUID  `json:"event_id"`
+	CalendarID        *uuid.UUID `json:"calendar_id"`
+	Date              time.Time  `json:"date"`
+	StartsAt          *time.Time `json:"starts_at,omitempty"`
+	EndsAt            *time.Time `json:"ends_at,omitempty"`
+	Description       string     `json:"description"`
+	ArchivedAt        time.Time  `json:"archived_at"`
+	OriginalCreatedAt *time.Time `json:"original_created_at"`
+	OriginalUpdatedAt *time.Time `json:"original_updated_at"`
+}
+
+type ExportReminder struct {
+	EventID  uuid.UUID `json:"event_id"`
+	RemindAt time.Time `json:"remind_at"`
+	Sent     bool      `json:"sent"`
+}
+
+// ExportSession is the sign-in state kept for the account: recent failed
+// attempts of the password ("password") or of the second factor ("mfa") and
+// the lockout they caused. Access tokens are stateless JWTs and are never
+// persisted.
+type ExportSession struct {
+	Kind          string     `json:"kind"`
+	Failures      int        `json:"failures"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until"`
+}
+
+// ExportAPIKey leaves out the secret hash.
+type ExportAPIKey struct {
+	ID         uuid.UUID  `json:"api_key_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scope      string     `json:"scope"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type ExportIdentity struct {
+	Issuer    string    `json:"issuer"`
+	Subject   string    `json:"subject"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExportCalendar struct {
+	ID          uuid.UUID `json:"calendar_id"`
+	OwnerID     uuid.UUID `json:"owner_id"`
+	Name        string    `json:"name"`
+	Color       string    `json:"color"`
+	Description string    `json:"description"`
+	IsDefault   bool      `json:"is_default"`
+	Visibility  string    `json:"visibility"`
+	Role        string    `json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ExportShare struct {
+	ID         uuid.UUID  `json:"share_id"`
+	CalendarID uuid.UUID  `json:"calendar_id"`
+	UserID     *uuid.UUID `json:"user_id"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type ExportInvitation struct {
+	ID          uuid.UUID  `json:"attendee_id"`
+	EventID     uuid.UUID  `json:"event_id"`
+	Email       string     `json:"email"`
+	Status      string     `json:"status"`
+	RespondedAt *time.Time `json:"responded_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type ExportRevision struct {
+	EventID uuid.UUID `json:"event_id"`
+	EventRevision
+}
+
+// UserExport is everything stored about a user.
+type UserExport struct {
+	ExportedAt     time.Time             `json:"exported_at"`
+	User           ExportUser            `json:"user"`
+	Sessions       []ExportSession       `json:"sessions"`
+	APIKeys        []ExportAPIKey        `json:"api_keys"`
+	Identities     []ExportIdentity      `json:"identities"`
+	Calendars      []ExportCalendar      `json:"calendars"`
+	Shares         []ExportShare         `json:"shares"`
+	Events         []ExportEvent         `json:"events"`
+	ArchivedEvents []ExportArchivedEvent `json:"archived_events"`
+	Reminders      []ExportReminder      `json:"reminders"`
+	Invitations    []ExportInvitation    `json:"invitations"`
+	History        []ExportRevision      `json:"history"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+const (
+	HealthUp           = "up"
+	HealthDown         = "down"
+	HealthReady        = "ready"
+	HealthNotReady     = "not_ready"
+	HealthShuttingDown = "shutting_down"
+)
+
+type ComponentHealth struct {
+	Status   string `json:"status"`
+	Critical bool   `json:"critical"`
+	Error    string `json:"error,omitempty"`
+}
+
+type HealthResponse struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentHealth `json:"components,omitempty"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Nullable tells apart a JSON member that is absent from one that is
+// explicitly null, as needed by merge patches.
+type Nullable[T any] struct {
+	Value T
+	Set   bool
+	Null  bool
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		n.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &n.Value)
+}
+
+// Ptr returns the value, or nil if the member is null. It must only be
+// called when Set is true.
+func (n Nullable[T]) Ptr() *T {
+	if n.Null {
+		return nil
+	}
+	return &n.Value
+}
@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+type FreeBusyRequest struct {
+	Emails   []string  `json:"emails" validate:"required,min=1,max=50,dive,email"`
+	From     time.Time `json:"from" validate:"required"`
+	To       time.Time `json:"to" validate:"required,gtfield=From"`
+	TimeZone string    `json:"time_zone,omitempty" validate:"omitempty,timezone"`
+}
+
+type BusyInterval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// UserFreeBusy carries the busy intervals of one email. Unknown is set when
+// the email has no account or none of its calendars are visible to the
+// requester; Busy then only lists what the requester happens to see and
+// the rest of the time must not be taken as free.
+type UserFreeBusy struct {
+	Email   string         `json:"email"`
+	Busy    []BusyInterval `json:"busy"`
+	Unknown bool           `json:"unknown"`
+}
+
+type FreeBusyResponse struct {
+	Users []UserFreeBusy `json:"users"`
+}
+
+// SuggestSlotsRequest asks for free slots shared by the requester and the
+// given emails. Working hours are in TimeZone and default to 09:00-17:00.
+type SuggestSlotsRequest struct {
+	Emails          []string  `json:"emails" validate:"required,min=1,max=50,dive,email"`
+	From            time.Time `json:"from" validate:"required"`
+	To              time.Time `json:"to" validate:"required,gtfield=From"`
+	DurationMinutes int       `json:"duration_minutes" validate:"required,min=5,max=480"`
+	Count           int       `json:"count,omitempty" validate:"omitempty,min=1,max=20"`
+	WorkdayStart    string    `json:"workday_start,omitempty" validate:"omitempty,datetime=15:04"`
+	WorkdayEnd      string    `json:"workday_end,omitempty" validate:"omitempty,datetime=15:04"`
+	IncludeWeekends bool      `json:"include_weekends,omitempty"`
+	TimeZone        string    `json:"time_zone,omitempty" validate:"omitempty,timezone"`
+}
+
+// SuggestSlotsResponse lists the proposed slots. When some emails are
+// unknown, as in UserFreeBusy, no slots are proposed and they are listed in
+// Unknown instead.
+type SuggestSlotsResponse struct {
+	Slots   []BusyInterval `json:"slots"`
+	Unknown []string       `json:"unknown,omitempty"`
+}
@@ -0,0 +1,139 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: guard.go
+//
+// Generated by this command:
+//
+//	mockgen -source=guard.go -destination=../mocks/guard_mocks.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/ilam072/event-calendar/internal/types/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLoginThrottleRepo is a mock of LoginThrottleRepo interface.
+type MockLoginThrottleRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginThrottleRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockLoginThrottleRepoMockRecorder is the mock recorder for MockLoginThrottleRepo.
+type MockLoginThrottleRepoMockRecorder struct {
+	mock *MockLoginThrottleRepo
+}
+
+// NewMockLoginThrottleRepo creates a new mock instance.
+func NewMockLoginThrottleRepo(ctrl *gomock.Controller) *MockLoginThrottleRepo {
+	mock := &MockLoginThrottleRepo{ctrl: ctrl}
+	mock.recorder = &MockLoginThrottleRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginThrottleRepo) EXPECT() *MockLoginThrottleRepoMockRecorder {
+	return m.recorder
+}
+
+// GetLoginThrottles mocks base method.
+func (m *MockLoginThrottleRepo) GetLoginThrottles(ctx context.Context, keys []string) ([]domain.LoginThrottle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginThrottles", ctx, keys)
+	ret0, _ := ret[0].([]domain.LoginThrottle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginThrottles indicates an expected call of GetLoginThrottles.
+func (mr *MockLoginThrottleRepoMockRecorder) GetLoginThrottles(ctx, keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginThrottles", reflect.TypeOf((*MockLoginThrottleRepo)(nil).GetLoginThrottles), ctx, keys)
+}
+
+// LockLogin mocks base method.
+func (m *MockLoginThrottleRepo) LockLogin(ctx context.Context, key string, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockLogin", ctx, key, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockLogin indicates an expected call of LockLogin.
+func (mr *MockLoginThrottleRepoMockRecorder) LockLogin(ctx, key, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockLogin", reflect.TypeOf((*MockLoginThrottleRepo)(nil).LockLogin), ctx, key, until)
+}
+
+// RecordLoginFailure mocks base method.
+func (m *MockLoginThrottleRepo) RecordLoginFailure(ctx context.Context, key string, now, resetBefore time.Time) (domain.LoginThrottle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLoginFailure", ctx, key, now, resetBefore)
+	ret0, _ := ret[0].(domain.LoginThrottle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordLoginFailure indicates an expected call of RecordLoginFailure.
+func (mr *MockLoginThrottleRepoMockRecorder) RecordLoginFailure(ctx, key, now, resetBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLoginFailure", reflect.TypeOf((*MockLoginThrottleRepo)(nil).RecordLoginFailure), ctx, key, now, resetBefore)
+}
+
+// ResetLoginFailures mocks base method.
+func (m *MockLoginThrottleRepo) ResetLoginFailures(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetLoginFailures", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetLoginFailures indicates an expected call of ResetLoginFailures.
+func (mr *MockLoginThrottleRepoMockRecorder) ResetLoginFailures(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetLoginFailures", reflect.TypeOf((*MockLoginThrottleRepo)(nil).ResetLoginFailures), ctx, key)
+}
+
+// MockSender is a mock of Sender interface.
+type MockSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockSenderMockRecorder
+	isgomock struct{}
+}
+
+// MockSenderMockRecorder is the mock recorder for MockSender.
+type MockSenderMockRecorder struct {
+	mock *MockSender
+}
+
+// NewMockSender creates a new mock instance.
+func NewMockSender(ctrl *gomock.Controller) *MockSender {
+	mock := &MockSender{ctrl: ctrl}
+	mock.recorder = &MockSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSender) EXPECT() *MockSenderMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockSender) Send(subject, message, to string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", subject, message, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockSenderMockRecorder) Send(subject, message, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockSender)(nil).Send), subject, message, to)
+}
@@ -16,6 +16,7 @@ import (
 
 	uuid "github.com/google/uuid"
 	domain "github.com/ilam072/event-calendar/internal/types/domain"
+	jwt "github.com/ilam072/event-calendar/pkg/jwt"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -58,6 +59,34 @@ func (mr *MockUserRepoMockRecorder) CreateUser(ctx, user any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserRepo)(nil).CreateUser), ctx, user)
 }
 
+// DisableTOTP mocks base method.
+func (m *MockUserRepo) DisableTOTP(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTP", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTOTP indicates an expected call of DisableTOTP.
+func (mr *MockUserRepoMockRecorder) DisableTOTP(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTP", reflect.TypeOf((*MockUserRepo)(nil).DisableTOTP), ctx, userID)
+}
+
+// EnableTOTP mocks base method.
+func (m *MockUserRepo) EnableTOTP(ctx context.Context, userID uuid.UUID, recoveryCodeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTOTP", ctx, userID, recoveryCodeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableTOTP indicates an expected call of EnableTOTP.
+func (mr *MockUserRepoMockRecorder) EnableTOTP(ctx, userID, recoveryCodeHashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTOTP", reflect.TypeOf((*MockUserRepo)(nil).EnableTOTP), ctx, userID, recoveryCodeHashes)
+}
+
 // GetUserByEmail mocks base method.
 func (m *MockUserRepo) GetUserByEmail(ctx context.Context, email string) (domain.User, error) {
 	m.ctrl.T.Helper()
@@ -73,6 +102,120 @@ func (mr *MockUserRepoMockRecorder) GetUserByEmail(ctx, email any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockUserRepo)(nil).GetUserByEmail), ctx, email)
 }
 
+// GetUserByID mocks base method.
+func (m *MockUserRepo) GetUserByID(ctx context.Context, userID uuid.UUID) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, userID)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockUserRepoMockRecorder) GetUserByID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserRepo)(nil).GetUserByID), ctx, userID)
+}
+
+// GetUserByIdentity mocks base method.
+func (m *MockUserRepo) GetUserByIdentity(ctx context.Context, issuer, subject string) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByIdentity", ctx, issuer, subject)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByIdentity indicates an expected call of GetUserByIdentity.
+func (mr *MockUserRepoMockRecorder) GetUserByIdentity(ctx, issuer, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByIdentity", reflect.TypeOf((*MockUserRepo)(nil).GetUserByIdentity), ctx, issuer, subject)
+}
+
+// LinkIdentity mocks base method.
+func (m *MockUserRepo) LinkIdentity(ctx context.Context, userID uuid.UUID, issuer, subject string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkIdentity", ctx, userID, issuer, subject)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LinkIdentity indicates an expected call of LinkIdentity.
+func (mr *MockUserRepoMockRecorder) LinkIdentity(ctx, userID, issuer, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkIdentity", reflect.TypeOf((*MockUserRepo)(nil).LinkIdentity), ctx, userID, issuer, subject)
+}
+
+// MarkEmailVerified mocks base method.
+func (m *MockUserRepo) MarkEmailVerified(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEmailVerified", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEmailVerified indicates an expected call of MarkEmailVerified.
+func (mr *MockUserRepoMockRecorder) MarkEmailVerified(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmailVerified", reflect.TypeOf((*MockUserRepo)(nil).MarkEmailVerified), ctx, userID)
+}
+
+// SetTOTPSecret mocks base method.
+func (m *MockUserRepo) SetTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTOTPSecret", ctx, userID, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTOTPSecret indicates an expected call of SetTOTPSecret.
+func (mr *MockUserRepoMockRecorder) SetTOTPSecret(ctx, userID, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTOTPSecret", reflect.TypeOf((*MockUserRepo)(nil).SetTOTPSecret), ctx, userID, secret)
+}
+
+// UseMFAToken mocks base method.
+func (m *MockUserRepo) UseMFAToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseMFAToken", ctx, jti, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseMFAToken indicates an expected call of UseMFAToken.
+func (mr *MockUserRepoMockRecorder) UseMFAToken(ctx, jti, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseMFAToken", reflect.TypeOf((*MockUserRepo)(nil).UseMFAToken), ctx, jti, expiresAt)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockUserRepo) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, userID, codeHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockUserRepoMockRecorder) UseRecoveryCode(ctx, userID, codeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockUserRepo)(nil).UseRecoveryCode), ctx, userID, codeHash)
+}
+
+// UseTOTPStep mocks base method.
+func (m *MockUserRepo) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTOTPStep", ctx, userID, step)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseTOTPStep indicates an expected call of UseTOTPStep.
+func (mr *MockUserRepoMockRecorder) UseTOTPStep(ctx, userID, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTOTPStep", reflect.TypeOf((*MockUserRepo)(nil).UseTOTPStep), ctx, userID, step)
+}
+
 // MockTokenManager is a mock of TokenManager interface.
 type MockTokenManager struct {
 	ctrl     *gomock.Controller
@@ -97,6 +240,36 @@ func (m *MockTokenManager) EXPECT() *MockTokenManagerMockRecorder {
 	return m.recorder
 }
 
+// NewEmailVerificationToken mocks base method.
+func (m *MockTokenManager) NewEmailVerificationToken(userID string, ttl time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewEmailVerificationToken", userID, ttl)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewEmailVerificationToken indicates an expected call of NewEmailVerificationToken.
+func (mr *MockTokenManagerMockRecorder) NewEmailVerificationToken(userID, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewEmailVerificationToken", reflect.TypeOf((*MockTokenManager)(nil).NewEmailVerificationToken), userID, ttl)
+}
+
+// NewMFAToken mocks base method.
+func (m *MockTokenManager) NewMFAToken(userID string, ttl time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewMFAToken", userID, ttl)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewMFAToken indicates an expected call of NewMFAToken.
+func (mr *MockTokenManagerMockRecorder) NewMFAToken(userID, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewMFAToken", reflect.TypeOf((*MockTokenManager)(nil).NewMFAToken), userID, ttl)
+}
+
 // NewToken mocks base method.
 func (m *MockTokenManager) NewToken(userID string, ttl time.Duration) (string, error) {
 	m.ctrl.T.Helper()
@@ -111,3 +284,141 @@ func (mr *MockTokenManagerMockRecorder) NewToken(userID, ttl any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewToken", reflect.TypeOf((*MockTokenManager)(nil).NewToken), userID, ttl)
 }
+
+// ParseEmailVerificationToken mocks base method.
+func (m *MockTokenManager) ParseEmailVerificationToken(tokenStr string) (*jwt.TokenClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseEmailVerificationToken", tokenStr)
+	ret0, _ := ret[0].(*jwt.TokenClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseEmailVerificationToken indicates an expected call of ParseEmailVerificationToken.
+func (mr *MockTokenManagerMockRecorder) ParseEmailVerificationToken(tokenStr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseEmailVerificationToken", reflect.TypeOf((*MockTokenManager)(nil).ParseEmailVerificationToken), tokenStr)
+}
+
+// ParseMFAToken mocks base method.
+func (m *MockTokenManager) ParseMFAToken(tokenStr string) (*jwt.TokenClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseMFAToken", tokenStr)
+	ret0, _ := ret[0].(*jwt.TokenClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseMFAToken indicates an expected call of ParseMFAToken.
+func (mr *MockTokenManagerMockRecorder) ParseMFAToken(tokenStr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseMFAToken", reflect.TypeOf((*MockTokenManager)(nil).ParseMFAToken), tokenStr)
+}
+
+// MockLoginGuard is a mock of LoginGuard interface.
+type MockLoginGuard struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginGuardMockRecorder
+	isgomock struct{}
+}
+
+// MockLoginGuardMockRecorder is the mock recorder for MockLoginGuard.
+type MockLoginGuardMockRecorder struct {
+	mock *MockLoginGuard
+}
+
+// NewMockLoginGuard creates a new mock instance.
+func NewMockLoginGuard(ctrl *gomock.Controller) *MockLoginGuard {
+	mock := &MockLoginGuard{ctrl: ctrl}
+	mock.recorder = &MockLoginGuardMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginGuard) EXPECT() *MockLoginGuardMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockLoginGuard) Check(ctx context.Context, email, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, email, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockLoginGuardMockRecorder) Check(ctx, email, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLoginGuard)(nil).Check), ctx, email, ip)
+}
+
+// CheckMFA mocks base method.
+func (m *MockLoginGuard) CheckMFA(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckMFA", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckMFA indicates an expected call of CheckMFA.
+func (mr *MockLoginGuardMockRecorder) CheckMFA(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckMFA", reflect.TypeOf((*MockLoginGuard)(nil).CheckMFA), ctx, userID)
+}
+
+// Failure mocks base method.
+func (m *MockLoginGuard) Failure(ctx context.Context, email, ip string, accountExists bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Failure", ctx, email, ip, accountExists)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Failure indicates an expected call of Failure.
+func (mr *MockLoginGuardMockRecorder) Failure(ctx, email, ip, accountExists any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Failure", reflect.TypeOf((*MockLoginGuard)(nil).Failure), ctx, email, ip, accountExists)
+}
+
+// MFAFailure mocks base method.
+func (m *MockLoginGuard) MFAFailure(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MFAFailure", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MFAFailure indicates an expected call of MFAFailure.
+func (mr *MockLoginGuardMockRecorder) MFAFailure(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MFAFailure", reflect.TypeOf((*MockLoginGuard)(nil).MFAFailure), ctx, userID)
+}
+
+// MFASuccess mocks base method.
+func (m *MockLoginGuard) MFASuccess(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MFASuccess", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MFASuccess indicates an expected call of MFASuccess.
+func (mr *MockLoginGuardMockRecorder) MFASuccess(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MFASuccess", reflect.TypeOf((*MockLoginGuard)(nil).MFASuccess), ctx, userID)
+}
+
+// Success mocks base method.
+func (m *MockLoginGuard) Success(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Success", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Success indicates an expected call of Success.
+func (mr *MockLoginGuardMockRecorder) Success(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Success", reflect.TypeOf((*MockLoginGuard)(nil).Success), ctx, email)
+}
@@ -7,13 +7,17 @@ import (
 	"github.com/google/uuid"
 	"github.com/ilam072/event-calendar/internal/types/domain"
 	"github.com/ilam072/event-calendar/pkg/errutils"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/lib/pq"
+	"time"
 )
 
 var (
-	ErrUserExists   = errors.New("user exists")
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserExists           = errors.New("user exists")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrRecoveryCodeNotFound = errors.New("recovery code not found")
+	ErrTOTPStepUsed         = errors.New("totp step already used")
+	ErrMFATokenUsed         = errors.New("mfa token already used")
 )
 
 type UserRepo struct {
@@ -24,7 +28,19 @@ func NewUserRepo(db *pgxpool.Pool) *UserRepo {
 	return &UserRepo{db: db}
 }
 
+// CreateUser creates the user together with their default calendar. Pending
+// invitations sent to their email are linked only once the email is verified,
+// see MarkEmailVerified.
 func (r *UserRepo) CreateUser(ctx context.Context, user domain.User) (uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, errutils.Wrap("failed to begin tx", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
 	query := `
 		INSERT INTO users (email, password_hash)
 		VALUES ($1, $2)
@@ -32,26 +48,88 @@ func (r *UserRepo) CreateUser(ctx context.Context, user domain.User) (uuid.UUID,
 	`
 
 	var ID uuid.UUID
-	if err := r.db.QueryRow(ctx, query, user.Email, user.PasswordHash).Scan(&ID); err != nil {
+	if err = tx.QueryRow(ctx, query, user.Email, user.PasswordHash).Scan(&ID); err != nil {
 		if isUniqueViolation(err) {
 			return uuid.Nil, errutils.Wrap("failed to create user", ErrUserExists)
 		}
 		return uuid.Nil, errutils.Wrap("failed to create user", err)
 	}
 
+	query = `
+		INSERT INTO calendars (user_id, name, color, is_default)
+		VALUES ($1, $2, $3, true);
+	`
+
+	if _, err = tx.Exec(ctx, query, ID, domain.DefaultCalendarName, domain.DefaultCalendarColor); err != nil {
+		return uuid.Nil, errutils.Wrap("failed to create default calendar", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return uuid.Nil, errutils.Wrap("failed to commit tx", err)
+	}
+
 	return ID, nil
 }
 
+// MarkEmailVerified records that the user owns their email and links the
+// calendar and event invitations sent to it that are still pending.
+func (r *UserRepo) MarkEmailVerified(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errutils.Wrap("failed to begin tx", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `
+		UPDATE users
+		SET email_verified_at = COALESCE(email_verified_at, now()), updated_at = now()
+		WHERE id = $1
+		RETURNING email;
+	`
+
+	var email string
+	if err = tx.QueryRow(ctx, query, userID).Scan(&email); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errutils.Wrap("failed to verify email", ErrUserNotFound)
+		}
+		return errutils.Wrap("failed to verify email", err)
+	}
+
+	// Calendars the user owns can not be shared with them.
+	query = `
+		UPDATE calendar_shares s SET user_id = $1
+		FROM calendars c
+		WHERE c.id = s.calendar_id AND c.user_id <> $1 AND s.user_id IS NULL AND s.email = lower($2);
+	`
+	if _, err = tx.Exec(ctx, query, userID, email); err != nil {
+		return errutils.Wrap("failed to accept calendar invitations", err)
+	}
+
+	query = `UPDATE event_attendees SET user_id = $1 WHERE user_id IS NULL AND email = lower($2);`
+	if _, err = tx.Exec(ctx, query, userID, email); err != nil {
+		return errutils.Wrap("failed to link event invitations", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return errutils.Wrap("failed to commit tx", err)
+	}
+
+	return nil
+}
+
 func (r *UserRepo) GetUserByID(ctx context.Context, userID uuid.UUID) (domain.User, error) {
 	query := `
-		SELECT id, email, password_hash, created_at, updated_at
+		SELECT id, email, password_hash, COALESCE(totp_secret, ''), totp_enabled, disabled_at, email_verified_at, created_at, updated_at
 		FROM users
 		WHERE id = $1;
 	`
 
 	var user domain.User
 	err := r.db.QueryRow(ctx, query, userID).
-		Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+		Scan(&user.ID, &user.Email, &user.PasswordHash, &user.TOTPSecret, &user.TOTPEnabled, &user.DisabledAt, &user.EmailVerifiedAt, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.User{}, errutils.Wrap("failed to get user", ErrUserNotFound)
@@ -64,14 +142,14 @@ func (r *UserRepo) GetUserByID(ctx context.Context, userID uuid.UUID) (domain.Us
 
 func (r *UserRepo) GetUserByEmail(ctx context.Context, email string) (domain.User, error) {
 	query := `
-		SELECT id, email, password_hash, created_at, updated_at
+		SELECT id, email, password_hash, COALESCE(totp_secret, ''), totp_enabled, disabled_at, email_verified_at, created_at, updated_at
 		FROM users
 		WHERE email = $1;
 	`
 
 	var user domain.User
 	err := r.db.QueryRow(ctx, query, email).
-		Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+		Scan(&user.ID, &user.Email, &user.PasswordHash, &user.TOTPSecret, &user.TOTPEnabled, &user.DisabledAt, &user.EmailVerifiedAt, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.User{}, errutils.Wrap("failed to get user", ErrUserNotFound)
@@ -82,7 +160,293 @@ func (r *UserRepo) GetUserByEmail(ctx context.Context, email string) (domain.Use
 	return user, nil
 }
 
+func (r *UserRepo) SetTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	query := `
+		UPDATE users
+		SET totp_secret = $1,
+		    totp_enabled = false,
+		    totp_last_step = NULL,
+		    updated_at = now()
+		WHERE id = $2;
+	`
+
+	res, err := r.db.Exec(ctx, query, secret, userID)
+	if err != nil {
+		return errutils.Wrap("failed to set totp secret", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepo) EnableTOTP(ctx context.Context, userID uuid.UUID, recoveryCodeHashes []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errutils.Wrap("failed to begin tx", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `UPDATE users SET totp_enabled = true, updated_at = now() WHERE id = $1;`
+	res, err := tx.Exec(ctx, query, userID)
+	if err != nil {
+		return errutils.Wrap("failed to enable totp", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	if _, err = tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1;`, userID); err != nil {
+		return errutils.Wrap("failed to delete recovery codes", err)
+	}
+
+	query = `INSERT INTO user_recovery_codes (user_id, code_hash) VALUES ($1, $2);`
+	for _, hash := range recoveryCodeHashes {
+		if _, err = tx.Exec(ctx, query, userID, hash); err != nil {
+			return errutils.Wrap("failed to insert recovery code", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return errutils.Wrap("failed to commit tx", err)
+	}
+
+	return nil
+}
+
+func (r *UserRepo) DisableTOTP(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errutils.Wrap("failed to begin tx", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `
+		UPDATE users
+		SET totp_secret = NULL,
+		    totp_enabled = false,
+		    totp_last_step = NULL,
+		    updated_at = now()
+		WHERE id = $1;
+	`
+	res, err := tx.Exec(ctx, query, userID)
+	if err != nil {
+		return errutils.Wrap("failed to disable totp", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	if _, err = tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1;`, userID); err != nil {
+		return errutils.Wrap("failed to delete recovery codes", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return errutils.Wrap("failed to commit tx", err)
+	}
+
+	return nil
+}
+
+func (r *UserRepo) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	query := `
+		UPDATE user_recovery_codes
+		SET used_at = now()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+	`
+
+	res, err := r.db.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return errutils.Wrap("failed to use recovery code", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrRecoveryCodeNotFound
+	}
+
+	return nil
+}
+
+// UseTOTPStep records the time step of an accepted TOTP code. It returns
+// ErrTOTPStepUsed if a code of the same or a later step was accepted before.
+func (r *UserRepo) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	query := `
+		UPDATE users
+		SET totp_last_step = $2
+		WHERE id = $1 AND (totp_last_step IS NULL OR totp_last_step < $2);
+	`
+
+	res, err := r.db.Exec(ctx, query, userID, step)
+	if err != nil {
+		return errutils.Wrap("failed to use totp step", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrTOTPStepUsed
+	}
+
+	return nil
+}
+
+// UseMFAToken marks the MFA token as exchanged. It returns ErrMFATokenUsed if
+// it was exchanged before. The record is only needed until the token expires.
+func (r *UserRepo) UseMFAToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO used_mfa_tokens (jti, expires_at)
+		VALUES ($1, $2)
+		ON CONFLICT (jti) DO NOTHING;
+	`
+
+	res, err := r.db.Exec(ctx, query, jti, expiresAt)
+	if err != nil {
+		return errutils.Wrap("failed to use mfa token", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrMFATokenUsed
+	}
+
+	return nil
+}
+
+// DeleteExpiredMFATokens removes the used MFA tokens that have expired, since
+// they can not be exchanged anymore anyway.
+func (r *UserRepo) DeleteExpiredMFATokens(ctx context.Context) error {
+	query := `DELETE FROM used_mfa_tokens WHERE expires_at < now();`
+
+	if _, err := r.db.Exec(ctx, query); err != nil {
+		return errutils.Wrap("failed to delete expired mfa tokens", err)
+	}
+
+	return nil
+}
+
+func (r *UserRepo) GetUserByIdentity(ctx context.Context, issuer, subject string) (domain.User, error) {
+	query := `
+		SELECT u.id, u.email, u.password_hash, COALESCE(u.totp_secret, ''), u.totp_enabled, u.disabled_at, u.email_verified_at, u.created_at, u.updated_at
+		FROM users u
+		JOIN user_identities i ON i.user_id = u.id
+		WHERE i.issuer = $1 AND i.subject = $2;
+	`
+
+	var user domain.User
+	err := r.db.QueryRow(ctx, query, issuer, subject).
+		Scan(&user.ID, &user.Email, &user.PasswordHash, &user.TOTPSecret, &user.TOTPEnabled, &user.DisabledAt, &user.EmailVerifiedAt, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.User{}, errutils.Wrap("failed to get user by identity", ErrUserNotFound)
+		}
+		return domain.User{}, errutils.Wrap("failed to get user by identity", err)
+	}
+
+	return user, nil
+}
+
+func (r *UserRepo) LinkIdentity(ctx context.Context, userID uuid.UUID, issuer, subject string) error {
+	query := `
+		INSERT INTO user_identities (issuer, subject, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (issuer, subject) DO NOTHING;
+	`
+
+	if _, err := r.db.Exec(ctx, query, issuer, subject, userID); err != nil {
+		return errutils.Wrap("failed to link identity", err)
+	}
+
+	return nil
+}
+
+// GetIdentities returns the identity provider accounts linked to the user.
+func (r *UserRepo) GetIdentities(ctx context.Context, userID uuid.UUID) ([]domain.UserIdentity, error) {
+	query := `
+		SELECT issuer, subject, user_id, created_at
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at;
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get identities", err)
+	}
+	defer rows.Close()
+
+	var identities []domain.UserIdentity
+	for rows.Next() {
+		var identity domain.UserIdentity
+		if err = rows.Scan(&identity.Issuer, &identity.Subject, &identity.UserID, &identity.CreatedAt); err != nil {
+			return nil, errutils.Wrap("failed to scan identity", err)
+		}
+		identities = append(identities, identity)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errutils.Wrap("failed to iterate identities", err)
+	}
+
+	return identities, nil
+}
+
+// IsActive reports whether the user exists and is not disabled.
+func (r *UserRepo) IsActive(ctx context.Context, userID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1 AND disabled_at IS NULL);`
+
+	var active bool
+	if err := r.db.QueryRow(ctx, query, userID).Scan(&active); err != nil {
+		return false, errutils.Wrap("failed to check user", err)
+	}
+
+	return active, nil
+}
+
+// SetDisabled disables or re-enables sign-in for the user.
+func (r *UserRepo) SetDisabled(ctx context.Context, userID uuid.UUID, disabled bool) error {
+	query := `
+		UPDATE users
+		SET disabled_at = CASE WHEN $2 THEN COALESCE(disabled_at, now()) END, updated_at = now()
+		WHERE id = $1;
+	`
+
+	res, err := r.db.Exec(ctx, query, userID, disabled)
+	if err != nil {
+		return errutils.Wrap("failed to disable user", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return errutils.Wrap("failed to disable user", ErrUserNotFound)
+	}
+
+	return nil
+}
+
+func (r *UserRepo) SetPasswordHash(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	query := `UPDATE users SET password_hash = $2, updated_at = now() WHERE id = $1;`
+
+	res, err := r.db.Exec(ctx, query, userID, passwordHash)
+	if err != nil {
+		return errutils.Wrap("failed to set password", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return errutils.Wrap("failed to set password", ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// isUniqueViolation reports whether err is the unique_violation error of
+// Postgres.
 func isUniqueViolation(err error) bool {
-	var pqErr *pq.Error
-	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
 }
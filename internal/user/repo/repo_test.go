@@ -0,0 +1,27 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+	"testing"
+)
+
+func TestIsUniqueViolation(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"unique violation":         {&pgconn.PgError{Code: "23505"}, true},
+		"wrapped unique violation": {fmt.Errorf("failed to insert: %w", &pgconn.PgError{Code: "23505"}), true},
+		"other postgres error":     {&pgconn.PgError{Code: "23503"}, false},
+		"other error":              {errors.New("connection refused"), false},
+		"nil":                      {nil, false},
+	}
+
+	for name, c := range cases {
+		if got := isUniqueViolation(c.err); got != c.want {
+			t.Fatalf("%s: expected %v, got %v", name, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"context"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type LoginThrottleRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewLoginThrottleRepo(db *pgxpool.Pool) *LoginThrottleRepo {
+	return &LoginThrottleRepo{db: db}
+}
+
+func (r *LoginThrottleRepo) GetLoginThrottles(ctx context.Context, keys []string) ([]domain.LoginThrottle, error) {
+	query := `
+		SELECT key, failures, last_failure_at, locked_until
+		FROM login_throttles
+		WHERE key = ANY($1);
+	`
+
+	rows, err := r.db.Query(ctx, query, keys)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get login throttles", err)
+	}
+	defer rows.Close()
+
+	var throttles []domain.LoginThrottle
+	for rows.Next() {
+		var throttle domain.LoginThrottle
+		if err = rows.Scan(&throttle.Key, &throttle.Failures, &throttle.LastFailureAt, &throttle.LockedUntil); err != nil {
+			return nil, errutils.Wrap("failed to scan login throttle", err)
+		}
+		throttles = append(throttles, throttle)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errutils.Wrap("failed to iterate login throttles", err)
+	}
+
+	return throttles, nil
+}
+
+// RecordLoginFailure counts a failed attempt for the key. Failures older than
+// resetBefore are forgotten and the count starts again from one.
+func (r *LoginThrottleRepo) RecordLoginFailure(ctx context.Context, key string, now time.Time, resetBefore time.Time) (domain.LoginThrottle, error) {
+	query := `
+		INSERT INTO login_throttles (key, failures, last_failure_at)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE
+		SET failures = CASE
+		        WHEN login_throttles.last_failure_at < $3 THEN 1
+		        ELSE login_throttles.failures + 1
+		    END,
+		    last_failure_at = $2
+		RETURNING key, failures, last_failure_at, locked_until;
+	`
+
+	var throttle domain.LoginThrottle
+	err := r.db.QueryRow(ctx, query, key, now, resetBefore).
+		Scan(&throttle.Key, &throttle.Failures, &throttle.LastFailureAt, &throttle.LockedUntil)
+	if err != nil {
+		return domain.LoginThrottle{}, errutils.Wrap("failed to record login failure", err)
+	}
+
+	return throttle, nil
+}
+
+// LockLogin blocks the key until the given time and clears its failures, so
+// attempts after the lock expires start from a clean slate.
+func (r *LoginThrottleRepo) LockLogin(ctx context.Context, key string, until time.Time) error {
+	query := `
+		UPDATE login_throttles
+		SET failures = 0,
+		    locked_until = $2
+		WHERE key = $1;
+	`
+
+	if _, err := r.db.Exec(ctx, query, key, until); err != nil {
+		return errutils.Wrap("failed to lock login", err)
+	}
+
+	return nil
+}
+
+func (r *LoginThrottleRepo) ResetLoginFailures(ctx context.Context, key string) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM login_throttles WHERE key = $1;`, key); err != nil {
+		return errutils.Wrap("failed to reset login failures", err)
+	}
+
+	return nil
+}
+
+// DeleteStale removes the throttles that no longer lock their key and whose
+// last failure is older than olderThan.
+func (r *LoginThrottleRepo) DeleteStale(ctx context.Context, olderThan time.Duration) error {
+	query := `
+		DELETE FROM login_throttles
+		WHERE last_failure_at < $1
+		  AND (locked_until IS NULL OR locked_until < now());
+	`
+
+	if _, err := r.db.Exec(ctx, query, time.Now().Add(-olderThan)); err != nil {
+		return errutils.Wrap("failed to delete stale login throttles", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/ilam072/event-calendar/internal/response"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"github.com/ilam072/event-calendar/pkg/oidc"
+	"net/http"
+	"strings"
+)
+
+const (
+	oidcCookieName   = "oidc_flow"
+	oidcCookiePath   = "/auth/oidc"
+	oidcCookieMaxAge = 600
+)
+
+//go:generate mockgen -source=oidc.go -destination=../mocks/oidc_rest_mocks.go -package=mocks
+type OIDCUser interface {
+	LoginOIDC(ctx context.Context, identity dto.OIDCIdentity) (dto.LoginResponse, error)
+}
+
+type OIDCProvider interface {
+	AuthCodeURL(state, nonce, verifier string) string
+	Exchange(ctx context.Context, code, nonce, verifier string) (oidc.Identity, error)
+}
+
+type OIDCHandler struct {
+	user     OIDCUser
+	provider OIDCProvider
+	logger   logger.Logger
+}
+
+func NewOIDCHandler(user OIDCUser, provider OIDCProvider, logger logger.Logger) *OIDCHandler {
+	return &OIDCHandler{user: user, provider: provider, logger: logger}
+}
+
+// Login starts the authorization code flow. State, nonce and the PKCE
+// verifier are kept in a short-lived cookie until the callback.
+func (h *OIDCHandler) Login(c *gin.Context) {
+	state, nonce, verifier, err := oidc.NewFlow()
+	if err != nil {
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Msg("failed to start oidc flow")
+		response.InternalServerError(c)
+		return
+	}
+
+	h.setFlowCookie(c, strings.Join([]string{state, nonce, verifier}, "."), oidcCookieMaxAge)
+	c.Redirect(http.StatusFound, h.provider.AuthCodeURL(state, nonce, verifier))
+}
+
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	if errCode := c.Query("error"); errCode != "" {
+		h.logger.Warn().Ctx(c.Request.Context()).Str("error", errCode).Str("description", c.Query("error_description")).Msg("oidc provider returned error")
+		response.Unauthorized(c, "identity provider rejected the login")
+		return
+	}
+
+	cookie, err := c.Cookie(oidcCookieName)
+	if err != nil {
+		response.BadRequest(c, "missing login state")
+		return
+	}
+	h.setFlowCookie(c, "", -1)
+
+	parts := strings.Split(cookie, ".")
+	if len(parts) != 3 || c.Query("state") == "" || c.Query("state") != parts[0] {
+		response.BadRequest(c, "invalid login state")
+		return
+	}
+
+	code := c.Query("code")
+	if code == "" {
+		response.BadRequest(c, "missing authorization code")
+		return
+	}
+
+	identity, err := h.provider.Exchange(c.Request.Context(), code, parts[1], parts[2])
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to exchange oidc code")
+		response.Unauthorized(c, "failed to verify identity")
+		return
+	}
+
+	resp, err := h.user.LoginOIDC(c.Request.Context(), dto.OIDCIdentity{
+		Issuer:        identity.Issuer,
+		Subject:       identity.Subject,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrEmailNotVerified) {
+			response.Forbidden(c, "email is not verified by identity provider")
+			return
+		}
+		if errors.Is(err, domain.ErrUserDisabled) {
+			response.Forbidden(c, "account is disabled")
+			return
+		}
+		if errors.Is(err, domain.ErrUnverifiedAccountExists) {
+			response.Conflict(c, "UNVERIFIED_ACCOUNT_EXISTS", "an account with this email exists but its email is not verified: sign in with the password and verify the email first")
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("issuer", identity.Issuer).Str("subject", identity.Subject).Msg("failed to login oidc user")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *OIDCHandler) setFlowCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcCookieName, value, maxAge, oidcCookiePath, "", c.Request.TLS != nil, true)
+}
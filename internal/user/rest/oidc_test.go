@@ -0,0 +1,156 @@
+package rest_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/internal/user/mocks"
+	"github.com/ilam072/event-calendar/internal/user/rest"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"github.com/ilam072/event-calendar/pkg/oidc"
+)
+
+func oidcRouter(h *rest.OIDCHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/auth/oidc/login", h.Login)
+	r.GET("/auth/oidc/callback", h.Callback)
+	return r
+}
+
+// startOIDCLogin runs the login endpoint and returns the flow cookie and
+// the state passed to the provider.
+func startOIDCLogin(t *testing.T, r *gin.Engine, provider *mocks.MockOIDCProvider) (*http.Cookie, string, string, string) {
+	t.Helper()
+
+	var state, nonce, verifier string
+	provider.EXPECT().AuthCodeURL(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(s, n, v string) string {
+			state, nonce, verifier = s, n, v
+			return "https://idp.example.com/authorize?state=" + s
+		})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/oidc/login", nil))
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://idp.example.com/authorize?state="+state, w.Header().Get("Location"))
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected flow cookie, got %v", cookies)
+	}
+	assert.True(t, cookies[0].HttpOnly)
+
+	return cookies[0], state, nonce, verifier
+}
+
+func TestOIDCHandler_Callback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUser := mocks.NewMockOIDCUser(ctrl)
+	mockProvider := mocks.NewMockOIDCProvider(ctrl)
+	r := oidcRouter(rest.NewOIDCHandler(mockUser, mockProvider, &logger.DummyLogger{}))
+
+	identity := oidc.Identity{
+		Issuer:        "https://idp.example.com",
+		Subject:       "sub-1",
+		Email:         "test@mail.com",
+		EmailVerified: true,
+	}
+
+	callback := func(cookie *http.Cookie, state string) *httptest.ResponseRecorder {
+		q := url.Values{"code": {"code-1"}, "state": {state}}
+		req := httptest.NewRequest(http.MethodGet, "/auth/oidc/callback?"+q.Encode(), nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("success", func(t *testing.T) {
+		cookie, state, nonce, verifier := startOIDCLogin(t, r, mockProvider)
+
+		mockProvider.EXPECT().Exchange(gomock.Any(), "code-1", nonce, verifier).Return(identity, nil)
+		mockUser.EXPECT().LoginOIDC(gomock.Any(), dto.OIDCIdentity{
+			Issuer:        identity.Issuer,
+			Subject:       identity.Subject,
+			Email:         identity.Email,
+			EmailVerified: true,
+		}).Return(dto.LoginResponse{Token: "token"}, nil)
+
+		w := callback(cookie, state)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"token":"token"`)
+	})
+
+	t.Run("state mismatch", func(t *testing.T) {
+		cookie, _, _, _ := startOIDCLogin(t, r, mockProvider)
+
+		w := callback(cookie, "forged")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("missing cookie", func(t *testing.T) {
+		w := callback(nil, "state")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/auth/oidc/callback?error=access_denied", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("exchange failed", func(t *testing.T) {
+		cookie, state, _, _ := startOIDCLogin(t, r, mockProvider)
+
+		mockProvider.EXPECT().Exchange(gomock.Any(), "code-1", gomock.Any(), gomock.Any()).
+			Return(oidc.Identity{}, oidc.ErrInvalidIDToken)
+
+		w := callback(cookie, state)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("email not verified", func(t *testing.T) {
+		cookie, state, _, _ := startOIDCLogin(t, r, mockProvider)
+
+		mockProvider.EXPECT().Exchange(gomock.Any(), "code-1", gomock.Any(), gomock.Any()).Return(identity, nil)
+		mockUser.EXPECT().LoginOIDC(gomock.Any(), gomock.Any()).Return(dto.LoginResponse{}, domain.ErrEmailNotVerified)
+
+		w := callback(cookie, state)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		cookie, state, _, _ := startOIDCLogin(t, r, mockProvider)
+
+		mockProvider.EXPECT().Exchange(gomock.Any(), "code-1", gomock.Any(), gomock.Any()).Return(identity, nil)
+		mockUser.EXPECT().LoginOIDC(gomock.Any(), gomock.Any()).Return(dto.LoginResponse{}, errors.New("db down"))
+
+		w := callback(cookie, state)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.False(t, strings.Contains(w.Body.String(), "db down"))
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/ilam072/event-calendar/internal/response"
 	"github.com/ilam072/event-calendar/internal/types/domain"
 	"github.com/ilam072/event-calendar/internal/types/dto"
@@ -15,7 +16,12 @@ import (
 //go:generate mockgen -source=rest.go -destination=../mocks/rest_mocks.go -package=mocks
 type User interface {
 	Register(ctx context.Context, user dto.RegisterUser) (string, error)
-	Login(ctx context.Context, creds dto.LoginUser) (string, error)
+	VerifyEmail(ctx context.Context, token string) error
+	Login(ctx context.Context, creds dto.LoginUser, ip string) (dto.LoginResponse, error)
+	VerifyMFA(ctx context.Context, req dto.VerifyMFA) (string, error)
+	EnrollTOTP(ctx context.Context, userID uuid.UUID) (dto.TOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) (dto.RecoveryCodes, error)
+	DisableTOTP(ctx context.Context, userID uuid.UUID, code string) error
 }
 
 type Validator interface {
@@ -35,7 +41,7 @@ func NewUserHandler(user User, validator Validator, logger logger.Logger) *UserH
 func (h *UserHandler) SignUp(c *gin.Context) {
 	var user dto.RegisterUser
 	if err := c.BindJSON(&user); err != nil {
-		h.logger.Warn().Err(err).Msg("failed to bind register user json")
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to bind register user json")
 		response.BadRequest(c, "invalid request body")
 		return
 	}
@@ -51,7 +57,7 @@ func (h *UserHandler) SignUp(c *gin.Context) {
 			response.Conflict(c, "USER_EXISTS", "user with such email already exists")
 			return
 		}
-		h.logger.Error().Err(err).Any("user", user).Msg("failed to register user")
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Any("user", user).Msg("failed to register user")
 		response.InternalServerError(c)
 		return
 	}
@@ -59,10 +65,35 @@ func (h *UserHandler) SignUp(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"user_id": ID})
 }
 
+// VerifyEmailPage is where the link from the sign-up email leads. The email
+// is only verified once the page is submitted to VerifyEmail.
+func (h *UserHandler) VerifyEmailPage(c *gin.Context) {
+	response.ConfirmPage(c,
+		"Confirm your email address",
+		"Confirm that this email address is yours to get access to the calendars and events you are invited to.",
+		"Confirm", c.Request.URL.Path,
+		map[string]string{"token": c.Query("token")},
+	)
+}
+
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	if err := h.user.VerifyEmail(c.Request.Context(), c.PostForm("token")); err != nil {
+		if errors.Is(err, domain.ErrInvalidVerificationLink) {
+			response.Page(c, http.StatusBadRequest, "Invalid link", "This link is invalid or has expired.")
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Msg("failed to verify email")
+		response.Page(c, http.StatusInternalServerError, "Something went wrong", "Your email address could not be confirmed, try again later.")
+		return
+	}
+
+	response.Page(c, http.StatusOK, "Email address confirmed", "Your email address is confirmed.")
+}
+
 func (h *UserHandler) SignIn(c *gin.Context) {
 	var user dto.LoginUser
 	if err := c.BindJSON(&user); err != nil {
-		h.logger.Warn().Err(err).Msg("failed to bind register user json")
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to bind register user json")
 		response.BadRequest(c, "invalid request body")
 		return
 	}
@@ -72,16 +103,184 @@ func (h *UserHandler) SignIn(c *gin.Context) {
 		return
 	}
 
-	token, err := h.user.Login(c.Request.Context(), user)
+	resp, err := h.user.Login(c.Request.Context(), user, c.ClientIP())
 	if err != nil {
-		if errors.Is(err, domain.ErrInvalidCredentials) {
+		var blocked *domain.LoginBlockedError
+		switch {
+		case errors.Is(err, domain.ErrInvalidCredentials):
 			response.Unauthorized(c, "invalid credentials")
 			return
+		case errors.Is(err, domain.ErrUserDisabled):
+			response.Forbidden(c, "account is disabled")
+			return
+		case errors.As(err, &blocked) && errors.Is(blocked, domain.ErrAccountLocked):
+			response.Locked(c, blocked.RetryAfter, "account is temporarily locked after too many failed sign-in attempts")
+			return
+		case errors.As(err, &blocked):
+			response.TooManyRequests(c, blocked.RetryAfter, "too many sign-in attempts, try again later")
+			return
 		}
-		h.logger.Error().Err(err).Any("user", user).Msg("failed to login user")
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Any("user", user).Msg("failed to login user")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *UserHandler) VerifyMFA(c *gin.Context) {
+	var req dto.VerifyMFA
+	if err := c.BindJSON(&req); err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to bind verify mfa json")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	token, err := h.user.VerifyMFA(c.Request.Context(), req)
+	if err != nil {
+		var blocked *domain.LoginBlockedError
+		switch {
+		case errors.Is(err, domain.ErrInvalidCredentials) || errors.Is(err, domain.ErrInvalidMFACode):
+			response.Unauthorized(c, "invalid mfa token or code")
+			return
+		case errors.Is(err, domain.ErrUserDisabled):
+			response.Forbidden(c, "account is disabled")
+			return
+		case errors.As(err, &blocked) && errors.Is(blocked, domain.ErrAccountLocked):
+			response.Locked(c, blocked.RetryAfter, "two-factor sign-in is temporarily locked after too many failed attempts")
+			return
+		case errors.As(err, &blocked):
+			response.TooManyRequests(c, blocked.RetryAfter, "too many two-factor attempts, try again later")
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Msg("failed to verify mfa")
 		response.InternalServerError(c)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"token": token})
 }
+
+func (h *UserHandler) EnrollTOTP(c *gin.Context) {
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.user.EnrollTOTP(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTOTPEnabled) {
+			response.Conflict(c, "TOTP_ENABLED", "two-factor authentication is already enabled")
+			return
+		}
+		if errors.Is(err, domain.ErrUserNotFound) {
+			response.NotFound(c)
+			return
+		}
+		h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("user_id", userID.String()).Msg("failed to enroll totp")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+func (h *UserHandler) ConfirmTOTP(c *gin.Context) {
+	req, ok := h.bindTOTPCode(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.user.ConfirmTOTP(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidMFACode):
+			response.BadRequest(c, "invalid code")
+		case errors.Is(err, domain.ErrTOTPEnabled):
+			response.Conflict(c, "TOTP_ENABLED", "two-factor authentication is already enabled")
+		case errors.Is(err, domain.ErrTOTPNotEnrolled):
+			response.Conflict(c, "TOTP_NOT_ENROLLED", "two-factor authentication enrollment was not started")
+		case errors.Is(err, domain.ErrUserNotFound):
+			response.NotFound(c)
+		default:
+			h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("user_id", userID.String()).Msg("failed to confirm totp")
+			response.InternalServerError(c)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, codes)
+}
+
+func (h *UserHandler) DisableTOTP(c *gin.Context) {
+	req, ok := h.bindTOTPCode(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := h.getUserData(c)
+	if !ok {
+		return
+	}
+
+	if err := h.user.DisableTOTP(c.Request.Context(), userID, req.Code); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidMFACode):
+			response.BadRequest(c, "invalid code")
+		case errors.Is(err, domain.ErrTOTPNotEnrolled):
+			response.Conflict(c, "TOTP_NOT_ENROLLED", "two-factor authentication is not enabled")
+		case errors.Is(err, domain.ErrUserNotFound):
+			response.NotFound(c)
+		default:
+			h.logger.Error().Ctx(c.Request.Context()).Err(err).Str("user_id", userID.String()).Msg("failed to disable totp")
+			response.InternalServerError(c)
+		}
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h *UserHandler) bindTOTPCode(c *gin.Context) (dto.TOTPCode, bool) {
+	var req dto.TOTPCode
+	if err := c.BindJSON(&req); err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Err(err).Msg("failed to bind totp code json")
+		response.BadRequest(c, "invalid request body")
+		return dto.TOTPCode{}, false
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return dto.TOTPCode{}, false
+	}
+
+	return req, true
+}
+
+func (h *UserHandler) getUserData(c *gin.Context) (uuid.UUID, bool) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		h.logger.Error().Ctx(c.Request.Context()).Msg("failed to get user_id from ctx")
+		response.Unauthorized(c, "missing user_id in token")
+		return uuid.Nil, false
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		h.logger.Warn().Ctx(c.Request.Context()).Any("user_id", userID).Msg("failed to parse user id into uuid")
+		response.Unauthorized(c, "user_id must be uuid format")
+		return uuid.Nil, false
+	}
+
+	return userUUID, true
+}
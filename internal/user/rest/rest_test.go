@@ -8,9 +8,12 @@ import (
 	"go.uber.org/mock/gomock"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
 	"github.com/ilam072/event-calendar/internal/types/domain"
 	"github.com/ilam072/event-calendar/internal/types/dto"
@@ -150,7 +153,7 @@ func TestUserHandler_SignIn(t *testing.T) {
 		req := dto.LoginUser{Email: "test@mail.com", Password: "123456"}
 
 		mockValidator.EXPECT().Validate(req).Return(nil)
-		mockUser.EXPECT().Login(context.Background(), req).Return("TOKEN_123", nil)
+		mockUser.EXPECT().Login(context.Background(), req, "192.0.2.1").Return(dto.LoginResponse{Token: "TOKEN_123"}, nil)
 
 		h.SignIn(ctx)
 
@@ -203,7 +206,7 @@ func TestUserHandler_SignIn(t *testing.T) {
 		req := dto.LoginUser{Email: "test@mail.com", Password: "wrong"}
 
 		mockValidator.EXPECT().Validate(req).Return(nil)
-		mockUser.EXPECT().Login(context.Background(), req).Return("", domain.ErrInvalidCredentials)
+		mockUser.EXPECT().Login(context.Background(), req, "192.0.2.1").Return(dto.LoginResponse{}, domain.ErrInvalidCredentials)
 
 		h.SignIn(ctx)
 
@@ -212,6 +215,74 @@ func TestUserHandler_SignIn(t *testing.T) {
 		}
 	})
 
+	t.Run("disabled", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+
+		body := `{"email":"test@mail.com","password":"123456"}`
+		ctx.Request = httptest.NewRequest(http.MethodPost, "/signin", bytes.NewBufferString(body))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+
+		req := dto.LoginUser{Email: "test@mail.com", Password: "123456"}
+
+		mockValidator.EXPECT().Validate(req).Return(nil)
+		mockUser.EXPECT().Login(context.Background(), req, "192.0.2.1").Return(dto.LoginResponse{}, domain.ErrUserDisabled)
+
+		h.SignIn(ctx)
+
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("too many attempts", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+
+		body := `{"email":"test@mail.com","password":"wrong"}`
+		ctx.Request = httptest.NewRequest(http.MethodPost, "/signin", bytes.NewBufferString(body))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+
+		req := dto.LoginUser{Email: "test@mail.com", Password: "wrong"}
+		blocked := &domain.LoginBlockedError{Err: domain.ErrTooManyAttempts, RetryAfter: 1500 * time.Millisecond}
+
+		mockValidator.EXPECT().Validate(req).Return(nil)
+		mockUser.EXPECT().Login(context.Background(), req, "192.0.2.1").Return(dto.LoginResponse{}, blocked)
+
+		h.SignIn(ctx)
+
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("expected 429, got %d", w.Code)
+		}
+		if w.Header().Get("Retry-After") != "2" {
+			t.Fatalf("expected Retry-After 2, got %q", w.Header().Get("Retry-After"))
+		}
+	})
+
+	t.Run("account locked", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+
+		body := `{"email":"test@mail.com","password":"wrong"}`
+		ctx.Request = httptest.NewRequest(http.MethodPost, "/signin", bytes.NewBufferString(body))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+
+		req := dto.LoginUser{Email: "test@mail.com", Password: "wrong"}
+		blocked := &domain.LoginBlockedError{Err: domain.ErrAccountLocked, RetryAfter: 15 * time.Minute}
+
+		mockValidator.EXPECT().Validate(req).Return(nil)
+		mockUser.EXPECT().Login(context.Background(), req, "192.0.2.1").Return(dto.LoginResponse{}, blocked)
+
+		h.SignIn(ctx)
+
+		if w.Code != http.StatusLocked {
+			t.Fatalf("expected 423, got %d", w.Code)
+		}
+		if w.Header().Get("Retry-After") != "900" {
+			t.Fatalf("expected Retry-After 900, got %q", w.Header().Get("Retry-After"))
+		}
+	})
+
 	t.Run("internal error", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		ctx, _ := gin.CreateTestContext(w)
@@ -223,7 +294,7 @@ func TestUserHandler_SignIn(t *testing.T) {
 		req := dto.LoginUser{Email: "test@mail.com", Password: "123456"}
 
 		mockValidator.EXPECT().Validate(req).Return(nil)
-		mockUser.EXPECT().Login(context.Background(), req).Return("", errors.New("db error"))
+		mockUser.EXPECT().Login(context.Background(), req, "192.0.2.1").Return(dto.LoginResponse{}, errors.New("db error"))
 
 		h.SignIn(ctx)
 
@@ -232,3 +303,180 @@ func TestUserHandler_SignIn(t *testing.T) {
 		}
 	})
 }
+
+func TestUserHandler_VerifyMFA(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUser := mocks.NewMockUser(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	logStub := &logger.DummyLogger{}
+
+	h := rest.NewUserHandler(mockUser, mockValidator, logStub)
+
+	t.Run("success", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+
+		body := `{"mfa_token":"MFA_TOKEN","code":"123456"}`
+		ctx.Request = httptest.NewRequest(http.MethodPost, "/signin/mfa", bytes.NewBufferString(body))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+
+		req := dto.VerifyMFA{MFAToken: "MFA_TOKEN", Code: "123456"}
+
+		mockValidator.EXPECT().Validate(req).Return(nil)
+		mockUser.EXPECT().VerifyMFA(context.Background(), req).Return("TOKEN_123", nil)
+
+		h.VerifyMFA(ctx)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+
+		body := `{"mfa_token":"MFA_TOKEN","code":"000000"}`
+		ctx.Request = httptest.NewRequest(http.MethodPost, "/signin/mfa", bytes.NewBufferString(body))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+
+		req := dto.VerifyMFA{MFAToken: "MFA_TOKEN", Code: "000000"}
+
+		mockValidator.EXPECT().Validate(req).Return(nil)
+		mockUser.EXPECT().VerifyMFA(context.Background(), req).Return("", domain.ErrInvalidMFACode)
+
+		h.VerifyMFA(ctx)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", w.Code)
+		}
+	})
+}
+
+func TestUserHandler_ConfirmTOTP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUser := mocks.NewMockUser(ctrl)
+	mockValidator := mocks.NewMockValidator(ctrl)
+
+	logStub := &logger.DummyLogger{}
+
+	h := rest.NewUserHandler(mockUser, mockValidator, logStub)
+
+	userID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+
+		ctx.Request = httptest.NewRequest(http.MethodPost, "/me/totp/confirm", bytes.NewBufferString(`{"code":"123456"}`))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+		ctx.Set("user_id", userID.String())
+
+		mockValidator.EXPECT().Validate(dto.TOTPCode{Code: "123456"}).Return(nil)
+		mockUser.EXPECT().ConfirmTOTP(context.Background(), userID, "123456").Return(dto.RecoveryCodes{Codes: []string{"abcde-fghij"}}, nil)
+
+		h.ConfirmTOTP(ctx)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+
+		ctx.Request = httptest.NewRequest(http.MethodPost, "/me/totp/confirm", bytes.NewBufferString(`{"code":"000000"}`))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+		ctx.Set("user_id", userID.String())
+
+		mockValidator.EXPECT().Validate(dto.TOTPCode{Code: "000000"}).Return(nil)
+		mockUser.EXPECT().ConfirmTOTP(context.Background(), userID, "000000").Return(dto.RecoveryCodes{}, domain.ErrInvalidMFACode)
+
+		h.ConfirmTOTP(ctx)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("no user id", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+
+		ctx.Request = httptest.NewRequest(http.MethodPost, "/me/totp/confirm", bytes.NewBufferString(`{"code":"123456"}`))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+
+		mockValidator.EXPECT().Validate(dto.TOTPCode{Code: "123456"}).Return(nil)
+
+		h.ConfirmTOTP(ctx)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", w.Code)
+		}
+	})
+}
+
+func TestUserHandler_VerifyEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUser := mocks.NewMockUser(ctrl)
+	h := rest.NewUserHandler(mockUser, mocks.NewMockValidator(ctrl), &logger.DummyLogger{})
+
+	t.Run("link only shows the confirmation form", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/auth/verify-email?token=VERIFY", nil)
+
+		h.VerifyEmailPage(ctx)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+		if !strings.Contains(w.Body.String(), `method="post"`) || !strings.Contains(w.Body.String(), `value="VERIFY"`) {
+			t.Fatalf("expected a form posting the token, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("form verifies the email", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = httptest.NewRequest(http.MethodPost, "/auth/verify-email", strings.NewReader("token=VERIFY"))
+		ctx.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		mockUser.EXPECT().VerifyEmail(gomock.Any(), "VERIFY").Return(nil)
+
+		h.VerifyEmail(ctx)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("invalid link", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = httptest.NewRequest(http.MethodPost, "/auth/verify-email", strings.NewReader("token=BAD"))
+		ctx.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		mockUser.EXPECT().VerifyEmail(gomock.Any(), "BAD").Return(domain.ErrInvalidVerificationLink)
+
+		h.VerifyEmail(ctx)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", w.Code)
+		}
+	})
+}
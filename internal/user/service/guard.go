@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"strings"
+	"time"
+)
+
+const (
+	// failureWindow is how long a failed attempt is remembered.
+	failureWindow = 15 * time.Minute
+	// freeAttempts is the number of failures allowed before delays apply.
+	freeAttempts = 3
+	baseDelay    = time.Second
+	maxDelay     = 30 * time.Second
+
+	accountLockThreshold = 10
+	ipLockThreshold      = 50
+	// mfaLockThreshold is lower, as a valid password was already given and
+	// a code is much easier to guess.
+	mfaLockThreshold = 5
+	lockDuration     = 15 * time.Minute
+)
+
+//go:generate mockgen -source=guard.go -destination=../mocks/guard_mocks.go -package=mocks
+type LoginThrottleRepo interface {
+	GetLoginThrottles(ctx context.Context, keys []string) ([]domain.LoginThrottle, error)
+	RecordLoginFailure(ctx context.Context, key string, now time.Time, resetBefore time.Time) (domain.LoginThrottle, error)
+	LockLogin(ctx context.Context, key string, until time.Time) error
+	ResetLoginFailures(ctx context.Context, key string) error
+}
+
+type Sender interface {
+	Send(subject string, message string, to string) error
+}
+
+// Guard limits password sign-in attempts per account and per client IP, and
+// second-factor attempts per user. Repeated account and second-factor
+// failures are answered with growing delays and finally a temporary lockout;
+// an IP is only locked out, since many users may share it.
+type Guard struct {
+	repo   LoginThrottleRepo
+	sender Sender
+	logger logger.Logger
+}
+
+func NewGuard(repo LoginThrottleRepo, sender Sender, logger logger.Logger) *Guard {
+	return &Guard{repo: repo, sender: sender, logger: logger}
+}
+
+// Check returns a *domain.LoginBlockedError if the attempt must be rejected
+// without looking at the password.
+func (g *Guard) Check(ctx context.Context, email, ip string) error {
+	const op = "service.guard.Check"
+
+	accountKey, ipKey := accountThrottleKey(email), ipThrottleKey(ip)
+
+	throttles, err := g.repo.GetLoginThrottles(ctx, []string{accountKey, ipKey})
+	if err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	now := time.Now()
+	for _, throttle := range throttles {
+		if throttle.Key != accountKey {
+			if throttle.LockedUntil != nil && throttle.LockedUntil.After(now) {
+				return errutils.Wrap(op, &domain.LoginBlockedError{Err: domain.ErrTooManyAttempts, RetryAfter: throttle.LockedUntil.Sub(now)})
+			}
+			continue
+		}
+
+		if err = blocked(throttle, now); err != nil {
+			return errutils.Wrap(op, err)
+		}
+	}
+
+	return nil
+}
+
+// CheckMFA returns a *domain.LoginBlockedError if a second-factor attempt of
+// the user must be rejected without looking at the code.
+func (g *Guard) CheckMFA(ctx context.Context, userID uuid.UUID) error {
+	const op = "service.guard.CheckMFA"
+
+	throttles, err := g.repo.GetLoginThrottles(ctx, []string{mfaThrottleKey(userID)})
+	if err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	now := time.Now()
+	for _, throttle := range throttles {
+		if err = blocked(throttle, now); err != nil {
+			return errutils.Wrap(op, err)
+		}
+	}
+
+	return nil
+}
+
+// Failure records a failed attempt. When it locks the account, the owner is
+// notified in the background (if the account exists), so that the response
+// takes as long either way, and a *domain.LoginBlockedError is returned.
+func (g *Guard) Failure(ctx context.Context, email, ip string, accountExists bool) error {
+	const op = "service.guard.Failure"
+
+	now := time.Now()
+	resetBefore := now.Add(-failureWindow)
+
+	ipThrottle, err := g.repo.RecordLoginFailure(ctx, ipThrottleKey(ip), now, resetBefore)
+	if err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	if ipThrottle.Failures >= ipLockThreshold {
+		if err = g.repo.LockLogin(ctx, ipThrottle.Key, now.Add(lockDuration)); err != nil {
+			return errutils.Wrap(op, err)
+		}
+	}
+
+	accountThrottle, err := g.repo.RecordLoginFailure(ctx, accountThrottleKey(email), now, resetBefore)
+	if err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	if accountThrottle.Failures < accountLockThreshold {
+		return nil
+	}
+
+	lockedUntil := now.Add(lockDuration)
+	if err = g.repo.LockLogin(ctx, accountThrottle.Key, lockedUntil); err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	if accountExists {
+		go g.notifyLocked(context.WithoutCancel(ctx), email, ip, lockedUntil)
+	}
+
+	return errutils.Wrap(op, &domain.LoginBlockedError{Err: domain.ErrAccountLocked, RetryAfter: lockDuration})
+}
+
+// MFAFailure records a failed second-factor attempt. When it locks the user
+// out, a *domain.LoginBlockedError is returned.
+func (g *Guard) MFAFailure(ctx context.Context, userID uuid.UUID) error {
+	const op = "service.guard.MFAFailure"
+
+	now := time.Now()
+
+	throttle, err := g.repo.RecordLoginFailure(ctx, mfaThrottleKey(userID), now, now.Add(-failureWindow))
+	if err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	if throttle.Failures < mfaLockThreshold {
+		return nil
+	}
+
+	if err = g.repo.LockLogin(ctx, throttle.Key, now.Add(lockDuration)); err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	return errutils.Wrap(op, &domain.LoginBlockedError{Err: domain.ErrAccountLocked, RetryAfter: lockDuration})
+}
+
+// MFASuccess clears the user's second-factor failures.
+func (g *Guard) MFASuccess(ctx context.Context, userID uuid.UUID) error {
+	const op = "service.guard.MFASuccess"
+
+	if err := g.repo.ResetLoginFailures(ctx, mfaThrottleKey(userID)); err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Success clears the account's failures. The IP counter is left to expire,
+// otherwise one valid account would let an attacker reset it at will.
+func (g *Guard) Success(ctx context.Context, email string) error {
+	const op = "service.guard.Success"
+
+	if err := g.repo.ResetLoginFailures(ctx, accountThrottleKey(email)); err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (g *Guard) notifyLocked(ctx context.Context, email, ip string, lockedUntil time.Time) {
+	message := fmt.Sprintf(
+		"We detected %d failed sign-in attempts to your account, the last one from %s.\n\n"+
+			"Sign-in is blocked until %s. If this was not you, consider changing your password.",
+		accountLockThreshold, ip, lockedUntil.UTC().Format(time.RFC1123),
+	)
+
+	if err := g.sender.Send("Your account has been temporarily locked", message, email); err != nil {
+		g.logger.Error().Ctx(ctx).Err(err).Str("email", email).Msg("failed to send lockout notification")
+	}
+}
+
+// blocked returns the lockout or the pending delay of an account or
+// second-factor throttle, if any.
+func blocked(throttle domain.LoginThrottle, now time.Time) error {
+	if throttle.LockedUntil != nil && throttle.LockedUntil.After(now) {
+		return &domain.LoginBlockedError{Err: domain.ErrAccountLocked, RetryAfter: throttle.LockedUntil.Sub(now)}
+	}
+
+	if throttle.LastFailureAt.Before(now.Add(-failureWindow)) {
+		return nil
+	}
+
+	if next := throttle.LastFailureAt.Add(attemptDelay(throttle.Failures)); next.After(now) {
+		return &domain.LoginBlockedError{Err: domain.ErrTooManyAttempts, RetryAfter: next.Sub(now)}
+	}
+
+	return nil
+}
+
+// attemptDelay is the wait required after the given number of failures:
+// nothing for the first few, then doubling from baseDelay up to maxDelay.
+func attemptDelay(failures int) time.Duration {
+	if failures < freeAttempts {
+		return 0
+	}
+
+	delay := baseDelay
+	for i := freeAttempts; i < failures && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxDelay)
+}
+
+func accountThrottleKey(email string) string {
+	return "account:" + strings.ToLower(email)
+}
+
+func ipThrottleKey(ip string) string {
+	return "ip:" + ip
+}
+
+func mfaThrottleKey(userID uuid.UUID) string {
+	return "mfa:" + userID.String()
+}
@@ -0,0 +1,253 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/user/mocks"
+	"github.com/ilam072/event-calendar/internal/user/service"
+	"github.com/ilam072/event-calendar/pkg/logger"
+)
+
+func TestGuard_Check(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	throttleRepo := mocks.NewMockLoginThrottleRepo(ctrl)
+	sender := mocks.NewMockSender(ctrl)
+
+	g := service.NewGuard(throttleRepo, sender, &logger.DummyLogger{})
+
+	ctx := context.Background()
+	keys := []string{"account:test@mail.com", "ip:192.0.2.1"}
+
+	t.Run("no failures", func(t *testing.T) {
+		throttleRepo.EXPECT().GetLoginThrottles(ctx, keys).Return(nil, nil)
+
+		if err := g.Check(ctx, "Test@mail.com", "192.0.2.1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("few failures are free", func(t *testing.T) {
+		throttleRepo.EXPECT().GetLoginThrottles(ctx, keys).Return([]domain.LoginThrottle{
+			{Key: keys[0], Failures: 2, LastFailureAt: time.Now()},
+		}, nil)
+
+		if err := g.Check(ctx, "test@mail.com", "192.0.2.1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("progressive delay", func(t *testing.T) {
+		throttleRepo.EXPECT().GetLoginThrottles(ctx, keys).Return([]domain.LoginThrottle{
+			{Key: keys[0], Failures: 5, LastFailureAt: time.Now()},
+		}, nil)
+
+		err := g.Check(ctx, "test@mail.com", "192.0.2.1")
+
+		var blocked *domain.LoginBlockedError
+		if !errors.As(err, &blocked) || !errors.Is(err, domain.ErrTooManyAttempts) {
+			t.Fatalf("expected ErrTooManyAttempts, got %v", err)
+		}
+		if blocked.RetryAfter <= 3*time.Second || blocked.RetryAfter > 4*time.Second {
+			t.Fatalf("expected ~4s delay, got %v", blocked.RetryAfter)
+		}
+	})
+
+	t.Run("delay elapsed", func(t *testing.T) {
+		throttleRepo.EXPECT().GetLoginThrottles(ctx, keys).Return([]domain.LoginThrottle{
+			{Key: keys[0], Failures: 5, LastFailureAt: time.Now().Add(-5 * time.Second)},
+		}, nil)
+
+		if err := g.Check(ctx, "test@mail.com", "192.0.2.1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("account locked", func(t *testing.T) {
+		lockedUntil := time.Now().Add(10 * time.Minute)
+		throttleRepo.EXPECT().GetLoginThrottles(ctx, keys).Return([]domain.LoginThrottle{
+			{Key: keys[0], LastFailureAt: time.Now(), LockedUntil: &lockedUntil},
+		}, nil)
+
+		err := g.Check(ctx, "test@mail.com", "192.0.2.1")
+		if !errors.Is(err, domain.ErrAccountLocked) {
+			t.Fatalf("expected ErrAccountLocked, got %v", err)
+		}
+	})
+
+	t.Run("ip locked", func(t *testing.T) {
+		lockedUntil := time.Now().Add(10 * time.Minute)
+		throttleRepo.EXPECT().GetLoginThrottles(ctx, keys).Return([]domain.LoginThrottle{
+			{Key: keys[1], LastFailureAt: time.Now(), LockedUntil: &lockedUntil},
+		}, nil)
+
+		err := g.Check(ctx, "test@mail.com", "192.0.2.1")
+		if !errors.Is(err, domain.ErrTooManyAttempts) {
+			t.Fatalf("expected ErrTooManyAttempts, got %v", err)
+		}
+	})
+
+	t.Run("lock expired", func(t *testing.T) {
+		lockedUntil := time.Now().Add(-time.Minute)
+		throttleRepo.EXPECT().GetLoginThrottles(ctx, keys).Return([]domain.LoginThrottle{
+			{Key: keys[0], LastFailureAt: time.Now().Add(-20 * time.Minute), LockedUntil: &lockedUntil},
+		}, nil)
+
+		if err := g.Check(ctx, "test@mail.com", "192.0.2.1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestGuard_Failure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	throttleRepo := mocks.NewMockLoginThrottleRepo(ctrl)
+	sender := mocks.NewMockSender(ctrl)
+
+	g := service.NewGuard(throttleRepo, sender, &logger.DummyLogger{})
+
+	ctx := context.Background()
+
+	t.Run("below threshold", func(t *testing.T) {
+		throttleRepo.EXPECT().RecordLoginFailure(ctx, "ip:192.0.2.1", gomock.Any(), gomock.Any()).
+			Return(domain.LoginThrottle{Key: "ip:192.0.2.1", Failures: 1}, nil)
+		throttleRepo.EXPECT().RecordLoginFailure(ctx, "account:test@mail.com", gomock.Any(), gomock.Any()).
+			Return(domain.LoginThrottle{Key: "account:test@mail.com", Failures: 4}, nil)
+
+		if err := g.Failure(ctx, "test@mail.com", "192.0.2.1", true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("locks account and notifies owner", func(t *testing.T) {
+		throttleRepo.EXPECT().RecordLoginFailure(ctx, "ip:192.0.2.1", gomock.Any(), gomock.Any()).
+			Return(domain.LoginThrottle{Key: "ip:192.0.2.1", Failures: 10}, nil)
+		throttleRepo.EXPECT().RecordLoginFailure(ctx, "account:test@mail.com", gomock.Any(), gomock.Any()).
+			Return(domain.LoginThrottle{Key: "account:test@mail.com", Failures: 10}, nil)
+		throttleRepo.EXPECT().LockLogin(ctx, "account:test@mail.com", gomock.Any()).Return(nil)
+		sent := make(chan struct{})
+		sender.EXPECT().Send(gomock.Any(), gomock.Any(), "test@mail.com").
+			Do(func(string, string, string) { close(sent) }).
+			Return(nil)
+
+		err := g.Failure(ctx, "test@mail.com", "192.0.2.1", true)
+		if !errors.Is(err, domain.ErrAccountLocked) {
+			t.Fatalf("expected ErrAccountLocked, got %v", err)
+		}
+
+		select {
+		case <-sent:
+		case <-time.After(time.Second):
+			t.Fatalf("lockout notification was not sent")
+		}
+	})
+
+	t.Run("unknown account is locked silently", func(t *testing.T) {
+		throttleRepo.EXPECT().RecordLoginFailure(ctx, "ip:192.0.2.1", gomock.Any(), gomock.Any()).
+			Return(domain.LoginThrottle{Key: "ip:192.0.2.1", Failures: 11}, nil)
+		throttleRepo.EXPECT().RecordLoginFailure(ctx, "account:nobody@mail.com", gomock.Any(), gomock.Any()).
+			Return(domain.LoginThrottle{Key: "account:nobody@mail.com", Failures: 10}, nil)
+		throttleRepo.EXPECT().LockLogin(ctx, "account:nobody@mail.com", gomock.Any()).Return(nil)
+
+		err := g.Failure(ctx, "nobody@mail.com", "192.0.2.1", false)
+		if !errors.Is(err, domain.ErrAccountLocked) {
+			t.Fatalf("expected ErrAccountLocked, got %v", err)
+		}
+	})
+
+	t.Run("locks ip", func(t *testing.T) {
+		throttleRepo.EXPECT().RecordLoginFailure(ctx, "ip:192.0.2.1", gomock.Any(), gomock.Any()).
+			Return(domain.LoginThrottle{Key: "ip:192.0.2.1", Failures: 50}, nil)
+		throttleRepo.EXPECT().LockLogin(ctx, "ip:192.0.2.1", gomock.Any()).Return(nil)
+		throttleRepo.EXPECT().RecordLoginFailure(ctx, "account:test@mail.com", gomock.Any(), gomock.Any()).
+			Return(domain.LoginThrottle{Key: "account:test@mail.com", Failures: 1}, nil)
+
+		if err := g.Failure(ctx, "test@mail.com", "192.0.2.1", true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		throttleRepo.EXPECT().RecordLoginFailure(ctx, "ip:192.0.2.1", gomock.Any(), gomock.Any()).
+			Return(domain.LoginThrottle{}, errors.New("db down"))
+
+		if err := g.Failure(ctx, "test@mail.com", "192.0.2.1", true); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
+func TestGuard_MFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	throttleRepo := mocks.NewMockLoginThrottleRepo(ctrl)
+	sender := mocks.NewMockSender(ctrl)
+
+	g := service.NewGuard(throttleRepo, sender, &logger.DummyLogger{})
+
+	ctx := context.Background()
+	userID := uuid.New()
+	key := "mfa:" + userID.String()
+
+	t.Run("check delays after failures", func(t *testing.T) {
+		throttleRepo.EXPECT().GetLoginThrottles(ctx, []string{key}).Return([]domain.LoginThrottle{
+			{Key: key, Failures: 4, LastFailureAt: time.Now()},
+		}, nil)
+
+		err := g.CheckMFA(ctx, userID)
+		if !errors.Is(err, domain.ErrTooManyAttempts) {
+			t.Fatalf("expected ErrTooManyAttempts, got %v", err)
+		}
+	})
+
+	t.Run("check locked", func(t *testing.T) {
+		lockedUntil := time.Now().Add(10 * time.Minute)
+		throttleRepo.EXPECT().GetLoginThrottles(ctx, []string{key}).Return([]domain.LoginThrottle{
+			{Key: key, LastFailureAt: time.Now(), LockedUntil: &lockedUntil},
+		}, nil)
+
+		err := g.CheckMFA(ctx, userID)
+		if !errors.Is(err, domain.ErrAccountLocked) {
+			t.Fatalf("expected ErrAccountLocked, got %v", err)
+		}
+	})
+
+	t.Run("failure below threshold", func(t *testing.T) {
+		throttleRepo.EXPECT().RecordLoginFailure(ctx, key, gomock.Any(), gomock.Any()).
+			Return(domain.LoginThrottle{Key: key, Failures: 4}, nil)
+
+		if err := g.MFAFailure(ctx, userID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("failure locks without notifying", func(t *testing.T) {
+		throttleRepo.EXPECT().RecordLoginFailure(ctx, key, gomock.Any(), gomock.Any()).
+			Return(domain.LoginThrottle{Key: key, Failures: 5}, nil)
+		throttleRepo.EXPECT().LockLogin(ctx, key, gomock.Any()).Return(nil)
+
+		err := g.MFAFailure(ctx, userID)
+		if !errors.Is(err, domain.ErrAccountLocked) {
+			t.Fatalf("expected ErrAccountLocked, got %v", err)
+		}
+	})
+
+	t.Run("success resets", func(t *testing.T) {
+		throttleRepo.EXPECT().ResetLoginFailures(ctx, key).Return(nil)
+
+		if err := g.MFASuccess(ctx, userID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
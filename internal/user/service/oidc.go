@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/internal/user/repo"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// LoginOIDC signs in a user authenticated by an external identity provider.
+// The identity is matched by issuer and subject first, then linked to an
+// existing account whose owner has verified the same email, and otherwise a
+// new account is created. Accounts created this way get a random password.
+// An existing account with an unverified email is never linked: whoever
+// registered it may not own the email and would keep their password.
+func (u *User) LoginOIDC(ctx context.Context, identity dto.OIDCIdentity) (dto.LoginResponse, error) {
+	const op = "service.user.LoginOIDC"
+
+	user, err := u.repo.GetUserByIdentity(ctx, identity.Issuer, identity.Subject)
+	if err != nil {
+		if !errors.Is(err, repo.ErrUserNotFound) {
+			return dto.LoginResponse{}, errutils.Wrap(op, err)
+		}
+
+		if !identity.EmailVerified || identity.Email == "" {
+			return dto.LoginResponse{}, errutils.Wrap(op, domain.ErrEmailNotVerified)
+		}
+
+		user, err = u.userForIdentity(ctx, identity)
+		if err != nil {
+			return dto.LoginResponse{}, errutils.Wrap(op, err)
+		}
+
+		if err = u.repo.LinkIdentity(ctx, user.ID, identity.Issuer, identity.Subject); err != nil {
+			return dto.LoginResponse{}, errutils.Wrap(op, err)
+		}
+
+		// The provider vouches for the email, so pending invitations can be
+		// handed over.
+		if err = u.repo.MarkEmailVerified(ctx, user.ID); err != nil {
+			return dto.LoginResponse{}, errutils.Wrap(op, err)
+		}
+	}
+
+	resp, err := u.issueTokens(user)
+	if err != nil {
+		return dto.LoginResponse{}, errutils.Wrap(op, err)
+	}
+
+	return resp, nil
+}
+
+func (u *User) userForIdentity(ctx context.Context, identity dto.OIDCIdentity) (domain.User, error) {
+	user, err := u.repo.GetUserByEmail(ctx, identity.Email)
+	if err == nil {
+		return linkableUser(user)
+	}
+	if !errors.Is(err, repo.ErrUserNotFound) {
+		return domain.User{}, err
+	}
+
+	password := make([]byte, 32)
+	if _, err = rand.Read(password); err != nil {
+		return domain.User{}, err
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(password)), bcrypt.DefaultCost)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	user = domain.User{Email: identity.Email, PasswordHash: string(passwordHash)}
+	user.ID, err = u.repo.CreateUser(ctx, user)
+	if err != nil {
+		// The account may have been created concurrently by another login.
+		if errors.Is(err, repo.ErrUserExists) {
+			if user, err = u.repo.GetUserByEmail(ctx, identity.Email); err != nil {
+				return domain.User{}, err
+			}
+			return linkableUser(user)
+		}
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+// linkableUser lets an identity be linked to an existing account only once
+// its owner has verified the email.
+func linkableUser(user domain.User) (domain.User, error) {
+	if user.EmailVerifiedAt == nil {
+		return domain.User{}, domain.ErrUnverifiedAccountExists
+	}
+	return user, nil
+}
@@ -3,6 +3,7 @@ package service_test
 import (
 	"context"
 	"errors"
+	jwtlib "github.com/golang-jwt/jwt/v5"
 	"github.com/ilam072/event-calendar/internal/user/mocks"
 	"go.uber.org/mock/gomock"
 	"testing"
@@ -13,7 +14,11 @@ import (
 	"github.com/ilam072/event-calendar/internal/types/dto"
 	"github.com/ilam072/event-calendar/internal/user/repo"
 	"github.com/ilam072/event-calendar/internal/user/service"
+	"github.com/ilam072/event-calendar/pkg/jwt"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"github.com/ilam072/event-calendar/pkg/totp"
 	"golang.org/x/crypto/bcrypt"
+	"strings"
 )
 
 func TestUser_Register(t *testing.T) {
@@ -22,8 +27,10 @@ func TestUser_Register(t *testing.T) {
 
 	userRepo := mocks.NewMockUserRepo(ctrl)
 	tokenManager := mocks.NewMockTokenManager(ctrl)
+	guard := mocks.NewMockLoginGuard(ctrl)
+	sender := mocks.NewMockSender(ctrl)
 
-	s := service.NewUser(userRepo, tokenManager, time.Second*10)
+	s := service.NewUser(userRepo, tokenManager, guard, sender, time.Second*10, time.Minute, "Event Calendar", "https://calendar.example.com", &logger.DummyLogger{})
 
 	ctx := context.Background()
 
@@ -32,16 +39,32 @@ func TestUser_Register(t *testing.T) {
 		Password: "123456",
 	}
 
-	t.Run("success", func(t *testing.T) {
+	t.Run("success sends verification link", func(t *testing.T) {
+		userID := uuid.New()
+		sent := make(chan string, 1)
+
 		userRepo.
 			EXPECT().
 			CreateUser(ctx, gomock.Any()).
-			Return(uuid.New(), nil)
+			Return(userID, nil)
+		tokenManager.EXPECT().NewEmailVerificationToken(userID.String(), gomock.Any()).Return("VERIFY", nil)
+		sender.EXPECT().Send(gomock.Any(), gomock.Any(), req.Email).
+			Do(func(_ string, message string, _ string) { sent <- message }).
+			Return(nil)
 
 		_, err := s.Register(ctx, req)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
+
+		select {
+		case message := <-sent:
+			if !strings.Contains(message, "https://calendar.example.com/auth/verify-email?token=VERIFY") {
+				t.Fatalf("verification link missing from %q", message)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("verification email was not sent")
+		}
 	})
 
 	t.Run("user exists", func(t *testing.T) {
@@ -57,14 +80,54 @@ func TestUser_Register(t *testing.T) {
 	})
 }
 
+func TestUser_VerifyEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepo(ctrl)
+	tokenManager := mocks.NewMockTokenManager(ctrl)
+
+	s := service.NewUser(userRepo, tokenManager, mocks.NewMockLoginGuard(ctrl), mocks.NewMockSender(ctrl), time.Second*10, time.Minute, "Event Calendar", "https://calendar.example.com", &logger.DummyLogger{})
+
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		tokenManager.EXPECT().ParseEmailVerificationToken("VERIFY").Return(&jwt.TokenClaims{UserID: userID.String()}, nil)
+		userRepo.EXPECT().MarkEmailVerified(ctx, userID).Return(nil)
+
+		if err := s.VerifyEmail(ctx, "VERIFY"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		tokenManager.EXPECT().ParseEmailVerificationToken("BAD").Return(nil, errors.New("invalid token"))
+
+		if err := s.VerifyEmail(ctx, "BAD"); !errors.Is(err, domain.ErrInvalidVerificationLink) {
+			t.Fatalf("expected ErrInvalidVerificationLink, got %v", err)
+		}
+	})
+
+	t.Run("deleted user", func(t *testing.T) {
+		tokenManager.EXPECT().ParseEmailVerificationToken("VERIFY").Return(&jwt.TokenClaims{UserID: userID.String()}, nil)
+		userRepo.EXPECT().MarkEmailVerified(ctx, userID).Return(repo.ErrUserNotFound)
+
+		if err := s.VerifyEmail(ctx, "VERIFY"); !errors.Is(err, domain.ErrInvalidVerificationLink) {
+			t.Fatalf("expected ErrInvalidVerificationLink, got %v", err)
+		}
+	})
+}
+
 func TestUser_Login(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	userRepo := mocks.NewMockUserRepo(ctrl)
 	tokenManager := mocks.NewMockTokenManager(ctrl)
+	guard := mocks.NewMockLoginGuard(ctrl)
 
-	s := service.NewUser(userRepo, tokenManager, time.Second*10)
+	s := service.NewUser(userRepo, tokenManager, guard, mocks.NewMockSender(ctrl), time.Second*10, time.Minute, "Event Calendar", "https://calendar.example.com", &logger.DummyLogger{})
 
 	ctx := context.Background()
 
@@ -81,33 +144,90 @@ func TestUser_Login(t *testing.T) {
 		Password: "123456",
 	}
 
+	const ip = "192.0.2.1"
+
 	t.Run("success", func(t *testing.T) {
+		guard.EXPECT().Check(ctx, req.Email, ip).Return(nil)
+
 		userRepo.
 			EXPECT().
 			GetUserByEmail(ctx, req.Email).
 			Return(dbUser, nil)
 
+		guard.EXPECT().Success(ctx, req.Email).Return(nil)
+
 		tokenManager.
 			EXPECT().
 			NewToken(dbUser.ID.String(), gomock.Any()).
 			Return("TOKEN_123", nil)
 
-		token, err := s.Login(ctx, req)
+		resp, err := s.Login(ctx, req, ip)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
-		if token != "TOKEN_123" {
-			t.Fatalf("unexpected token: %s", token)
+		if resp.Token != "TOKEN_123" || resp.MFARequired {
+			t.Fatalf("unexpected response: %+v", resp)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		disabledAt := time.Now()
+		disabledUser := dbUser
+		disabledUser.DisabledAt = &disabledAt
+
+		guard.EXPECT().Check(ctx, req.Email, ip).Return(nil)
+
+		userRepo.
+			EXPECT().
+			GetUserByEmail(ctx, req.Email).
+			Return(disabledUser, nil)
+
+		guard.EXPECT().Success(ctx, req.Email).Return(nil)
+
+		_, err := s.Login(ctx, req, ip)
+		if !errors.Is(err, domain.ErrUserDisabled) {
+			t.Fatalf("expected ErrUserDisabled, got %v", err)
+		}
+	})
+
+	t.Run("mfa required", func(t *testing.T) {
+		mfaUser := dbUser
+		mfaUser.TOTPEnabled = true
+
+		guard.EXPECT().Check(ctx, req.Email, ip).Return(nil)
+
+		userRepo.
+			EXPECT().
+			GetUserByEmail(ctx, req.Email).
+			Return(mfaUser, nil)
+
+		guard.EXPECT().Success(ctx, req.Email).Return(nil)
+
+		tokenManager.
+			EXPECT().
+			NewMFAToken(dbUser.ID.String(), time.Minute).
+			Return("MFA_TOKEN", nil)
+
+		resp, err := s.Login(ctx, req, ip)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !resp.MFARequired || resp.MFAToken != "MFA_TOKEN" || resp.Token != "" {
+			t.Fatalf("unexpected response: %+v", resp)
 		}
 	})
 
 	t.Run("user not found", func(t *testing.T) {
+		guard.EXPECT().Check(ctx, req.Email, ip).Return(nil)
+
 		userRepo.
 			EXPECT().
 			GetUserByEmail(ctx, req.Email).
 			Return(domain.User{}, repo.ErrUserNotFound)
 
-		_, err := s.Login(ctx, req)
+		guard.EXPECT().Failure(ctx, req.Email, ip, false).Return(nil)
+
+		_, err := s.Login(ctx, req, ip)
 		if !errors.Is(err, domain.ErrInvalidCredentials) {
 			t.Fatalf("expected ErrInvalidCredentials, got: %v", err)
 		}
@@ -117,31 +237,391 @@ func TestUser_Login(t *testing.T) {
 		invalidDBUser := dbUser
 		invalidDBUser.PasswordHash = "WRONG"
 
+		guard.EXPECT().Check(ctx, req.Email, ip).Return(nil)
+
 		userRepo.
 			EXPECT().
 			GetUserByEmail(ctx, req.Email).
 			Return(invalidDBUser, nil)
 
-		_, err := s.Login(ctx, req)
+		guard.EXPECT().Failure(ctx, req.Email, ip, true).Return(nil)
+
+		_, err := s.Login(ctx, req, ip)
 		if !errors.Is(err, domain.ErrInvalidCredentials) {
 			t.Fatalf("expected ErrInvalidCredentials, got: %v", err)
 		}
 	})
 
+	t.Run("failure locks account", func(t *testing.T) {
+		invalidDBUser := dbUser
+		invalidDBUser.PasswordHash = "WRONG"
+
+		guard.EXPECT().Check(ctx, req.Email, ip).Return(nil)
+
+		userRepo.
+			EXPECT().
+			GetUserByEmail(ctx, req.Email).
+			Return(invalidDBUser, nil)
+
+		guard.
+			EXPECT().
+			Failure(ctx, req.Email, ip, true).
+			Return(&domain.LoginBlockedError{Err: domain.ErrAccountLocked, RetryAfter: time.Minute})
+
+		_, err := s.Login(ctx, req, ip)
+		if !errors.Is(err, domain.ErrAccountLocked) {
+			t.Fatalf("expected ErrAccountLocked, got: %v", err)
+		}
+	})
+
+	t.Run("blocked by guard", func(t *testing.T) {
+		guard.
+			EXPECT().
+			Check(ctx, req.Email, ip).
+			Return(&domain.LoginBlockedError{Err: domain.ErrTooManyAttempts, RetryAfter: time.Second})
+
+		_, err := s.Login(ctx, req, ip)
+
+		var blocked *domain.LoginBlockedError
+		if !errors.As(err, &blocked) || blocked.RetryAfter != time.Second {
+			t.Fatalf("expected LoginBlockedError, got: %v", err)
+		}
+	})
+
 	t.Run("token gen failed", func(t *testing.T) {
+		guard.EXPECT().Check(ctx, req.Email, ip).Return(nil)
+
 		userRepo.
 			EXPECT().
 			GetUserByEmail(ctx, req.Email).
 			Return(dbUser, nil)
 
+		guard.EXPECT().Success(ctx, req.Email).Return(nil)
+
 		tokenManager.
 			EXPECT().
 			NewToken(dbUser.ID.String(), gomock.Any()).
 			Return("", errors.New("token error"))
 
-		_, err := s.Login(ctx, req)
+		_, err := s.Login(ctx, req, ip)
 		if err == nil {
 			t.Fatal("expected error, got nil")
 		}
 	})
 }
+
+func TestUser_VerifyMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepo(ctrl)
+	tokenManager := mocks.NewMockTokenManager(ctrl)
+	guard := mocks.NewMockLoginGuard(ctrl)
+
+	s := service.NewUser(userRepo, tokenManager, guard, mocks.NewMockSender(ctrl), time.Second*10, time.Minute, "Event Calendar", "https://calendar.example.com", &logger.DummyLogger{})
+
+	ctx := context.Background()
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("failed to generate secret: %v", err)
+	}
+
+	dbUser := domain.User{
+		ID:          uuid.New(),
+		Email:       "test@mail.com",
+		TOTPSecret:  secret,
+		TOTPEnabled: true,
+	}
+	expiresAt := time.Now().Add(time.Minute)
+	claims := &jwt.TokenClaims{
+		RegisteredClaims: jwtlib.RegisteredClaims{ID: "jti-1", ExpiresAt: jwtlib.NewNumericDate(expiresAt)},
+		UserID:           dbUser.ID.String(),
+		Purpose:          jwt.PurposeMFA,
+	}
+
+	t.Run("success with totp code", func(t *testing.T) {
+		now := time.Now()
+		code, err := totp.GenerateCode(secret, now)
+		if err != nil {
+			t.Fatalf("failed to generate code: %v", err)
+		}
+
+		tokenManager.EXPECT().ParseMFAToken("MFA_TOKEN").Return(claims, nil)
+		guard.EXPECT().CheckMFA(ctx, dbUser.ID).Return(nil)
+		userRepo.EXPECT().GetUserByID(ctx, dbUser.ID).Return(dbUser, nil)
+		userRepo.EXPECT().UseTOTPStep(ctx, dbUser.ID, gomock.Any()).Return(nil)
+		userRepo.EXPECT().UseMFAToken(ctx, "jti-1", claims.ExpiresAt.Time).Return(nil)
+		guard.EXPECT().MFASuccess(ctx, dbUser.ID).Return(nil)
+		tokenManager.EXPECT().NewToken(dbUser.ID.String(), gomock.Any()).Return("TOKEN_123", nil)
+
+		token, err := s.VerifyMFA(ctx, dto.VerifyMFA{MFAToken: "MFA_TOKEN", Code: code})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if token != "TOKEN_123" {
+			t.Fatalf("unexpected token: %s", token)
+		}
+	})
+
+	t.Run("success with recovery code", func(t *testing.T) {
+		tokenManager.EXPECT().ParseMFAToken("MFA_TOKEN").Return(claims, nil)
+		guard.EXPECT().CheckMFA(ctx, dbUser.ID).Return(nil)
+		userRepo.EXPECT().GetUserByID(ctx, dbUser.ID).Return(dbUser, nil)
+		userRepo.EXPECT().UseRecoveryCode(ctx, dbUser.ID, gomock.Any()).Return(nil)
+		userRepo.EXPECT().UseMFAToken(ctx, "jti-1", gomock.Any()).Return(nil)
+		guard.EXPECT().MFASuccess(ctx, dbUser.ID).Return(nil)
+		tokenManager.EXPECT().NewToken(dbUser.ID.String(), gomock.Any()).Return("TOKEN_123", nil)
+
+		_, err := s.VerifyMFA(ctx, dto.VerifyMFA{MFAToken: "MFA_TOKEN", Code: "abcde-fghij"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		tokenManager.EXPECT().ParseMFAToken("MFA_TOKEN").Return(claims, nil)
+		guard.EXPECT().CheckMFA(ctx, dbUser.ID).Return(nil)
+		userRepo.EXPECT().GetUserByID(ctx, dbUser.ID).Return(dbUser, nil)
+		userRepo.EXPECT().UseRecoveryCode(ctx, dbUser.ID, gomock.Any()).Return(repo.ErrRecoveryCodeNotFound)
+		guard.EXPECT().MFAFailure(ctx, dbUser.ID).Return(nil)
+
+		_, err := s.VerifyMFA(ctx, dto.VerifyMFA{MFAToken: "MFA_TOKEN", Code: "000000"})
+		if !errors.Is(err, domain.ErrInvalidMFACode) {
+			t.Fatalf("expected ErrInvalidMFACode, got: %v", err)
+		}
+	})
+
+	t.Run("replayed totp code", func(t *testing.T) {
+		code, _ := totp.GenerateCode(secret, time.Now())
+
+		tokenManager.EXPECT().ParseMFAToken("MFA_TOKEN").Return(claims, nil)
+		guard.EXPECT().CheckMFA(ctx, dbUser.ID).Return(nil)
+		userRepo.EXPECT().GetUserByID(ctx, dbUser.ID).Return(dbUser, nil)
+		userRepo.EXPECT().UseTOTPStep(ctx, dbUser.ID, gomock.Any()).Return(repo.ErrTOTPStepUsed)
+		userRepo.EXPECT().UseRecoveryCode(ctx, dbUser.ID, gomock.Any()).Return(repo.ErrRecoveryCodeNotFound)
+		guard.EXPECT().MFAFailure(ctx, dbUser.ID).Return(nil)
+
+		_, err := s.VerifyMFA(ctx, dto.VerifyMFA{MFAToken: "MFA_TOKEN", Code: code})
+		if !errors.Is(err, domain.ErrInvalidMFACode) {
+			t.Fatalf("expected ErrInvalidMFACode, got: %v", err)
+		}
+	})
+
+	t.Run("lockout after failures", func(t *testing.T) {
+		tokenManager.EXPECT().ParseMFAToken("MFA_TOKEN").Return(claims, nil)
+		guard.EXPECT().CheckMFA(ctx, dbUser.ID).Return(nil)
+		userRepo.EXPECT().GetUserByID(ctx, dbUser.ID).Return(dbUser, nil)
+		userRepo.EXPECT().UseRecoveryCode(ctx, dbUser.ID, gomock.Any()).Return(repo.ErrRecoveryCodeNotFound)
+		guard.EXPECT().MFAFailure(ctx, dbUser.ID).
+			Return(&domain.LoginBlockedError{Err: domain.ErrAccountLocked, RetryAfter: time.Minute})
+
+		_, err := s.VerifyMFA(ctx, dto.VerifyMFA{MFAToken: "MFA_TOKEN", Code: "000000"})
+		if !errors.Is(err, domain.ErrAccountLocked) {
+			t.Fatalf("expected ErrAccountLocked, got: %v", err)
+		}
+	})
+
+	t.Run("throttled", func(t *testing.T) {
+		tokenManager.EXPECT().ParseMFAToken("MFA_TOKEN").Return(claims, nil)
+		guard.EXPECT().CheckMFA(ctx, dbUser.ID).
+			Return(&domain.LoginBlockedError{Err: domain.ErrTooManyAttempts, RetryAfter: time.Second})
+
+		_, err := s.VerifyMFA(ctx, dto.VerifyMFA{MFAToken: "MFA_TOKEN", Code: "000000"})
+		if !errors.Is(err, domain.ErrTooManyAttempts) {
+			t.Fatalf("expected ErrTooManyAttempts, got: %v", err)
+		}
+	})
+
+	t.Run("mfa token already used", func(t *testing.T) {
+		tokenManager.EXPECT().ParseMFAToken("MFA_TOKEN").Return(claims, nil)
+		guard.EXPECT().CheckMFA(ctx, dbUser.ID).Return(nil)
+		userRepo.EXPECT().GetUserByID(ctx, dbUser.ID).Return(dbUser, nil)
+		userRepo.EXPECT().UseRecoveryCode(ctx, dbUser.ID, gomock.Any()).Return(nil)
+		userRepo.EXPECT().UseMFAToken(ctx, "jti-1", gomock.Any()).Return(repo.ErrMFATokenUsed)
+
+		_, err := s.VerifyMFA(ctx, dto.VerifyMFA{MFAToken: "MFA_TOKEN", Code: "abcde-fghij"})
+		if !errors.Is(err, domain.ErrInvalidCredentials) {
+			t.Fatalf("expected ErrInvalidCredentials, got: %v", err)
+		}
+	})
+
+	t.Run("invalid mfa token", func(t *testing.T) {
+		tokenManager.EXPECT().ParseMFAToken("BAD").Return(nil, errors.New("invalid token"))
+
+		_, err := s.VerifyMFA(ctx, dto.VerifyMFA{MFAToken: "BAD", Code: "000000"})
+		if !errors.Is(err, domain.ErrInvalidCredentials) {
+			t.Fatalf("expected ErrInvalidCredentials, got: %v", err)
+		}
+	})
+}
+
+func TestUser_TOTPEnrollment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepo(ctrl)
+	tokenManager := mocks.NewMockTokenManager(ctrl)
+	guard := mocks.NewMockLoginGuard(ctrl)
+
+	s := service.NewUser(userRepo, tokenManager, guard, mocks.NewMockSender(ctrl), time.Second*10, time.Minute, "Event Calendar", "https://calendar.example.com", &logger.DummyLogger{})
+
+	ctx := context.Background()
+	dbUser := domain.User{ID: uuid.New(), Email: "test@mail.com"}
+
+	t.Run("enroll", func(t *testing.T) {
+		userRepo.EXPECT().GetUserByID(ctx, dbUser.ID).Return(dbUser, nil)
+		userRepo.EXPECT().SetTOTPSecret(ctx, dbUser.ID, gomock.Any()).Return(nil)
+
+		enrollment, err := s.EnrollTOTP(ctx, dbUser.ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if enrollment.Secret == "" || enrollment.URI == "" {
+			t.Fatalf("unexpected enrollment: %+v", enrollment)
+		}
+	})
+
+	t.Run("enroll when already enabled", func(t *testing.T) {
+		enabled := dbUser
+		enabled.TOTPEnabled = true
+
+		userRepo.EXPECT().GetUserByID(ctx, dbUser.ID).Return(enabled, nil)
+
+		_, err := s.EnrollTOTP(ctx, dbUser.ID)
+		if !errors.Is(err, domain.ErrTOTPEnabled) {
+			t.Fatalf("expected ErrTOTPEnabled, got: %v", err)
+		}
+	})
+
+	t.Run("confirm", func(t *testing.T) {
+		secret, _ := totp.GenerateSecret()
+		code, _ := totp.GenerateCode(secret, time.Now())
+
+		enrolled := dbUser
+		enrolled.TOTPSecret = secret
+
+		userRepo.EXPECT().GetUserByID(ctx, dbUser.ID).Return(enrolled, nil)
+		userRepo.EXPECT().UseTOTPStep(ctx, dbUser.ID, gomock.Any()).Return(nil)
+		userRepo.EXPECT().EnableTOTP(ctx, dbUser.ID, gomock.Len(10)).Return(nil)
+
+		codes, err := s.ConfirmTOTP(ctx, dbUser.ID, code)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(codes.Codes) != 10 {
+			t.Fatalf("expected 10 recovery codes, got %d", len(codes.Codes))
+		}
+	})
+
+	t.Run("confirm without enrollment", func(t *testing.T) {
+		userRepo.EXPECT().GetUserByID(ctx, dbUser.ID).Return(dbUser, nil)
+
+		_, err := s.ConfirmTOTP(ctx, dbUser.ID, "123456")
+		if !errors.Is(err, domain.ErrTOTPNotEnrolled) {
+			t.Fatalf("expected ErrTOTPNotEnrolled, got: %v", err)
+		}
+	})
+}
+
+func TestUser_LoginOIDC(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepo(ctrl)
+	tokenManager := mocks.NewMockTokenManager(ctrl)
+	guard := mocks.NewMockLoginGuard(ctrl)
+
+	s := service.NewUser(userRepo, tokenManager, guard, mocks.NewMockSender(ctrl), time.Second*10, time.Minute, "Event Calendar", "https://calendar.example.com", &logger.DummyLogger{})
+
+	ctx := context.Background()
+
+	identity := dto.OIDCIdentity{
+		Issuer:        "https://idp.example.com",
+		Subject:       "sub-1",
+		Email:         "test@mail.com",
+		EmailVerified: true,
+	}
+	verifiedAt := time.Now()
+	dbUser := domain.User{ID: uuid.New(), Email: identity.Email, EmailVerifiedAt: &verifiedAt}
+
+	t.Run("linked identity", func(t *testing.T) {
+		userRepo.EXPECT().GetUserByIdentity(ctx, identity.Issuer, identity.Subject).Return(dbUser, nil)
+		tokenManager.EXPECT().NewToken(dbUser.ID.String(), time.Second*10).Return("token", nil)
+
+		resp, err := s.LoginOIDC(ctx, identity)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Token != "token" {
+			t.Fatalf("expected token, got %+v", resp)
+		}
+	})
+
+	t.Run("links existing user by email", func(t *testing.T) {
+		userRepo.EXPECT().GetUserByIdentity(ctx, identity.Issuer, identity.Subject).Return(domain.User{}, repo.ErrUserNotFound)
+		userRepo.EXPECT().GetUserByEmail(ctx, identity.Email).Return(dbUser, nil)
+		userRepo.EXPECT().LinkIdentity(ctx, dbUser.ID, identity.Issuer, identity.Subject).Return(nil)
+		userRepo.EXPECT().MarkEmailVerified(ctx, dbUser.ID).Return(nil)
+		tokenManager.EXPECT().NewToken(dbUser.ID.String(), time.Second*10).Return("token", nil)
+
+		if _, err := s.LoginOIDC(ctx, identity); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("does not link unverified user", func(t *testing.T) {
+		unverified := dbUser
+		unverified.EmailVerifiedAt = nil
+
+		userRepo.EXPECT().GetUserByIdentity(ctx, identity.Issuer, identity.Subject).Return(domain.User{}, repo.ErrUserNotFound)
+		userRepo.EXPECT().GetUserByEmail(ctx, identity.Email).Return(unverified, nil)
+
+		_, err := s.LoginOIDC(ctx, identity)
+		if !errors.Is(err, domain.ErrUnverifiedAccountExists) {
+			t.Fatalf("expected ErrUnverifiedAccountExists, got %v", err)
+		}
+	})
+
+	t.Run("creates new user", func(t *testing.T) {
+		newID := uuid.New()
+		userRepo.EXPECT().GetUserByIdentity(ctx, identity.Issuer, identity.Subject).Return(domain.User{}, repo.ErrUserNotFound)
+		userRepo.EXPECT().GetUserByEmail(ctx, identity.Email).Return(domain.User{}, repo.ErrUserNotFound)
+		userRepo.EXPECT().CreateUser(ctx, gomock.Any()).Return(newID, nil)
+		userRepo.EXPECT().LinkIdentity(ctx, newID, identity.Issuer, identity.Subject).Return(nil)
+		userRepo.EXPECT().MarkEmailVerified(ctx, newID).Return(nil)
+		tokenManager.EXPECT().NewToken(newID.String(), time.Second*10).Return("token", nil)
+
+		if _, err := s.LoginOIDC(ctx, identity); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("unverified email", func(t *testing.T) {
+		unverified := identity
+		unverified.EmailVerified = false
+
+		userRepo.EXPECT().GetUserByIdentity(ctx, identity.Issuer, identity.Subject).Return(domain.User{}, repo.ErrUserNotFound)
+
+		_, err := s.LoginOIDC(ctx, unverified)
+		if !errors.Is(err, domain.ErrEmailNotVerified) {
+			t.Fatalf("expected ErrEmailNotVerified, got %v", err)
+		}
+	})
+
+	t.Run("mfa required", func(t *testing.T) {
+		mfaUser := dbUser
+		mfaUser.TOTPEnabled = true
+
+		userRepo.EXPECT().GetUserByIdentity(ctx, identity.Issuer, identity.Subject).Return(mfaUser, nil)
+		tokenManager.EXPECT().NewMFAToken(mfaUser.ID.String(), time.Minute).Return("mfa-token", nil)
+
+		resp, err := s.LoginOIDC(ctx, identity)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !resp.MFARequired || resp.MFAToken != "mfa-token" {
+			t.Fatalf("expected mfa challenge, got %+v", resp)
+		}
+	})
+}
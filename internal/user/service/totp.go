@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilam072/event-calendar/internal/types/domain"
+	"github.com/ilam072/event-calendar/internal/types/dto"
+	"github.com/ilam072/event-calendar/internal/user/repo"
+	"github.com/ilam072/event-calendar/pkg/errutils"
+	"github.com/ilam072/event-calendar/pkg/totp"
+	"strings"
+	"time"
+)
+
+const (
+	recoveryCodesCount = 10
+	recoveryCodeSize   = 10
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// EnrollTOTP generates a new secret for the user. Two-factor authentication
+// stays disabled until the secret is confirmed with ConfirmTOTP.
+func (u *User) EnrollTOTP(ctx context.Context, userID uuid.UUID) (dto.TOTPEnrollment, error) {
+	const op = "service.user.EnrollTOTP"
+
+	user, err := u.getUser(ctx, userID)
+	if err != nil {
+		return dto.TOTPEnrollment{}, errutils.Wrap(op, err)
+	}
+
+	if user.TOTPEnabled {
+		return dto.TOTPEnrollment{}, errutils.Wrap(op, domain.ErrTOTPEnabled)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return dto.TOTPEnrollment{}, errutils.Wrap(op, err)
+	}
+
+	if err = u.repo.SetTOTPSecret(ctx, userID, secret); err != nil {
+		return dto.TOTPEnrollment{}, errutils.Wrap(op, err)
+	}
+
+	return dto.TOTPEnrollment{
+		Secret: secret,
+		URI:    totp.URI(u.totpIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication once the user proves they
+// can generate codes, and returns a fresh set of one-time recovery codes.
+func (u *User) ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) (dto.RecoveryCodes, error) {
+	const op = "service.user.ConfirmTOTP"
+
+	user, err := u.getUser(ctx, userID)
+	if err != nil {
+		return dto.RecoveryCodes{}, errutils.Wrap(op, err)
+	}
+
+	if user.TOTPEnabled {
+		return dto.RecoveryCodes{}, errutils.Wrap(op, domain.ErrTOTPEnabled)
+	}
+
+	if user.TOTPSecret == "" {
+		return dto.RecoveryCodes{}, errutils.Wrap(op, domain.ErrTOTPNotEnrolled)
+	}
+
+	if err = u.checkTOTPCode(ctx, user, code); err != nil {
+		return dto.RecoveryCodes{}, errutils.Wrap(op, err)
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return dto.RecoveryCodes{}, errutils.Wrap(op, err)
+	}
+
+	if err = u.repo.EnableTOTP(ctx, userID, hashes); err != nil {
+		return dto.RecoveryCodes{}, errutils.Wrap(op, err)
+	}
+
+	return dto.RecoveryCodes{Codes: codes}, nil
+}
+
+func (u *User) DisableTOTP(ctx context.Context, userID uuid.UUID, code string) error {
+	const op = "service.user.DisableTOTP"
+
+	user, err := u.getUser(ctx, userID)
+	if err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	if !user.TOTPEnabled {
+		return errutils.Wrap(op, domain.ErrTOTPNotEnrolled)
+	}
+
+	if err = u.checkSecondFactor(ctx, user, code); err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	if err = u.repo.DisableTOTP(ctx, userID); err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// checkSecondFactor accepts either a current TOTP code or an unused
+// recovery code, which is consumed.
+func (u *User) checkSecondFactor(ctx context.Context, user domain.User, code string) error {
+	if err := u.checkTOTPCode(ctx, user, code); !errors.Is(err, domain.ErrInvalidMFACode) {
+		return err
+	}
+
+	if err := u.repo.UseRecoveryCode(ctx, user.ID, hashRecoveryCode(code)); err != nil {
+		if errors.Is(err, repo.ErrRecoveryCodeNotFound) {
+			return domain.ErrInvalidMFACode
+		}
+		return err
+	}
+
+	return nil
+}
+
+// checkTOTPCode accepts a current TOTP code that is newer than the last one
+// accepted, so an observed code cannot be replayed while it is still valid.
+func (u *User) checkTOTPCode(ctx context.Context, user domain.User, code string) error {
+	step, ok := totp.Match(code, user.TOTPSecret, time.Now())
+	if !ok {
+		return domain.ErrInvalidMFACode
+	}
+
+	if err := u.repo.UseTOTPStep(ctx, user.ID, step); err != nil {
+		if errors.Is(err, repo.ErrTOTPStepUsed) {
+			return domain.ErrInvalidMFACode
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (u *User) getUser(ctx context.Context, userID uuid.UUID) (domain.User, error) {
+	user, err := u.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrUserNotFound) {
+			return domain.User{}, domain.ErrUserNotFound
+		}
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([]string, 0, recoveryCodesCount)
+
+	for i := 0; i < recoveryCodesCount; i++ {
+		raw := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+
+		code := strings.ToLower(recoveryEncoding.EncodeToString(raw))[:recoveryCodeSize]
+		code = code[:recoveryCodeSize/2] + "-" + code[recoveryCodeSize/2:]
+
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode normalizes the code so it is accepted regardless of case
+// and grouping. Codes are random, so a fast hash is sufficient.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
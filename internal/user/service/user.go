@@ -3,36 +3,81 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/ilam072/event-calendar/internal/types/domain"
 	"github.com/ilam072/event-calendar/internal/types/dto"
 	"github.com/ilam072/event-calendar/internal/user/repo"
 	"github.com/ilam072/event-calendar/pkg/errutils"
+	"github.com/ilam072/event-calendar/pkg/jwt"
+	"github.com/ilam072/event-calendar/pkg/logger"
 	"golang.org/x/crypto/bcrypt"
+	"net/url"
+	"strings"
 	"time"
 )
 
 //go:generate mockgen -source=user.go -destination=../mocks/service_mocks.go -package=mocks
 type UserRepo interface {
 	CreateUser(ctx context.Context, user domain.User) (uuid.UUID, error)
+	GetUserByID(ctx context.Context, userID uuid.UUID) (domain.User, error)
 	GetUserByEmail(ctx context.Context, email string) (domain.User, error)
+	SetTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error
+	EnableTOTP(ctx context.Context, userID uuid.UUID, recoveryCodeHashes []string) error
+	DisableTOTP(ctx context.Context, userID uuid.UUID) error
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error
+	UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) error
+	UseMFAToken(ctx context.Context, jti string, expiresAt time.Time) error
+	GetUserByIdentity(ctx context.Context, issuer, subject string) (domain.User, error)
+	LinkIdentity(ctx context.Context, userID uuid.UUID, issuer, subject string) error
+	MarkEmailVerified(ctx context.Context, userID uuid.UUID) error
 }
 
 type TokenManager interface {
 	NewToken(userID string, ttl time.Duration) (string, error)
+	NewMFAToken(userID string, ttl time.Duration) (string, error)
+	ParseMFAToken(tokenStr string) (*jwt.TokenClaims, error)
+	NewEmailVerificationToken(userID string, ttl time.Duration) (string, error)
+	ParseEmailVerificationToken(tokenStr string) (*jwt.TokenClaims, error)
 }
 
+type LoginGuard interface {
+	Check(ctx context.Context, email, ip string) error
+	Failure(ctx context.Context, email, ip string, accountExists bool) error
+	Success(ctx context.Context, email string) error
+	CheckMFA(ctx context.Context, userID uuid.UUID) error
+	MFAFailure(ctx context.Context, userID uuid.UUID) error
+	MFASuccess(ctx context.Context, userID uuid.UUID) error
+}
+
+// emailVerificationTTL is how long the link sent on sign-up stays valid.
+const emailVerificationTTL = 72 * time.Hour
+
 type User struct {
-	repo     UserRepo
-	manager  TokenManager
-	tokenTTL time.Duration
+	repo        UserRepo
+	manager     TokenManager
+	guard       LoginGuard
+	sender      Sender
+	tokenTTL    time.Duration
+	mfaTokenTTL time.Duration
+	totpIssuer  string
+	baseURL     string
+	logger      logger.Logger
 }
 
-func NewUser(repo UserRepo, manager TokenManager, tokenTTL time.Duration) *User {
+// NewUser creates the service. baseURL is the public address of the API used
+// to build the email verification links.
+func NewUser(repo UserRepo, manager TokenManager, guard LoginGuard, sender Sender, tokenTTL time.Duration, mfaTokenTTL time.Duration, totpIssuer string, baseURL string, logger logger.Logger) *User {
 	return &User{
-		repo:     repo,
-		manager:  manager,
-		tokenTTL: tokenTTL,
+		repo:        repo,
+		manager:     manager,
+		guard:       guard,
+		sender:      sender,
+		tokenTTL:    tokenTTL,
+		mfaTokenTTL: mfaTokenTTL,
+		totpIssuer:  totpIssuer,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		logger:      logger,
 	}
 }
 
@@ -57,13 +102,86 @@ func (u *User) Register(ctx context.Context, user dto.RegisterUser) (string, err
 		return "", errutils.Wrap(op, err)
 	}
 
+	domainUser.ID = ID
+	go u.sendVerification(context.WithoutCancel(ctx), domainUser)
+
 	return ID.String(), nil
 }
 
-func (u *User) Login(ctx context.Context, creds dto.LoginUser) (string, error) {
+// VerifyEmail confirms the email of the user the link from the sign-up email
+// was sent to, which gives them access to the invitations sent to it.
+func (u *User) VerifyEmail(ctx context.Context, token string) error {
+	const op = "service.user.VerifyEmail"
+
+	claims, err := u.manager.ParseEmailVerificationToken(token)
+	if err != nil {
+		return errutils.Wrap(op, domain.ErrInvalidVerificationLink)
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return errutils.Wrap(op, domain.ErrInvalidVerificationLink)
+	}
+
+	if err = u.repo.MarkEmailVerified(ctx, userID); err != nil {
+		if errors.Is(err, repo.ErrUserNotFound) {
+			return errutils.Wrap(op, domain.ErrInvalidVerificationLink)
+		}
+		return errutils.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (u *User) Login(ctx context.Context, creds dto.LoginUser, ip string) (dto.LoginResponse, error) {
 	const op = "service.user.Login"
 
+	if err := u.guard.Check(ctx, creds.Email, ip); err != nil {
+		return dto.LoginResponse{}, errutils.Wrap(op, err)
+	}
+
 	user, err := u.repo.GetUserByEmail(ctx, creds.Email)
+	if err != nil {
+		if errors.Is(err, repo.ErrUserNotFound) {
+			return dto.LoginResponse{}, errutils.Wrap(op, u.loginFailed(ctx, creds.Email, ip, false))
+		}
+		return dto.LoginResponse{}, errutils.Wrap(op, err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(creds.Password)); err != nil {
+		return dto.LoginResponse{}, errutils.Wrap(op, u.loginFailed(ctx, creds.Email, ip, true))
+	}
+
+	if err = u.guard.Success(ctx, creds.Email); err != nil {
+		return dto.LoginResponse{}, errutils.Wrap(op, err)
+	}
+
+	resp, err := u.issueTokens(user)
+	if err != nil {
+		return dto.LoginResponse{}, errutils.Wrap(op, err)
+	}
+
+	return resp, nil
+}
+
+func (u *User) VerifyMFA(ctx context.Context, req dto.VerifyMFA) (string, error) {
+	const op = "service.user.VerifyMFA"
+
+	claims, err := u.manager.ParseMFAToken(req.MFAToken)
+	if err != nil {
+		return "", errutils.Wrap(op, domain.ErrInvalidCredentials)
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil || claims.ID == "" || claims.ExpiresAt == nil {
+		return "", errutils.Wrap(op, domain.ErrInvalidCredentials)
+	}
+
+	if err = u.guard.CheckMFA(ctx, userID); err != nil {
+		return "", errutils.Wrap(op, err)
+	}
+
+	user, err := u.repo.GetUserByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, repo.ErrUserNotFound) {
 			return "", errutils.Wrap(op, domain.ErrInvalidCredentials)
@@ -71,9 +189,34 @@ func (u *User) Login(ctx context.Context, creds dto.LoginUser) (string, error) {
 		return "", errutils.Wrap(op, err)
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(creds.Password)); err != nil {
+	if !user.TOTPEnabled {
 		return "", errutils.Wrap(op, domain.ErrInvalidCredentials)
 	}
+	if user.DisabledAt != nil {
+		return "", errutils.Wrap(op, domain.ErrUserDisabled)
+	}
+
+	if err = u.checkSecondFactor(ctx, user, req.Code); err != nil {
+		if errors.Is(err, domain.ErrInvalidMFACode) {
+			if failErr := u.guard.MFAFailure(ctx, user.ID); failErr != nil {
+				return "", errutils.Wrap(op, failErr)
+			}
+		}
+		return "", errutils.Wrap(op, err)
+	}
+
+	// The token proves the password only once, so a code guessed with a
+	// stolen token cannot be exchanged again.
+	if err = u.repo.UseMFAToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		if errors.Is(err, repo.ErrMFATokenUsed) {
+			return "", errutils.Wrap(op, domain.ErrInvalidCredentials)
+		}
+		return "", errutils.Wrap(op, err)
+	}
+
+	if err = u.guard.MFASuccess(ctx, user.ID); err != nil {
+		return "", errutils.Wrap(op, err)
+	}
 
 	token, err := u.manager.NewToken(user.ID.String(), u.tokenTTL)
 	if err != nil {
@@ -82,3 +225,57 @@ func (u *User) Login(ctx context.Context, creds dto.LoginUser) (string, error) {
 
 	return token, nil
 }
+
+// sendVerification emails the user a link that confirms their email. It runs
+// in the background, so a slow mail server does not hold up the sign-up.
+func (u *User) sendVerification(ctx context.Context, user domain.User) {
+	token, err := u.manager.NewEmailVerificationToken(user.ID.String(), emailVerificationTTL)
+	if err != nil {
+		u.logger.Error().Ctx(ctx).Err(err).Str("user_id", user.ID.String()).Msg("failed to create email verification token")
+		return
+	}
+
+	query := url.Values{}
+	query.Set("token", token)
+
+	message := fmt.Sprintf(
+		"Confirm your email address to get access to the calendars and events you are invited to:\n\n%s",
+		u.baseURL+"/auth/verify-email?"+query.Encode(),
+	)
+
+	if err = u.sender.Send("Confirm your email address", message, user.Email); err != nil {
+		u.logger.Error().Ctx(ctx).Err(err).Str("user_id", user.ID.String()).Msg("failed to send email verification")
+	}
+}
+
+// loginFailed records a failed attempt and returns the error to report:
+// the lockout if this attempt triggered one, invalid credentials otherwise.
+func (u *User) loginFailed(ctx context.Context, email, ip string, accountExists bool) error {
+	if err := u.guard.Failure(ctx, email, ip, accountExists); err != nil {
+		return err
+	}
+	return domain.ErrInvalidCredentials
+}
+
+// issueTokens returns an access token for the user, or an MFA challenge
+// when two-factor authentication is enabled. Disabled users get neither.
+func (u *User) issueTokens(user domain.User) (dto.LoginResponse, error) {
+	if user.DisabledAt != nil {
+		return dto.LoginResponse{}, domain.ErrUserDisabled
+	}
+
+	if user.TOTPEnabled {
+		mfaToken, err := u.manager.NewMFAToken(user.ID.String(), u.mfaTokenTTL)
+		if err != nil {
+			return dto.LoginResponse{}, err
+		}
+		return dto.LoginResponse{MFARequired: true, MFAToken: mfaToken}, nil
+	}
+
+	token, err := u.manager.NewToken(user.ID.String(), u.tokenTTL)
+	if err != nil {
+		return dto.LoginResponse{}, err
+	}
+
+	return dto.LoginResponse{Token: token}, nil
+}
@@ -0,0 +1,13 @@
+ALTER TABLE users
+        ADD COLUMN totp_secret VARCHAR(64) NULL,
+        ADD COLUMN totp_enabled BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE user_recovery_codes (
+        id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+        user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+        code_hash VARCHAR(64) NOT NULL,
+        used_at TIMESTAMP NULL,
+        created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_user_recovery_codes_user ON user_recovery_codes (user_id);
@@ -0,0 +1,13 @@
+CREATE TABLE api_keys (
+        id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+        user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+        name VARCHAR(100) NOT NULL,
+        prefix VARCHAR(16) NOT NULL UNIQUE,
+        secret_hash VARCHAR(64) NOT NULL,
+        scope VARCHAR(16) NOT NULL CHECK (scope IN ('read', 'read_write')),
+        expires_at TIMESTAMP NULL,
+        last_used_at TIMESTAMP NULL,
+        created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_api_keys_user ON api_keys (user_id);
@@ -0,0 +1,35 @@
+CREATE TABLE calendars (
+        id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+        user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+        name VARCHAR(100) NOT NULL,
+        color VARCHAR(7) NOT NULL DEFAULT '#1e88e5',
+        description TEXT NOT NULL DEFAULT '',
+        is_default BOOLEAN NOT NULL DEFAULT false,
+        visibility VARCHAR(16) NOT NULL DEFAULT 'private' CHECK (visibility IN ('private', 'public')),
+        created_at TIMESTAMP NOT NULL DEFAULT now(),
+        updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_calendars_user ON calendars (user_id);
+CREATE UNIQUE INDEX idx_calendars_user_default ON calendars (user_id) WHERE is_default;
+
+INSERT INTO calendars (user_id, name, is_default)
+SELECT id, 'Personal', true FROM users;
+
+ALTER TABLE events ADD COLUMN calendar_id UUID REFERENCES calendars(id) ON DELETE CASCADE;
+
+UPDATE events e
+SET calendar_id = c.id
+FROM calendars c
+WHERE c.user_id = e.user_id AND c.is_default;
+
+ALTER TABLE events ALTER COLUMN calendar_id SET NOT NULL;
+
+CREATE INDEX idx_events_calendar_date ON events (calendar_id, event_date);
+
+ALTER TABLE events_archive ADD COLUMN calendar_id UUID NULL;
+
+UPDATE events_archive a
+SET calendar_id = c.id
+FROM calendars c
+WHERE c.user_id = a.user_id AND c.is_default;
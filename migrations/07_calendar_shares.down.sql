@@ -0,0 +1,2 @@
+DROP VIEW IF EXISTS calendar_access;
+DROP TABLE IF EXISTS calendar_shares;
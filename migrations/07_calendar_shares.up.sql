@@ -0,0 +1,22 @@
+CREATE TABLE calendar_shares (
+        id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+        calendar_id UUID NOT NULL REFERENCES calendars(id) ON DELETE CASCADE,
+        user_id UUID NULL REFERENCES users(id) ON DELETE CASCADE,
+        email VARCHAR(255) NOT NULL,
+        role VARCHAR(16) NOT NULL CHECK (role IN ('viewer', 'editor', 'manager')),
+        created_by UUID NULL REFERENCES users(id) ON DELETE SET NULL,
+        created_at TIMESTAMP NOT NULL DEFAULT now(),
+        UNIQUE (calendar_id, email)
+);
+
+CREATE INDEX idx_calendar_shares_user ON calendar_shares (user_id);
+CREATE INDEX idx_calendar_shares_pending_email ON calendar_shares (lower(email)) WHERE user_id IS NULL;
+
+-- calendar_access lists every calendar a user can open and the role they hold.
+CREATE VIEW calendar_access AS
+SELECT id AS calendar_id, user_id, 'owner' AS role
+FROM calendars
+UNION ALL
+SELECT calendar_id, user_id, role
+FROM calendar_shares
+WHERE user_id IS NOT NULL;
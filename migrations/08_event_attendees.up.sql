@@ -0,0 +1,14 @@
+CREATE TABLE event_attendees (
+        id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+        event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+        user_id UUID NULL REFERENCES users(id) ON DELETE CASCADE,
+        email VARCHAR(255) NOT NULL,
+        status VARCHAR(16) NOT NULL DEFAULT 'needs_action'
+            CHECK (status IN ('needs_action', 'accepted', 'declined', 'tentative')),
+        responded_at TIMESTAMP NULL,
+        created_at TIMESTAMP NOT NULL DEFAULT now(),
+        UNIQUE (event_id, email)
+);
+
+CREATE INDEX idx_event_attendees_user ON event_attendees (user_id, status);
+CREATE INDEX idx_event_attendees_pending_email ON event_attendees (lower(email)) WHERE user_id IS NULL;
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_events_starts_at;
+
+ALTER TABLE events_archive
+        DROP COLUMN IF EXISTS starts_at,
+        DROP COLUMN IF EXISTS ends_at;
+
+ALTER TABLE events
+        DROP CONSTRAINT IF EXISTS events_time_range_check,
+        DROP COLUMN IF EXISTS starts_at,
+        DROP COLUMN IF EXISTS ends_at;
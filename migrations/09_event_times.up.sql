@@ -0,0 +1,12 @@
+-- Events without a time range stay all-day events on event_date.
+ALTER TABLE events
+        ADD COLUMN starts_at TIMESTAMPTZ NULL,
+        ADD COLUMN ends_at TIMESTAMPTZ NULL,
+        ADD CONSTRAINT events_time_range_check
+            CHECK ((starts_at IS NULL AND ends_at IS NULL) OR ends_at > starts_at);
+
+ALTER TABLE events_archive
+        ADD COLUMN starts_at TIMESTAMPTZ NULL,
+        ADD COLUMN ends_at TIMESTAMPTZ NULL;
+
+CREATE INDEX idx_events_starts_at ON events (starts_at, ends_at) WHERE starts_at IS NOT NULL;
@@ -0,0 +1,2 @@
+-- version is bumped on every update and exposed as the event's ETag.
+ALTER TABLE events ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,12 @@
+CREATE TABLE idempotency_keys (
+        user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+        key VARCHAR(255) NOT NULL,
+        request_hash CHAR(64) NOT NULL,
+        status_code INTEGER NULL,
+        response_body BYTEA NULL,
+        created_at TIMESTAMP NOT NULL DEFAULT now(),
+        expires_at TIMESTAMP NOT NULL,
+        PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_events_deleted_at;
+
+DELETE FROM events WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE events DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,3 @@
+ALTER TABLE events ADD COLUMN deleted_at TIMESTAMP NULL;
+
+CREATE INDEX idx_events_deleted_at ON events (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS event_history;
+
+DROP FUNCTION IF EXISTS event_history_append_only();
@@ -0,0 +1,26 @@
+-- event_history is append-only and outlives the events it describes, so it
+-- has no foreign keys.
+CREATE TABLE event_history (
+        id BIGSERIAL PRIMARY KEY,
+        event_id UUID NOT NULL,
+        action VARCHAR(16) NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore', 'revert', 'archive', 'purge')),
+        actor_id UUID NULL,
+        version INTEGER NOT NULL,
+        before JSONB NULL,
+        after JSONB NULL,
+        changes JSONB NOT NULL DEFAULT '{}',
+        request_id VARCHAR(64) NULL,
+        created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_event_history_event ON event_history (event_id, id);
+
+CREATE FUNCTION event_history_append_only() RETURNS trigger AS $$
+BEGIN
+        RAISE EXCEPTION 'event_history is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER event_history_append_only
+        BEFORE UPDATE OR DELETE ON event_history
+        FOR EACH ROW EXECUTE FUNCTION event_history_append_only();
@@ -0,0 +1,8 @@
+ALTER TABLE users ADD COLUMN totp_last_step BIGINT NULL;
+
+CREATE TABLE used_mfa_tokens (
+        jti VARCHAR(64) PRIMARY KEY,
+        expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_used_mfa_tokens_expires_at ON used_mfa_tokens (expires_at);
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP NULL;
+
+-- accounts that signed in through an identity provider have a verified email
+UPDATE users SET email_verified_at = now()
+WHERE id IN (SELECT user_id FROM user_identities);
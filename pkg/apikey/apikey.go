@@ -0,0 +1,58 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Prefix distinguishes API keys from JWTs in the Authorization header.
+const Prefix = "ecal_"
+
+const (
+	idSize     = 6
+	secretSize = 24
+)
+
+// Generate returns a new key in the form ecal_<id>_<secret> along with the
+// public id used for lookups and the hash of the secret to be stored.
+func Generate() (key string, id string, secretHash string, err error) {
+	idBytes := make([]byte, idSize)
+	if _, err = rand.Read(idBytes); err != nil {
+		return "", "", "", err
+	}
+
+	secretBytes := make([]byte, secretSize)
+	if _, err = rand.Read(secretBytes); err != nil {
+		return "", "", "", err
+	}
+
+	id = hex.EncodeToString(idBytes)
+	secret := hex.EncodeToString(secretBytes)
+
+	return Prefix + id + "_" + secret, id, Hash(secret), nil
+}
+
+// Parse splits a key into its public id and secret parts.
+func Parse(key string) (id string, secret string, ok bool) {
+	if !IsAPIKey(key) {
+		return "", "", false
+	}
+
+	id, secret, ok = strings.Cut(strings.TrimPrefix(key, Prefix), "_")
+	if !ok || len(id) != idSize*2 || len(secret) != secretSize*2 {
+		return "", "", false
+	}
+
+	return id, secret, true
+}
+
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, Prefix)
+}
+
+func Hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
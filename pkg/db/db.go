@@ -21,6 +21,7 @@ func OpenDB(ctx context.Context, cfg config.DBConfig) (*pgxpool.Pool, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse connection string: %w", err)
 	}
+	poolConfig.ConnConfig.Tracer = NewQueryTracer(nil)
 
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
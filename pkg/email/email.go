@@ -1,6 +1,7 @@
 package email
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/smtp"
@@ -47,3 +48,27 @@ func (c Client) Send(subject string, message string, to string) error {
 
 	return smtp.SendMail(addr, auth, c.cfg.From, recipient, msg)
 }
+
+// Ping connects to the SMTP server and waits for its greeting, without
+// authenticating or sending anything.
+func (c Client) Ping(ctx context.Context) error {
+	addr := net.JoinHostPort(c.cfg.SMTPHost, c.cfg.SMTPPort)
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, c.cfg.SMTPHost)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	return client.Quit()
+}
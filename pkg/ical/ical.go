@@ -0,0 +1,102 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+)
+
+const (
+	prodID     = "-//event-calendar//EN"
+	dateLayout = "20060102"
+	timeLayout = "20060102T150405Z"
+	lineLimit  = 75
+)
+
+type Event struct {
+	UID       string
+	Date      time.Time
+	StartsAt  *time.Time
+	EndsAt    *time.Time
+	Summary   string
+	RemindAt  *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Encode renders events as an RFC 5545 calendar. Events without a time
+// range become all-day VEVENTs.
+func Encode(events []Event, now time.Time) []byte {
+	var buf bytes.Buffer
+
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:"+prodID)
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+
+	for _, e := range events {
+		writeLine(&buf, "BEGIN:VEVENT")
+		writeLine(&buf, "UID:"+escape(e.UID))
+		writeLine(&buf, "DTSTAMP:"+now.UTC().Format(timeLayout))
+		if e.StartsAt != nil && e.EndsAt != nil {
+			writeLine(&buf, "DTSTART:"+e.StartsAt.UTC().Format(timeLayout))
+			writeLine(&buf, "DTEND:"+e.EndsAt.UTC().Format(timeLayout))
+		} else {
+			writeLine(&buf, "DTSTART;VALUE=DATE:"+e.Date.Format(dateLayout))
+			writeLine(&buf, "DTEND;VALUE=DATE:"+e.Date.AddDate(0, 0, 1).Format(dateLayout))
+		}
+		writeLine(&buf, "SUMMARY:"+escape(e.Summary))
+		if !e.CreatedAt.IsZero() {
+			writeLine(&buf, "CREATED:"+e.CreatedAt.UTC().Format(timeLayout))
+		}
+		if !e.UpdatedAt.IsZero() {
+			writeLine(&buf, "LAST-MODIFIED:"+e.UpdatedAt.UTC().Format(timeLayout))
+		}
+		if e.RemindAt != nil {
+			writeLine(&buf, "BEGIN:VALARM")
+			writeLine(&buf, "ACTION:DISPLAY")
+			writeLine(&buf, "DESCRIPTION:"+escape(e.Summary))
+			writeLine(&buf, "TRIGGER;VALUE=DATE-TIME:"+e.RemindAt.UTC().Format(timeLayout))
+			writeLine(&buf, "END:VALARM")
+		}
+		writeLine(&buf, "END:VEVENT")
+	}
+
+	writeLine(&buf, "END:VCALENDAR")
+
+	return buf.Bytes()
+}
+
+func escape(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return r.Replace(s)
+}
+
+// writeLine folds content lines longer than 75 octets without splitting
+// UTF-8 sequences.
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := lineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// continuation lines start with a space that counts towards the limit
+		limit = lineLimit - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -1,49 +1,163 @@
 package jwt
 
 import (
+	"crypto"
 	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"time"
 )
 
+const (
+	// PurposeMFA marks a short-lived token that only proves the password step
+	// of a two-step login and must be exchanged for an access token.
+	PurposeMFA = "mfa"
+	// PurposeRSVP marks a token embedded in an invitation link. Its subject
+	// is the attendee ID rather than a user.
+	PurposeRSVP = "rsvp"
+	// PurposeVerifyEmail marks a token embedded in the link that confirms a
+	// user owns the email they signed up with.
+	PurposeVerifyEmail = "verify_email"
+)
+
+var ErrUnexpectedPurpose = errors.New("unexpected token purpose")
+
+// Key is an asymmetric key identified by kid. Keys without a private part
+// can only verify tokens.
+type Key struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
 type Manager struct {
-	secret []byte
+	secret  []byte
+	signing *Key
+	keys    map[string]Key
 }
 
+// NewManager creates a manager that signs and verifies tokens with HS256.
 func NewManager(secret []byte) *Manager {
 	return &Manager{secret: secret}
 }
 
+// NewManagerWithKeys creates a manager that signs tokens with the key
+// identified by activeKID and verifies tokens signed with any of keys.
+// If secret is not empty, HS256 tokens issued before the switch to
+// asymmetric keys are still accepted.
+func NewManagerWithKeys(secret []byte, keys []Key, activeKID string) (*Manager, error) {
+	m := &Manager{
+		secret: secret,
+		keys:   make(map[string]Key, len(keys)),
+	}
+
+	for _, key := range keys {
+		if _, ok := m.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		m.keys[key.ID] = key
+	}
+
+	active, ok := m.keys[activeKID]
+	if !ok {
+		return nil, fmt.Errorf("active key %q not found", activeKID)
+	}
+	if active.PrivateKey == nil {
+		return nil, fmt.Errorf("active key %q has no private key", activeKID)
+	}
+	m.signing = &active
+
+	return m, nil
+}
+
 type TokenClaims struct {
 	jwt.RegisteredClaims
-	UserID string `json:"user_id"`
+	UserID  string `json:"user_id"`
+	Purpose string `json:"purpose,omitempty"`
 }
 
 func (m *Manager) NewToken(userID string, ttl time.Duration) (string, error) {
-	claims := TokenClaims{
+	return m.newToken(userID, "", ttl)
+}
+
+// NewMFAToken returns a token for the second step of a login. It carries a
+// unique ID (jti) so that it can be accepted only once.
+func (m *Manager) NewMFAToken(userID string, ttl time.Duration) (string, error) {
+	return m.sign(TokenClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
-		UserID: userID,
+		UserID:  userID,
+		Purpose: PurposeMFA,
+	})
+}
+
+// ParseToken parses an access token. Tokens issued for any other purpose
+// are rejected.
+func (m *Manager) ParseToken(tokenStr string) (*TokenClaims, error) {
+	return m.parseToken(tokenStr, "")
+}
+
+func (m *Manager) ParseMFAToken(tokenStr string) (*TokenClaims, error) {
+	return m.parseToken(tokenStr, PurposeMFA)
+}
+
+func (m *Manager) NewRSVPToken(attendeeID string, ttl time.Duration) (string, error) {
+	return m.sign(TokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   attendeeID,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+		Purpose: PurposeRSVP,
+	})
+}
+
+func (m *Manager) ParseRSVPToken(tokenStr string) (*TokenClaims, error) {
+	return m.parseToken(tokenStr, PurposeRSVP)
+}
+
+func (m *Manager) NewEmailVerificationToken(userID string, ttl time.Duration) (string, error) {
+	return m.newToken(userID, PurposeVerifyEmail, ttl)
+}
+
+func (m *Manager) ParseEmailVerificationToken(tokenStr string) (*TokenClaims, error) {
+	return m.parseToken(tokenStr, PurposeVerifyEmail)
+}
+
+func (m *Manager) newToken(userID string, purpose string, ttl time.Duration) (string, error) {
+	return m.sign(TokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+		UserID:  userID,
+		Purpose: purpose,
+	})
+}
+
+func (m *Manager) sign(claims TokenClaims) (string, error) {
+	if m.signing == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString(m.secret)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(m.secret)
+	token := jwt.NewWithClaims(m.signing.Method, claims)
+	token.Header["kid"] = m.signing.ID
+
+	tokenString, err := token.SignedString(m.signing.PrivateKey)
 	if err != nil {
 		return "", err
 	}
 	return tokenString, nil
 }
 
-func (m *Manager) ParseToken(tokenStr string) (*TokenClaims, error) {
-	jwtToken, err := jwt.ParseWithClaims(tokenStr, &TokenClaims{}, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return m.secret, nil
-	})
+func (m *Manager) parseToken(tokenStr string, purpose string) (*TokenClaims, error) {
+	jwtToken, err := jwt.ParseWithClaims(tokenStr, &TokenClaims{}, m.keyFunc)
 	if err != nil {
 		return nil, err
 	}
@@ -52,5 +166,31 @@ func (m *Manager) ParseToken(tokenStr string) (*TokenClaims, error) {
 	if !ok || !jwtToken.Valid {
 		return nil, errors.New("invalid token claims")
 	}
+
+	if claims.Purpose != purpose {
+		return nil, ErrUnexpectedPurpose
+	}
+
 	return claims, nil
 }
+
+func (m *Manager) keyFunc(token *jwt.Token) (any, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if len(m.secret) == 0 {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return m.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := m.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.PublicKey, nil
+}
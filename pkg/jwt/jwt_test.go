@@ -0,0 +1,149 @@
+package jwt_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/ilam072/event-calendar/pkg/jwt"
+)
+
+func TestManager_KeyRotation(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ed25519 key: %v", err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+
+	oldKey, _ := jwt.NewKey("old", rsaKey)
+	newKey, _ := jwt.NewKey("new", edKey)
+
+	legacy := jwt.NewManager([]byte("secret"))
+	legacyToken, err := legacy.NewToken("user-1", time.Minute)
+	if err != nil {
+		t.Fatalf("failed to sign legacy token: %v", err)
+	}
+
+	before, err := jwt.NewManagerWithKeys([]byte("secret"), []jwt.Key{oldKey}, "old")
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	oldToken, err := before.NewToken("user-1", time.Minute)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	after, err := jwt.NewManagerWithKeys([]byte("secret"), []jwt.Key{oldKey, newKey}, "new")
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	newToken, err := after.NewToken("user-1", time.Minute)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	for name, token := range map[string]string{"legacy": legacyToken, "old": oldToken, "new": newToken} {
+		claims, err := after.ParseToken(token)
+		if err != nil {
+			t.Fatalf("%s token rejected: %v", name, err)
+		}
+		if claims.UserID != "user-1" {
+			t.Fatalf("%s token: unexpected user id %s", name, claims.UserID)
+		}
+	}
+
+	if _, err = before.ParseToken(newToken); err == nil {
+		t.Fatalf("token signed with unknown key must be rejected")
+	}
+
+	rotated, err := jwt.NewManagerWithKeys(nil, []jwt.Key{oldKey, newKey}, "new")
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	if _, err = rotated.ParseToken(legacyToken); err == nil {
+		t.Fatalf("hs256 token must be rejected without a secret")
+	}
+	if _, err = rotated.ParseToken(newToken); err != nil {
+		t.Fatalf("new token rejected: %v", err)
+	}
+
+	if got := len(after.JWKS().Keys); got != 2 {
+		t.Fatalf("expected 2 keys in JWKS, got %d", got)
+	}
+}
+
+func TestManager_RejectsMFATokenAsAccessToken(t *testing.T) {
+	m := jwt.NewManager([]byte("secret"))
+
+	token, err := m.NewMFAToken("user-1", time.Minute)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	if _, err = m.ParseToken(token); err == nil {
+		t.Fatalf("mfa token must not be accepted as access token")
+	}
+	claims, err := m.ParseMFAToken(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	other, _ := m.NewMFAToken("user-1", time.Minute)
+	otherClaims, err := m.ParseMFAToken(other)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.ID == "" || claims.ID == otherClaims.ID {
+		t.Fatalf("mfa tokens must carry unique ids, got %q and %q", claims.ID, otherClaims.ID)
+	}
+}
+
+func TestManager_RSVPToken(t *testing.T) {
+	m := jwt.NewManager([]byte("secret"))
+
+	token, err := m.NewRSVPToken("attendee-1", time.Minute)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	if _, err = m.ParseToken(token); err == nil {
+		t.Fatalf("rsvp token must not be accepted as access token")
+	}
+
+	claims, err := m.ParseRSVPToken(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.Subject != "attendee-1" || claims.UserID != "" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestManager_EmailVerificationToken(t *testing.T) {
+	m := jwt.NewManager([]byte("secret"))
+
+	token, err := m.NewEmailVerificationToken("user-1", time.Minute)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	if _, err = m.ParseToken(token); err == nil {
+		t.Fatalf("email verification token must not be accepted as access token")
+	}
+	if _, err = m.ParseRSVPToken(token); err == nil {
+		t.Fatalf("email verification token must not be accepted as rsvp token")
+	}
+
+	claims, err := m.ParseEmailVerificationToken(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.UserID != "user-1" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+}
@@ -0,0 +1,94 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const keyFileExt = ".pem"
+
+// LoadKeys reads every *.pem file in dir. The file name without extension
+// becomes the key id. Private keys (PKCS#8 or PKCS#1) can sign and verify,
+// public keys (PKIX) can only verify.
+func LoadKeys(dir string) ([]Key, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keys dir: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != keyFileExt {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	keys := make([]Key, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key %s: %w", name, err)
+		}
+
+		key, err := ParseKey(strings.TrimSuffix(name, keyFileExt), data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key %s: %w", name, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// ParseKey parses a PEM-encoded RSA or Ed25519 key.
+func ParseKey(kid string, data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, fmt.Errorf("no PEM block found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return Key{}, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return Key{}, err
+	}
+
+	return NewKey(kid, parsed)
+}
+
+// NewKey wraps an RSA or Ed25519 private or public key. RSA keys sign with
+// RS256 and Ed25519 keys with EdDSA.
+func NewKey(kid string, key any) (Key, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return Key{ID: kid, Method: jwt.SigningMethodRS256, PrivateKey: k, PublicKey: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		return Key{ID: kid, Method: jwt.SigningMethodRS256, PublicKey: k}, nil
+	case ed25519.PrivateKey:
+		return Key{ID: kid, Method: jwt.SigningMethodEdDSA, PrivateKey: k, PublicKey: k.Public()}, nil
+	case ed25519.PublicKey:
+		return Key{ID: kid, Method: jwt.SigningMethodEdDSA, PublicKey: k}, nil
+	default:
+		return Key{}, fmt.Errorf("unsupported key type %T", key)
+	}
+}
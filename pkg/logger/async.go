@@ -1,9 +1,13 @@
 package logger
 
 import (
+	"context"
 	"encoding/json"
-	"github.com/rs/zerolog/log"
+	"fmt"
+	"io"
 	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -14,38 +18,154 @@ type LogEntry struct {
 	Fields  map[string]interface{} `json:"fields,omitempty"`
 }
 
+const (
+	OutputFile   = "file"
+	OutputStdout = "stdout"
+	OutputBoth   = "both"
+)
+
+type Config struct {
+	// Output is OutputFile, OutputStdout or OutputBoth.
+	Output string
+	File   string
+	Level  Level
+	Buffer int
+	// Block makes a full buffer hold up the caller rather than drop the
+	// entry.
+	Block bool
+	// MaxSize, MaxAge, MaxBackups and MaxBackupAge control the rotation of
+	// the file, see RotatingFile.
+	MaxSize      int64
+	MaxAge       time.Duration
+	MaxBackups   int
+	MaxBackupAge time.Duration
+	// DropReportInterval is how often a warning is logged about entries
+	// dropped since the last one. Defaults to 30s.
+	DropReportInterval time.Duration
+}
+
 type AsyncLogger struct {
-	ch     chan LogEntry
-	writer *os.File
+	ch          chan LogEntry
+	sinks       []io.Writer
+	file        *RotatingFile
+	level       Level
+	block       bool
+	reportEvery time.Duration
+	dropped     atomic.Uint64
+
+	// mu keeps entries from being sent once Stop closed the channel.
+	mu       sync.RWMutex
+	closed   bool
+	started  atomic.Bool
+	done     chan struct{}
+	stopOnce sync.Once
 }
 
-func NewAsyncLogger(filePath string, buffer int) (*AsyncLogger, error) {
-	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return nil, err
+func NewAsyncLogger(cfg Config) (*AsyncLogger, error) {
+	l := &AsyncLogger{
+		ch:          make(chan LogEntry, cfg.Buffer),
+		level:       cfg.Level,
+		block:       cfg.Block,
+		reportEvery: cfg.DropReportInterval,
+		done:        make(chan struct{}),
+	}
+	if l.reportEvery <= 0 {
+		l.reportEvery = 30 * time.Second
 	}
 
-	return &AsyncLogger{
-		ch:     make(chan LogEntry, buffer),
-		writer: f,
-	}, nil
+	switch cfg.Output {
+	case OutputFile, "", OutputBoth:
+		f, err := OpenRotatingFile(cfg.File, cfg.MaxSize, cfg.MaxAge, cfg.MaxBackups, cfg.MaxBackupAge)
+		if err != nil {
+			return nil, err
+		}
+		l.file = f
+		l.sinks = append(l.sinks, f)
+		if cfg.Output == OutputBoth {
+			l.sinks = append(l.sinks, os.Stdout)
+		}
+	case OutputStdout:
+		l.sinks = append(l.sinks, os.Stdout)
+	default:
+		return nil, fmt.Errorf("unknown log output %q", cfg.Output)
+	}
+
+	return l, nil
 }
 
 func (l *AsyncLogger) Start() {
-	go func() {
-		for entry := range l.ch {
-			data, _ := json.Marshal(entry)
-			_, err := l.writer.Write(append(data, '\n'))
-			if err != nil {
-				log.Error().Err(err).Msg("async logger write failed")
+	l.started.Store(true)
+	go l.run()
+}
+
+func (l *AsyncLogger) run() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.reportEvery)
+	defer ticker.Stop()
+
+	var reported uint64
+	for {
+		select {
+		case entry, ok := <-l.ch:
+			if !ok {
+				l.reportDrops(reported)
+				return
 			}
+			l.write(entry)
+		case <-ticker.C:
+			reported = l.reportDrops(reported)
 		}
-	}()
+	}
+}
+
+// reportDrops logs how many entries were dropped since the count reported
+// last and returns the current count.
+func (l *AsyncLogger) reportDrops(reported uint64) uint64 {
+	dropped := l.dropped.Load()
+	if dropped > reported {
+		l.write(LogEntry{
+			Level:   WarnLevel.String(),
+			Message: "log buffer full, entries dropped",
+			Time:    time.Now(),
+			Fields: map[string]interface{}{
+				"dropped":       dropped - reported,
+				"dropped_total": dropped,
+			},
+		})
+	}
+	return dropped
 }
 
+func (l *AsyncLogger) write(entry LogEntry) {
+	data, _ := json.Marshal(entry)
+	data = append(data, '\n')
+
+	for _, sink := range l.sinks {
+		if _, err := sink.Write(data); err != nil {
+			// The failing sink may be the only one, so the error can not go
+			// through the logger itself.
+			fmt.Fprintf(os.Stderr, "async logger write failed: %v\n", err)
+		}
+	}
+}
+
+// Stop waits for the buffered entries to be written and closes the file.
+// Entries logged after Stop are dropped.
 func (l *AsyncLogger) Stop() {
-	close(l.ch)
-	_ = l.writer.Close()
+	l.stopOnce.Do(func() {
+		l.mu.Lock()
+		l.closed = true
+		close(l.ch)
+		l.mu.Unlock()
+
+		if l.started.Load() {
+			<-l.done
+		}
+		if l.file != nil {
+			_ = l.file.Close()
+		}
+	})
 }
 
 type EventBuilder struct {
@@ -54,28 +174,41 @@ type EventBuilder struct {
 	fields map[string]interface{}
 }
 
-func (l *AsyncLogger) Error() Event {
-	return &EventBuilder{
-		logger: l,
-		level:  "ERROR",
-		fields: make(map[string]interface{}),
-	}
+func (l *AsyncLogger) Debug() Event {
+	return l.newEvent(DebugLevel)
+}
+
+func (l *AsyncLogger) Info() Event {
+	return l.newEvent(InfoLevel)
 }
 
 func (l *AsyncLogger) Warn() Event {
+	return l.newEvent(WarnLevel)
+}
+
+func (l *AsyncLogger) Error() Event {
+	return l.newEvent(ErrorLevel)
+}
+
+// newEvent returns an event that discards everything when the level is
+// below the minimum one.
+func (l *AsyncLogger) newEvent(level Level) Event {
+	if level < l.level {
+		return &DummyEvent{}
+	}
+
 	return &EventBuilder{
 		logger: l,
-		level:  "WARN",
+		level:  level.String(),
 		fields: make(map[string]interface{}),
 	}
 }
 
-func (l *AsyncLogger) Info() Event {
-	return &EventBuilder{
-		logger: l,
-		level:  "INFO",
-		fields: make(map[string]interface{}),
+func (e *EventBuilder) Ctx(ctx context.Context) Event {
+	for _, f := range contextFields(ctx) {
+		e.fields[f.key] = f.value
 	}
+	return e
 }
 
 func (e *EventBuilder) Str(key, value string) Event {
@@ -105,13 +238,35 @@ func (e *EventBuilder) Msg(msg string) {
 }
 
 func (l *AsyncLogger) enqueue(level, msg string, fields map[string]interface{}) {
-	select {
-	case l.ch <- LogEntry{
+	entry := LogEntry{
 		Level:   level,
 		Message: msg,
 		Time:    time.Now(),
 		Fields:  fields,
-	}:
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.closed {
+		l.dropped.Add(1)
+		return
+	}
+
+	if l.block {
+		l.ch <- entry
+		return
+	}
+
+	select {
+	case l.ch <- entry:
 	default:
+		l.dropped.Add(1)
 	}
 }
+
+// Dropped returns how many entries were dropped because the buffer was full
+// or the logger stopped.
+func (l *AsyncLogger) Dropped() uint64 {
+	return l.dropped.Load()
+}
@@ -0,0 +1,112 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readEntries(t *testing.T, path string) []logger.LogEntry {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var entries []logger.LogEntry
+	for _, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var entry logger.LogEntry
+		require.NoError(t, json.Unmarshal(line, &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAsyncLogger_StopFlushes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	l, err := logger.NewAsyncLogger(logger.Config{File: path, Buffer: 1000})
+	require.NoError(t, err)
+	l.Start()
+
+	for i := 0; i < 500; i++ {
+		l.Info().Msg("entry")
+	}
+	l.Stop()
+	l.Stop()
+
+	assert.Len(t, readEntries(t, path), 500)
+	assert.Zero(t, l.Dropped())
+}
+
+func TestAsyncLogger_MinLevel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	l, err := logger.NewAsyncLogger(logger.Config{File: path, Level: logger.WarnLevel, Buffer: 10})
+	require.NoError(t, err)
+	l.Start()
+
+	l.Debug().Msg("debug")
+	l.Info().Msg("info")
+	l.Warn().Msg("warn")
+	l.Error().Msg("error")
+	l.Stop()
+
+	entries := readEntries(t, path)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "WARN", entries[0].Level)
+	assert.Equal(t, "ERROR", entries[1].Level)
+}
+
+func TestAsyncLogger_DropsWhenFull(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	l, err := logger.NewAsyncLogger(logger.Config{File: path, Buffer: 2})
+	require.NoError(t, err)
+
+	// not started yet, so nothing drains the buffer
+	for i := 0; i < 5; i++ {
+		l.Info().Msg("entry")
+	}
+	assert.Equal(t, uint64(3), l.Dropped())
+
+	l.Start()
+	l.Stop()
+
+	entries := readEntries(t, path)
+	require.Len(t, entries, 3)
+	last := entries[2]
+	assert.Equal(t, "WARN", last.Level)
+	assert.Equal(t, float64(3), last.Fields["dropped"])
+}
+
+func TestAsyncLogger_BlocksWhenFull(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	l, err := logger.NewAsyncLogger(logger.Config{File: path, Buffer: 1, Block: true})
+	require.NoError(t, err)
+	l.Start()
+
+	for i := 0; i < 200; i++ {
+		l.Info().Msg("entry")
+	}
+	l.Stop()
+
+	assert.Len(t, readEntries(t, path), 200)
+	assert.Zero(t, l.Dropped())
+}
+
+func TestAsyncLogger_UnknownOutput(t *testing.T) {
+	_, err := logger.NewAsyncLogger(logger.Config{Output: "syslog"})
+	assert.Error(t, err)
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := logger.ParseLevel("DEBUG")
+	require.NoError(t, err)
+	assert.Equal(t, logger.DebugLevel, level)
+
+	_, err = logger.ParseLevel("verbose")
+	assert.Error(t, err)
+}
@@ -0,0 +1,32 @@
+package logger
+
+import "context"
+
+type fieldsKey struct{}
+
+type field struct {
+	key   string
+	value string
+}
+
+// NewContext returns a copy of ctx that carries key=value. Entries that are
+// given the context through Event.Ctx include every field it carries, so
+// request-scoped values such as request_id and user_id are set once by the
+// middlewares rather than at each call site.
+func NewContext(ctx context.Context, key string, value string) context.Context {
+	parent := contextFields(ctx)
+
+	fields := make([]field, 0, len(parent)+1)
+	fields = append(fields, parent...)
+	fields = append(fields, field{key: key, value: value})
+
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+func contextFields(ctx context.Context) []field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]field)
+	return fields
+}
@@ -0,0 +1,43 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEventCtx(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	l, err := logger.NewAsyncLogger(logger.Config{File: path, Buffer: 10})
+	require.NoError(t, err)
+	l.Start()
+
+	ctx := logger.NewContext(context.Background(), "request_id", "req-1")
+	ctx = logger.NewContext(ctx, "user_id", "user-1")
+	l.Error().Ctx(ctx).Str("op", "test").Msg("failed")
+	// fields of a context do not leak into its parent
+	l.Info().Ctx(context.Background()).Msg("plain")
+
+	l.Stop()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var entry logger.LogEntry
+	require.NoError(t, json.Unmarshal(lines[0], &entry))
+	assert.Equal(t, "failed", entry.Message)
+	assert.Equal(t, map[string]interface{}{"request_id": "req-1", "user_id": "user-1", "op": "test"}, entry.Fields)
+
+	entry = logger.LogEntry{}
+	require.NoError(t, json.Unmarshal(lines[1], &entry))
+	assert.Empty(t, entry.Fields)
+}
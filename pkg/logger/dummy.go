@@ -1,14 +1,18 @@
 package logger
 
+import "context"
+
 type DummyLogger struct{}
 
+func (d *DummyLogger) Debug() Event { return &DummyEvent{} }
 func (d *DummyLogger) Info() Event  { return &DummyEvent{} }
 func (d *DummyLogger) Warn() Event  { return &DummyEvent{} }
 func (d *DummyLogger) Error() Event { return &DummyEvent{} }
 
 type DummyEvent struct{}
 
-func (e *DummyEvent) Err(error) Event          { return e }
-func (e *DummyEvent) Any(string, any) Event    { return e }
-func (e *DummyEvent) Str(string, string) Event { return e }
-func (e *DummyEvent) Msg(string)               {}
+func (e *DummyEvent) Ctx(context.Context) Event { return e }
+func (e *DummyEvent) Err(error) Event           { return e }
+func (e *DummyEvent) Any(string, any) Event     { return e }
+func (e *DummyEvent) Str(string, string) Event  { return e }
+func (e *DummyEvent) Msg(string)                {}
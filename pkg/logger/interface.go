@@ -1,6 +1,10 @@
 package logger
 
+import "context"
+
 type Event interface {
+	// Ctx adds the fields carried by ctx, see NewContext.
+	Ctx(context.Context) Event
 	Err(error) Event
 	Any(string, any) Event
 	Str(string, string) Event
@@ -8,6 +12,7 @@ type Event interface {
 }
 
 type Logger interface {
+	Debug() Event
 	Info() Event
 	Warn() Event
 	Error() Event
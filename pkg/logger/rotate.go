@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const backupTimeFormat = "20060102T150405.000"
+
+// RotatingFile is a file that is moved aside to <path>.<timestamp> once it
+// grows past maxSize bytes or has been written to for maxAge. Of the moved
+// files, the newest maxBackups are kept and those rotated more than
+// maxBackupAge ago are removed. A zero limit disables it. It is not safe for
+// concurrent use.
+type RotatingFile struct {
+	path         string
+	maxSize      int64
+	maxAge       time.Duration
+	maxBackups   int
+	maxBackupAge time.Duration
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	now      func() time.Time
+}
+
+func OpenRotatingFile(path string, maxSize int64, maxAge time.Duration, maxBackups int, maxBackupAge time.Duration) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path:         path,
+		maxSize:      maxSize,
+		maxAge:       maxAge,
+		maxBackups:   maxBackups,
+		maxBackupAge: maxBackupAge,
+		now:          time.Now,
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	// a failed rotation can leave the file closed, it is reopened here
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if f.due(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	return n, err
+}
+
+func (f *RotatingFile) Close() error {
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = f.now()
+
+	return nil
+}
+
+// due reports whether the file has to be rotated before n more bytes are
+// written. An empty file is never rotated, so an entry larger than maxSize
+// still gets written.
+func (f *RotatingFile) due(n int64) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.maxSize > 0 && f.size+n > f.maxSize {
+		return true
+	}
+	return f.maxAge > 0 && f.now().Sub(f.openedAt) >= f.maxAge
+}
+
+// rotate moves the file aside and opens a new one. If that fails, the
+// original path is reopened, so that later writes go on appending to it and
+// retry the rotation.
+func (f *RotatingFile) rotate() error {
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+
+	backup := f.path + "." + f.now().UTC().Format(backupTimeFormat)
+	if err := os.Rename(f.path, backup); err != nil {
+		if openErr := f.open(); openErr != nil {
+			return fmt.Errorf("failed to reopen log file: %w", openErr)
+		}
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	return f.prune()
+}
+
+// prune removes the oldest backups beyond maxBackups and those older than
+// maxBackupAge.
+func (f *RotatingFile) prune() error {
+	if f.maxBackups <= 0 && f.maxBackupAge <= 0 {
+		return nil
+	}
+
+	matches, err := filepath.Glob(f.path + ".*")
+	if err != nil {
+		return err
+	}
+
+	var backups, expired []string
+	for _, match := range matches {
+		suffix := strings.TrimPrefix(match, f.path+".")
+		rotatedAt, err := time.Parse(backupTimeFormat, suffix)
+		if err != nil {
+			continue
+		}
+		if f.maxBackupAge > 0 && f.now().Sub(rotatedAt) > f.maxBackupAge {
+			expired = append(expired, match)
+		} else {
+			backups = append(backups, match)
+		}
+	}
+
+	if f.maxBackups > 0 && len(backups) > f.maxBackups {
+		// the timestamps sort chronologically
+		sort.Strings(backups)
+		expired = append(expired, backups[:len(backups)-f.maxBackups]...)
+	}
+
+	for _, backup := range expired {
+		if err := os.Remove(backup); err != nil {
+			return fmt.Errorf("failed to remove old log file: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func backups(t *testing.T, path string) []string {
+	matches, err := filepath.Glob(path + ".*")
+	require.NoError(t, err)
+	return matches
+}
+
+func TestRotatingFile_Size(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	f, err := OpenRotatingFile(path, 10, 0, 2, 0)
+	require.NoError(t, err)
+	f.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	for _, line := range []string{"aaaaaa\n", "bbbbbb\n", "cccccc\n", "dddddd\n"} {
+		_, err = f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, f.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "dddddd\n", string(data))
+
+	// the oldest backup, with the a's, is pruned
+	kept := backups(t, path)
+	require.Len(t, kept, 2)
+	first, err := os.ReadFile(kept[0])
+	require.NoError(t, err)
+	assert.Equal(t, "bbbbbb\n", string(first))
+}
+
+func TestRotatingFile_Age(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	f, err := OpenRotatingFile(path, 0, time.Hour, 0, 0)
+	require.NoError(t, err)
+	f.now = func() time.Time { return now }
+	f.openedAt = now
+
+	_, err = f.Write([]byte("first\n"))
+	require.NoError(t, err)
+	now = now.Add(30 * time.Minute)
+	_, err = f.Write([]byte("second\n"))
+	require.NoError(t, err)
+	now = now.Add(30 * time.Minute)
+	_, err = f.Write([]byte("third\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	kept := backups(t, path)
+	require.Len(t, kept, 1)
+	assert.True(t, strings.HasSuffix(kept[0], ".20250101T010000.000"))
+
+	data, err := os.ReadFile(kept[0])
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", string(data))
+}
+
+func TestRotatingFile_BackupAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	f, err := OpenRotatingFile(path, 0, time.Hour, 0, 90*time.Minute)
+	require.NoError(t, err)
+	f.now = func() time.Time { return now }
+	f.openedAt = now
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err = f.Write([]byte(line))
+		require.NoError(t, err)
+		now = now.Add(time.Hour)
+	}
+	require.NoError(t, f.Close())
+
+	// the backup of 01:00 is older than 90 minutes at the last rotation
+	kept := backups(t, path)
+	require.Len(t, kept, 2)
+	assert.True(t, strings.HasSuffix(kept[0], ".20250101T020000.000"))
+	assert.True(t, strings.HasSuffix(kept[1], ".20250101T030000.000"))
+}
+
+func TestRotatingFile_RotateFailureReopens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	f, err := OpenRotatingFile(path, 10, 0, 0, 0)
+	require.NoError(t, err)
+	f.now = func() time.Time { return now }
+
+	_, err = f.Write([]byte("aaaaaa\n"))
+	require.NoError(t, err)
+
+	// a non-empty directory in the way of the backup makes the rename fail
+	blocked := path + "." + now.Format(backupTimeFormat)
+	require.NoError(t, os.MkdirAll(filepath.Join(blocked, "dir"), 0755))
+
+	_, err = f.Write([]byte("bbbbbb\n"))
+	require.Error(t, err)
+
+	now = now.Add(time.Second)
+	_, err = f.Write([]byte("cccccc\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "cccccc\n", string(data))
+
+	data, err = os.ReadFile(path + "." + now.Format(backupTimeFormat))
+	require.NoError(t, err)
+	assert.Equal(t, "aaaaaa\n", string(data))
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+)
+
+// ZerologLogger is a Logger that writes through a zerolog.Logger
+// synchronously.
+type ZerologLogger struct {
+	log zerolog.Logger
+}
+
+func NewZerologLogger(log zerolog.Logger) *ZerologLogger {
+	return &ZerologLogger{log: log}
+}
+
+func (l *ZerologLogger) Debug() Event { return &zerologEvent{e: l.log.Debug()} }
+func (l *ZerologLogger) Info() Event  { return &zerologEvent{e: l.log.Info()} }
+func (l *ZerologLogger) Warn() Event  { return &zerologEvent{e: l.log.Warn()} }
+func (l *ZerologLogger) Error() Event { return &zerologEvent{e: l.log.Error()} }
+
+// zerologEvent wraps an event that is nil when its level is disabled, which
+// zerolog treats as a no-op.
+type zerologEvent struct {
+	e *zerolog.Event
+}
+
+func (z *zerologEvent) Ctx(ctx context.Context) Event {
+	for _, f := range contextFields(ctx) {
+		z.e = z.e.Str(f.key, f.value)
+	}
+	return z
+}
+
+func (z *zerologEvent) Err(err error) Event {
+	if err != nil {
+		z.e = z.e.Err(err)
+	}
+	return z
+}
+
+func (z *zerologEvent) Any(key string, value any) Event {
+	z.e = z.e.Interface(key, value)
+	return z
+}
+
+func (z *zerologEvent) Str(key, value string) Event {
+	z.e = z.e.Str(key, value)
+	return z
+}
+
+func (z *zerologEvent) Msg(msg string) {
+	z.e.Msg(msg)
+}
+
+// ZerologLevel returns the zerolog level matching l.
+func ZerologLevel(l Level) zerolog.Level {
+	switch l {
+	case DebugLevel:
+		return zerolog.DebugLevel
+	case WarnLevel:
+		return zerolog.WarnLevel
+	case ErrorLevel:
+		return zerolog.ErrorLevel
+	default:
+		return zerolog.InfoLevel
+	}
+}
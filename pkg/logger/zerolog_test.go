@@ -0,0 +1,33 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ilam072/event-calendar/pkg/logger"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestZerologLogger(t *testing.T) {
+	var out bytes.Buffer
+	l := logger.NewZerologLogger(zerolog.New(&out).Level(logger.ZerologLevel(logger.InfoLevel)))
+
+	ctx := logger.NewContext(context.Background(), "request_id", "req-1")
+	l.Debug().Ctx(ctx).Msg("hidden")
+	l.Error().Ctx(ctx).Err(errors.New("boom")).Str("op", "test").Any("attempt", 2).Msg("failed")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, map[string]any{
+		"level":      "error",
+		"request_id": "req-1",
+		"error":      "boom",
+		"op":         "test",
+		"attempt":    float64(2),
+		"message":    "failed",
+	}, entry)
+}
@@ -0,0 +1,308 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"io/fs"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// lockID is the key of the advisory lock that keeps replicas starting at the
+// same time from applying migrations concurrently.
+const lockID = 7_286_351_902
+
+// The table has the layout used by golang-migrate, so databases that were
+// migrated with the migrate CLI keep their version.
+const createTableQuery = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT NOT NULL PRIMARY KEY,
+		dirty BOOLEAN NOT NULL
+	);
+`
+
+// hasTablesQuery tells whether the schema holds anything besides
+// schema_migrations.
+const hasTablesQuery = `
+	SELECT EXISTS (
+		SELECT 1 FROM information_schema.tables
+		WHERE table_schema = current_schema() AND table_name <> 'schema_migrations'
+	);
+`
+
+var (
+	ErrDirty      = errors.New("database is dirty, fix the failed migration by hand and run migrate force <version>")
+	ErrNoBaseline = errors.New("database has tables but no schema version, record the version it is at with migrate force <version>")
+)
+
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Migration
+	Applied bool
+}
+
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+// New reads the migrations from fsys with Load. Every migration needs both an
+// up and a down file.
+func New(pool *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+// Load reads the migrations named <version>_<name>.(up|down).sql from the
+// root of fsys, ordered by version.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q: %w", entry.Name(), err)
+		}
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %q: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[uint(version)]
+		if !ok {
+			m = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migrations %d_%s and %d_%s share a version", version, m.Name, version, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up applies the pending migrations and returns them. A database without a
+// recorded version must be empty: one that was migrated by hand has to be
+// baselined with Force first, or the first migration would be run again.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn, current uint) error {
+		if current == 0 {
+			var hasTables bool
+			if err := conn.QueryRow(ctx, hasTablesQuery).Scan(&hasTables); err != nil {
+				return fmt.Errorf("failed to inspect schema: %w", err)
+			}
+			if hasTables {
+				return ErrNoBaseline
+			}
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version <= current {
+				continue
+			}
+
+			if err := apply(ctx, conn, migration.Up, migration.Version); err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts the last steps applied migrations and returns them.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn, current uint) error {
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if migration.Version > current {
+				continue
+			}
+
+			var previous uint
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+
+			if err := apply(ctx, conn, migration.Down, previous); err != nil {
+				return fmt.Errorf("failed to revert migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Force records version as the current one without running any migration
+// and clears the dirty flag. It baselines a database that was migrated by
+// hand and recovers from a failed migration once it has been fixed. Zero
+// marks the database as not migrated at all.
+func (m *Migrator) Force(ctx context.Context, version uint) error {
+	if version != 0 && !slices.ContainsFunc(m.migrations, func(migration Migration) bool { return migration.Version == version }) {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.locked(ctx, func(conn *pgxpool.Conn) error {
+		tx, err := conn.Begin(ctx)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = tx.Rollback(ctx)
+		}()
+
+		if err = setVersion(ctx, tx, version); err != nil {
+			return fmt.Errorf("failed to set schema version: %w", err)
+		}
+
+		return tx.Commit(ctx)
+	})
+}
+
+// Status lists the known migrations and whether they have been applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+
+	err := m.withLock(ctx, func(_ *pgxpool.Conn, current uint) error {
+		for _, migration := range m.migrations {
+			statuses = append(statuses, Status{Migration: migration, Applied: migration.Version <= current})
+		}
+		return nil
+	})
+
+	return statuses, err
+}
+
+// withLock runs fn with the current version while holding the advisory lock.
+// Session locks belong to a connection, so fn has to use conn.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn, current uint) error) error {
+	return m.locked(ctx, func(conn *pgxpool.Conn) error {
+		var version int64
+		var dirty bool
+		err := conn.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1;`).Scan(&version, &dirty)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("failed to get schema version: %w", err)
+		}
+		if dirty {
+			return fmt.Errorf("version %d: %w", version, ErrDirty)
+		}
+
+		return fn(conn, uint(version))
+	})
+}
+
+// locked runs fn while holding the advisory lock, once schema_migrations
+// exists.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err = conn.Exec(ctx, `SELECT pg_advisory_lock($1);`, lockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		_, _ = conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1);`, lockID)
+	}()
+
+	if _, err = conn.Exec(ctx, createTableQuery); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// apply runs a migration and records the resulting version in the same
+// transaction, so that a failed migration leaves no trace.
+func apply(ctx context.Context, conn *pgxpool.Conn, sql string, version uint) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if _, err = tx.Exec(ctx, sql); err != nil {
+		return err
+	}
+
+	if err = setVersion(ctx, tx, version); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// setVersion replaces the recorded version. Zero leaves no row, like a
+// database that was never migrated.
+func setVersion(ctx context.Context, tx pgx.Tx, version uint) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations;`); err != nil {
+		return err
+	}
+
+	if version > 0 {
+		if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, false);`, int64(version)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package migrate_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/ilam072/event-calendar/migrations"
+	"github.com/ilam072/event-calendar/pkg/migrate"
+)
+
+func TestLoad_Embedded(t *testing.T) {
+	loaded, err := migrate.Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(loaded) == 0 || loaded[0].Version != 1 || loaded[0].Name != "init" {
+		t.Fatalf("expected 01_init first, got %+v", loaded)
+	}
+
+	for i := 1; i < len(loaded); i++ {
+		if loaded[i].Version <= loaded[i-1].Version {
+			t.Fatalf("migrations out of order: %d after %d", loaded[i].Version, loaded[i-1].Version)
+		}
+	}
+}
+
+func TestLoad_OrdersByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"10_b.up.sql":   {Data: []byte("up 10")},
+		"10_b.down.sql": {Data: []byte("down 10")},
+		"2_a.up.sql":    {Data: []byte("up 2")},
+		"2_a.down.sql":  {Data: []byte("down 2")},
+		"README.md":     {Data: []byte("ignored")},
+	}
+
+	loaded, err := migrate.Load(fsys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(loaded) != 2 || loaded[0].Version != 2 || loaded[1].Version != 10 {
+		t.Fatalf("unexpected migrations %+v", loaded)
+	}
+	if loaded[1].Up != "up 10" || loaded[1].Down != "down 10" {
+		t.Fatalf("unexpected contents %+v", loaded[1])
+	}
+}
+
+func TestLoad_MissingDown(t *testing.T) {
+	fsys := fstest.MapFS{
+		"01_a.up.sql": {Data: []byte("up")},
+	}
+
+	if _, err := migrate.Load(fsys); err == nil {
+		t.Fatalf("expected an error for a migration without a down file")
+	}
+}
+
+func TestLoad_DuplicateVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"01_a.up.sql":   {Data: []byte("up")},
+		"01_a.down.sql": {Data: []byte("down")},
+		"01_b.up.sql":   {Data: []byte("up")},
+		"01_b.down.sql": {Data: []byte("down")},
+	}
+
+	if _, err := migrate.Load(fsys); err == nil {
+		t.Fatalf("expected an error for migrations sharing a version")
+	}
+}
@@ -0,0 +1,228 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/ilam072/event-calendar/pkg/oidc"
+)
+
+const (
+	clientID     = "event-calendar"
+	clientSecret = "client-secret"
+	redirectURL  = "http://localhost:8080/auth/oidc/callback"
+	authCode     = "auth-code"
+)
+
+// mockIdP is a minimal OpenID provider: discovery, JWKS and a token endpoint
+// that enforces PKCE against the challenge captured from the authorize URL.
+type mockIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu            sync.Mutex
+	challenge     string
+	nonce         string
+	emailVerified any
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	idp := &mockIdP{key: key, emailVerified: true}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("/keys", idp.keys)
+	mux.HandleFunc("/token", idp.token)
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+func (idp *mockIdP) authorize(t *testing.T, authURL string) {
+	t.Helper()
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("invalid auth url: %v", err)
+	}
+
+	q := u.Query()
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		t.Fatalf("auth url has no PKCE challenge: %s", authURL)
+	}
+
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	idp.challenge = q.Get("code_challenge")
+	idp.nonce = q.Get("nonce")
+}
+
+func (idp *mockIdP) discovery(w http.ResponseWriter, _ *http.Request) {
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"issuer":                                idp.server.URL,
+		"authorization_endpoint":                idp.server.URL + "/authorize",
+		"token_endpoint":                        idp.server.URL + "/token",
+		"jwks_uri":                              idp.server.URL + "/keys",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (idp *mockIdP) keys(w http.ResponseWriter, _ *http.Request) {
+	pub := idp.key.PublicKey
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test",
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func (idp *mockIdP) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "bad form", http.StatusBadRequest)
+		return
+	}
+
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != idp.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	if r.PostForm.Get("code") != authCode {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":            idp.server.URL,
+		"sub":            "idp-user-1",
+		"aud":            clientID,
+		"exp":            time.Now().Add(time.Minute).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          idp.nonce,
+		"email":          "user@example.com",
+		"email_verified": idp.emailVerified,
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test"
+	idToken, _ := token.SignedString(idp.key)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     idToken,
+	})
+}
+
+func newClient(t *testing.T, idp *mockIdP) *oidc.Client {
+	t.Helper()
+
+	client, err := oidc.New(context.Background(), oidc.Config{
+		IssuerURL:    idp.server.URL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestClient_Exchange(t *testing.T) {
+	idp := newMockIdP(t)
+	client := newClient(t, idp)
+
+	state, nonce, verifier, err := oidc.NewFlow()
+	if err != nil {
+		t.Fatalf("failed to start flow: %v", err)
+	}
+	idp.authorize(t, client.AuthCodeURL(state, nonce, verifier))
+
+	identity, err := client.Exchange(context.Background(), authCode, nonce, verifier)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if identity.Issuer != idp.server.URL || identity.Subject != "idp-user-1" {
+		t.Fatalf("unexpected identity: %+v", identity)
+	}
+	if identity.Email != "user@example.com" || !identity.EmailVerified {
+		t.Fatalf("unexpected identity: %+v", identity)
+	}
+}
+
+func TestClient_Exchange_EmailVerifiedString(t *testing.T) {
+	idp := newMockIdP(t)
+	idp.emailVerified = "false"
+	client := newClient(t, idp)
+
+	state, nonce, verifier, _ := oidc.NewFlow()
+	idp.authorize(t, client.AuthCodeURL(state, nonce, verifier))
+
+	identity, err := client.Exchange(context.Background(), authCode, nonce, verifier)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if identity.EmailVerified {
+		t.Fatalf("expected unverified email")
+	}
+}
+
+func TestClient_Exchange_WrongVerifier(t *testing.T) {
+	idp := newMockIdP(t)
+	client := newClient(t, idp)
+
+	state, nonce, verifier, _ := oidc.NewFlow()
+	idp.authorize(t, client.AuthCodeURL(state, nonce, verifier))
+
+	_, _, otherVerifier, _ := oidc.NewFlow()
+	if _, err := client.Exchange(context.Background(), authCode, nonce, otherVerifier); err == nil {
+		t.Fatalf("expected error for wrong PKCE verifier")
+	}
+}
+
+func TestClient_Exchange_NonceMismatch(t *testing.T) {
+	idp := newMockIdP(t)
+	client := newClient(t, idp)
+
+	state, nonce, verifier, _ := oidc.NewFlow()
+	idp.authorize(t, client.AuthCodeURL(state, nonce, verifier))
+
+	if _, err := client.Exchange(context.Background(), authCode, "other-nonce", verifier); err == nil {
+		t.Fatalf("expected error for nonce mismatch")
+	}
+}
@@ -0,0 +1,70 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/ilam072/event-calendar/pkg/totp"
+)
+
+// rfcSecret is the SHA1 seed of RFC 6238 appendix B, "12345678901234567890".
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+// The RFC lists 8-digit codes, these are their last 6 digits.
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestGenerateCode_RFC6238(t *testing.T) {
+	for _, v := range rfcVectors {
+		code, err := totp.GenerateCode(rfcSecret, time.Unix(v.unix, 0))
+		if err != nil {
+			t.Fatalf("failed to generate code: %v", err)
+		}
+		if code != v.code {
+			t.Fatalf("code at %d: expected %s, got %s", v.unix, v.code, code)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	for _, v := range rfcVectors {
+		at := time.Unix(v.unix, 0)
+		step := v.unix / 30
+
+		got, ok := totp.Match(v.code, rfcSecret, at)
+		if !ok || got != step {
+			t.Fatalf("code at %d: expected step %d, got %d (ok=%v)", v.unix, step, got, ok)
+		}
+
+		// accepted one step late for clock drift, but not two
+		if got, ok = totp.Match(v.code, rfcSecret, at.Add(30*time.Second)); !ok || got != step {
+			t.Fatalf("code at %d: expected step %d one step later, got %d (ok=%v)", v.unix, step, got, ok)
+		}
+		if _, ok = totp.Match(v.code, rfcSecret, at.Add(60*time.Second)); ok {
+			t.Fatalf("code at %d: accepted two steps later", v.unix)
+		}
+	}
+}
+
+func TestValidate_Malformed(t *testing.T) {
+	at := time.Unix(59, 0)
+
+	for _, code := range []string{"", "28708", "2870820", "abcdef"} {
+		if totp.Validate(code, rfcSecret, at) {
+			t.Fatalf("malformed code %q accepted", code)
+		}
+	}
+	if totp.Validate("287082", "not base32!", at) {
+		t.Fatalf("code accepted with an invalid secret")
+	}
+}
@@ -0,0 +1,162 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"github.com/ilam072/event-calendar/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// collector is an in-process OTLP trace receiver.
+type collector struct {
+	coltracepb.UnimplementedTraceServiceServer
+
+	mu    sync.Mutex
+	spans []*tracepb.Span
+	names []string
+}
+
+func (c *collector) Export(_ context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, rs := range req.ResourceSpans {
+		for _, attr := range rs.Resource.Attributes {
+			if attr.Key == "service.name" {
+				c.names = append(c.names, attr.Value.GetStringValue())
+			}
+		}
+		for _, ss := range rs.ScopeSpans {
+			c.spans = append(c.spans, ss.Spans...)
+		}
+	}
+
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req := &coltracepb.ExportTraceServiceRequest{}
+	if err = proto.Unmarshal(body, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, _ := c.Export(r.Context(), req)
+	out, _ := proto.Marshal(resp)
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(out)
+}
+
+func startHTTP(t *testing.T, c *collector) string {
+	srv := httptest.NewServer(c)
+	t.Cleanup(srv.Close)
+	return srv.URL + "/v1/traces"
+}
+
+func startGRPC(t *testing.T, c *collector) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(srv, c)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	return "http://" + lis.Addr().String()
+}
+
+func TestExporter(t *testing.T) {
+	tests := []struct {
+		protocol string
+		start    func(t *testing.T, c *collector) string
+	}{
+		{protocol: tracing.ProtocolHTTP, start: startHTTP},
+		{protocol: tracing.ProtocolGRPC, start: startGRPC},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.protocol, func(t *testing.T) {
+			c := &collector{}
+			cfg := tracing.Config{
+				ServiceName: "event-calendar-test",
+				Exporter:    tracing.ExporterOTLP,
+				Protocol:    tt.protocol,
+				Endpoint:    tt.start(t, c),
+				SampleRatio: 1,
+			}
+
+			exporter, err := tracing.NewExporter(context.Background(), cfg)
+			require.NoError(t, err)
+
+			provider := tracing.NewProvider(cfg, sdktrace.WithSyncer(exporter))
+			tracer := provider.Tracer("test")
+
+			ctx, parent := tracer.Start(context.Background(), "GET /api/v1/events/:id")
+			_, child := tracer.Start(ctx, "db SELECT")
+			child.End()
+			parent.End()
+
+			require.NoError(t, provider.Shutdown(context.Background()))
+
+			c.mu.Lock()
+			defer c.mu.Unlock()
+
+			require.Len(t, c.spans, 2)
+			assert.Equal(t, "db SELECT", c.spans[0].Name)
+			assert.Equal(t, "GET /api/v1/events/:id", c.spans[1].Name)
+			assert.Equal(t, c.spans[1].SpanId, c.spans[0].ParentSpanId)
+			assert.Equal(t, c.spans[1].TraceId, c.spans[0].TraceId)
+			assert.Contains(t, c.names, "event-calendar-test")
+		})
+	}
+}
+
+func TestNewExporter_Unknown(t *testing.T) {
+	_, err := tracing.NewExporter(context.Background(), tracing.Config{Exporter: "zipkin"})
+	assert.Error(t, err)
+
+	_, err = tracing.NewExporter(context.Background(), tracing.Config{Exporter: tracing.ExporterOTLP, Protocol: "thrift"})
+	assert.Error(t, err)
+}
+
+func TestSetup_None(t *testing.T) {
+	shutdown, err := tracing.Setup(context.Background(), tracing.Config{Exporter: tracing.ExporterNone})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestEnd(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	_, ok := tracer.Start(context.Background(), "ok")
+	tracing.End(ok, nil)
+	_, failed := tracer.Start(context.Background(), "failed")
+	tracing.End(failed, errors.New("smtp down"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "smtp down", spans[1].Status().Description)
+}